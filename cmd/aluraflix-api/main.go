@@ -15,6 +15,10 @@
 // @in header
 // @name Authorization
 
+// @securityDefinitions.apikey APIKeyHeader
+// @in header
+// @name X-API-Key
+
 package main
 
 import (
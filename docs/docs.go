@@ -2,16 +2,9 @@
 // This file was generated by swaggo/swag
 package docs
 
-import (
-	"bytes"
-	"encoding/json"
-	"strings"
-	"text/template"
+import "github.com/swaggo/swag"
 
-	"github.com/swaggo/swag"
-)
-
-var doc = `{
+const docTemplate_swagger = `{
     "schemes": {{ marshal .Schemes }},
     "swagger": "2.0",
     "info": {
@@ -30,134 +23,46 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/categories": {
+        "/api-keys": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Get details of all categories",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get every API key, revoked or not, newest first. Keys are only identified by their prefix: their secret is never shown again after they were created.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
-                ],
-                "summary": "Get details of all categories",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Search by name",
-                        "name": "search",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page size",
-                        "name": "pageSize",
-                        "in": "query"
-                    }
+                    "api-keys"
                 ],
+                "summary": "Get all API keys",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Category"
+                                "$ref": "#/definitions/models.APIKey"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/resources.ErrorMessage"
-                        }
-                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": ""
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/resources.ErrorMessage"
-                        }
-                    }
-                }
-            },
-            "put": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
-                ],
-                "description": "Update a category by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "categories"
-                ],
-                "summary": "Update a category by ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "New category values",
-                        "name": "category",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.InsertCategory"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.Category"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                    "403": {
+                        "description": "Requires manage:api-keys",
                         "schema": {
                             "$ref": "#/definitions/resources.ErrorMessage"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": ""
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -170,9 +75,12 @@ var doc = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Create a new Category",
+                "description": "Create an API key granting the scopes, which the token creating it must grant too, until expiresAt (90 days without one). The key is sent in the X-API-Key header in place of a token, and is only shown in this response.",
                 "consumes": [
                     "application/json"
                 ],
@@ -180,17 +88,17 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "api-keys"
                 ],
-                "summary": "Create a new Category",
+                "summary": "Create a new API key",
                 "parameters": [
                     {
-                        "description": "New category",
-                        "name": "category",
+                        "description": "New API key",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.InsertCategory"
+                            "$ref": "#/definitions/dto.InsertAPIKey"
                         }
                     }
                 ],
@@ -198,7 +106,7 @@ var doc = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/resources.CreatedAPIKey"
                         }
                     },
                     "400": {
@@ -213,6 +121,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires manage:api-keys and the scopes granted",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -220,28 +134,30 @@ var doc = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api-keys/{id}": {
             "delete": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Delete a category by ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revoke an API key, which is refused from then on. Revoking a key already revoked leaves it as it was.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "api-keys"
                 ],
-                "summary": "Delete a category by ID",
+                "summary": "Revoke an API key by ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Category ID",
+                        "type": "string",
+                        "description": "API key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -249,12 +165,9 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": ""
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/resources.ErrorMessage"
+                            "$ref": "#/definitions/models.APIKey"
                         }
                     },
                     "401": {
@@ -263,6 +176,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires manage:api-keys",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
@@ -275,14 +194,17 @@ var doc = `{
                 }
             }
         },
-        "/categories/{id}": {
+        "/audit": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Get details of a category by ID",
+                "description": "Get a page of the audit entries of every video and category write, newest first. Entries record creations, updates, deletes and restores, including the videos a category delete removes or moves to FREE.",
                 "consumes": [
                     "application/json"
                 ],
@@ -290,23 +212,91 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "categories"
+                    "audit"
                 ],
-                "summary": "Get details of a category by ID",
+                "summary": "Get the audit log",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "videos or categories",
+                        "name": "resource",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the video or category",
+                        "name": "resourceID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subject of the token that made the write",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create, update, delete, restore or publish",
+                        "name": "operation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Written on or after this date (2006-01-02) or RFC 3339 time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Written on or before this date (2006-01-02) or RFC 3339 time",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
                         }
                     },
                     "400": {
@@ -321,6 +311,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires read:audit",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
@@ -333,47 +329,29 @@ var doc = `{
                 }
             }
         },
-        "/categories/{id}/videos": {
+        "/auth/jwks/stats": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Get all videos by category ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get how the cache of the token signing keys fetched from the jwks.json of the issuer is doing: the lookups served fresh or stale, the misses, the refreshes and their errors, and per issuer the number of keys, when they were refreshed and until when they are kept.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
-                ],
-                "summary": "Get all videos by category ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Get the statistics of the signing key cache",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Video"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/resources.ErrorMessage"
+                            "$ref": "#/definitions/jwt.KeyCacheStats"
                         }
                     },
                     "401": {
@@ -382,11 +360,8 @@ var doc = `{
                             "type": "string"
                         }
                     },
-                    "404": {
-                        "description": ""
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Requires read:stats",
                         "schema": {
                             "$ref": "#/definitions/resources.ErrorMessage"
                         }
@@ -394,14 +369,17 @@ var doc = `{
                 }
             }
         },
-        "/videos": {
+        "/categories": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Get details of all videos",
+                "description": "Get a page of categories with the total count. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.",
                 "consumes": [
                     "application/json"
                 ],
@@ -409,9 +387,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
                 ],
-                "summary": "Get details of all videos",
+                "summary": "Get details of all categories",
                 "parameters": [
                     {
                         "type": "string",
@@ -430,15 +408,57 @@ var doc = `{
                         "description": "Page size",
                         "name": "pageSize",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by titulo, cor, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as nextCursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size when paging by cursor",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Video"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Category"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
                             }
                         }
                     },
@@ -454,6 +474,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
@@ -469,9 +495,12 @@ var doc = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Update a video by ID",
+                "description": "Update a category by ID. The FREE category cannot be renamed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -479,24 +508,30 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
                 ],
-                "summary": "Update a video by ID",
+                "summary": "Update a category by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Video ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "New video values",
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New category values",
                         "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.InsertVideo"
+                            "$ref": "#/definitions/dto.InsertCategory"
                         }
                     }
                 ],
@@ -504,7 +539,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Video"
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
                         }
                     },
                     "400": {
@@ -519,9 +560,27 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires write:categories, and to own the category or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -534,9 +593,12 @@ var doc = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Create a new Video",
+                "description": "Create a new Category",
                 "consumes": [
                     "application/json"
                 ],
@@ -544,17 +606,17 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
                 ],
-                "summary": "Create a new Video",
+                "summary": "Create a new Category",
                 "parameters": [
                     {
-                        "description": "New video",
-                        "name": "video",
+                        "description": "New category",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.InsertVideo"
+                            "$ref": "#/definitions/dto.InsertCategory"
                         }
                     }
                 ],
@@ -562,7 +624,13 @@ var doc = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Video"
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
                         }
                     },
                     "400": {
@@ -577,6 +645,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires write:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -589,9 +663,12 @@ var doc = `{
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Delete a video by ID",
+                "description": "Delete a category by ID. Its videos are handled according to CATEGORY_DELETE_POLICY (reject, cascade or reassign), and the FREE category cannot be deleted.",
                 "consumes": [
                     "application/json"
                 ],
@@ -599,16 +676,22 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
                 ],
-                "summary": "Delete a video by ID",
+                "summary": "Delete a category by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Video ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -627,9 +710,27 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires delete:categories, and to own the category or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -639,9 +740,17 @@ var doc = `{
                 }
             }
         },
-        "/videos/free": {
+        "/categories/{id}": {
             "get": {
-                "description": "Get all free videos",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get details of a category by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -649,19 +758,40 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
+                ],
+                "summary": "Get details of a category by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached version",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get all free videos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Video"
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
                             }
                         }
                     },
+                    "304": {
+                        "description": ""
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -674,6 +804,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
@@ -684,40 +820,62 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/videos/{id}": {
-            "get": {
+            },
+            "patch": {
                 "security": [
                     {
                         "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
                     }
                 ],
-                "description": "Get details of a video by ID",
+                "description": "Apply a JSON Merge Patch (RFC 7396) to a category. Only the fields present are validated and changed. The FREE category cannot be renamed.",
                 "consumes": [
-                    "application/json"
+                    "application/merge-patch+json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "videos"
+                    "categories"
                 ],
-                "summary": "Get details of a video by ID",
+                "summary": "Partially update a category by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Video ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchCategory"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Video"
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
                         }
                     },
                     "400": {
@@ -732,9 +890,33 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "Requires write:categories, and to own the category or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "404": {
                         "description": ""
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -743,61 +925,3681 @@ var doc = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.InsertCategory": {
-            "type": "object",
-            "properties": {
-                "cor": {
-                    "type": "string",
-                    "example": "blue"
-                },
-                "titulo": {
-                    "type": "string",
-                    "example": "Example video"
-                }
-            }
         },
-        "dto.InsertVideo": {
-            "type": "object",
-            "properties": {
-                "categoriaID": {
-                    "type": "string",
-                    "example": "000000000000000000000000"
-                },
-                "descricao": {
-                    "type": "string",
-                    "example": "Example description"
-                },
-                "titulo": {
-                    "type": "string",
-                    "example": "Example video"
-                },
-                "url": {
-                    "type": "string",
-                    "example": "https://www.example-url.com"
+        "/categories/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the audit entries of a category, newest first, deleted or not. Each entry holds who made the write, when, and the value of every field it changed before and after it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get the change history of a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
                 }
             }
         },
-        "models.Category": {
-            "type": "object",
-            "properties": {
-                "active": {
-                    "type": "boolean",
-                    "example": true
-                },
-                "cor": {
-                    "type": "string",
-                    "example": "Red"
-                },
+        "/categories/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the versions of a category replaced by its updates, newest first. Each revision is the version it was.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get the revisions of a category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CategoryRevision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Update a category back to the values it had at a revision, with the same validation as any other update. The restore keeps the replaced version as a new revision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Restore a category to one of its revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:categories, and to own the category or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/videos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get all videos by category ID, leaving out the ones not published yet unless the token grants write:videos. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get all videos by category ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as nextCursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size when paging by cursor",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/dev/token": {
+            "post": {
+                "description": "Mint a token of the development issuer for the sub and scopes asked for, valid for expiresIn seconds (up to a day). Without a body it is valid for an hour, for the dev|aluraflix subject, and grants every permission. Its public key is served at /.well-known/jwks.json. Only served when ENV is dev.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "dev"
+                ],
+                "summary": "Mint a development token",
+                "parameters": [
+                    {
+                        "description": "Subject, scopes and lifetime of the token",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DevTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DevToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the profile of the token subject, as read from the claims of their latest token. Requests authenticated with an API key have no user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get the user of the token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/videos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the videos the token subject owns, whatever their publication status unless filtered by status. Takes the filters, sort and pagination of the video listing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "me"
+                ],
+                "summary": "Get the videos of the token subject",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Category ids, repeated or comma separated",
+                        "name": "categoriaID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "URL hosts such as youtube.com, repeated or comma separated",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "false lists the deleted videos instead of the active ones",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as nextCursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size when paging by cursor",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "facets": {
+                                            "$ref": "#/definitions/dto.VideoFacets"
+                                        },
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Video"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the playlists the token may see, in creation order: the public ones and its own, or every one with admin:content. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get all playlists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only the playlists of the user with this ID",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Playlist"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:playlists",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Create an empty playlist owned by the token subject. The visibility is public or private, private when left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Create a new playlist",
+                "parameters": [
+                    {
+                        "description": "New playlist",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InsertPlaylist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:playlists",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get details of a playlist by ID, with its videos in order. A private playlist is only found by its owner and admin:content.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get details of a playlist by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached version",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:playlists",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Update the title, description and visibility of a playlist by ID, keeping its videos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Update a playlist by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New playlist values",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InsertPlaylist"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:playlists, and to own the playlist or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Delete a playlist by ID for good. Its videos are left as they are.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Delete a playlist by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires delete:playlists, and to own the playlist or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/items": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Add an active video to a playlist at a position starting from 1, shifting the videos from there on, or at the end without one. A video is only once in a playlist.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Add a video to a playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Video to add",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InsertPlaylistItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:playlists, and to own the playlist or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/items/{videoID}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Remove a video from a playlist, moving up the videos after it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Remove a video from a playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "videoID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:playlists, and to own the playlist or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Move a video of a playlist to another position starting from 1, shifting the videos in between.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Move a video of a playlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "videoID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New position",
+                        "name": "move",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MovePlaylistItem"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the playlist"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:playlists, and to own the playlist or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Search the titles and descriptions of videos and the titles of categories, ignoring case and accents, and leaving out the videos not published yet unless the token grants write:videos. Each group is ranked from the most relevant, and highlights hold the matching fields with the matched words wrapped in \u003cmark\u003e.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Full-text search over videos and categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum items of each type, 10 by default and 50 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos and read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SearchResults"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Complete the typed prefix with the titles of videos and categories, ignoring case and accents, in alphabetical order. Without a token only the videos of the FREE category are suggested, and the videos not published yet only when the token grants write:videos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Autocomplete titles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Beginning of the title",
+                        "name": "prefix",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum suggestions, 10 by default and 50 at most",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Suggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos and read:categories when authenticated",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Suggestion"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/categories": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the categories in the trash, most recently deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get all deleted categories",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Permanently remove the categories deleted before the trash retention period (TRASH_RETENTION_DAYS). Categories still referenced by videos are kept. Only admin:content purges the categories of every user, others purge their own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge deleted categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/resources.PurgeResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires delete:categories",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/categories/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Restore a deleted category by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted category by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the category"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:categories, and to own the category or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/videos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the videos in the trash, most recently deleted first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get all deleted videos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Permanently remove the videos deleted before the trash retention period (TRASH_RETENTION_DAYS). Only admin:content purges the videos of every user, others purge their own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge deleted videos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/resources.PurgeResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires delete:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/videos/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Restore a deleted video by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted video by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:videos, and to own the video or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the users the API has seen a token of, most recently created first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:users",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the profile of a user by the subject of their tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, the subject of their tokens",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:users",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/videos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the videos a user owns. Without write:videos only published videos are listed. Takes the filters, sort and pagination of the video listing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the videos of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID, the subject of their tokens",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Category ids, repeated or comma separated",
+                        "name": "categoriaID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "URL hosts such as youtube.com, repeated or comma separated",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "false lists the deleted videos instead of the active ones",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as nextCursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size when paging by cursor",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "facets": {
+                                            "$ref": "#/definitions/dto.VideoFacets"
+                                        },
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Video"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:users",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of videos with the total count and the facet counts per category and per URL host. Each facet applies every filter but its own. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get details of all videos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Category ids, repeated or comma separated",
+                        "name": "categoriaID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "URL hosts such as youtube.com, repeated or comma separated",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated. Without write:videos only published videos are listed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "false lists the deleted videos instead of the active ones",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or after this date (2006-01-02) or RFC 3339 time",
+                        "name": "createdFrom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created on or before this date (2006-01-02) or RFC 3339 time",
+                        "name": "createdTo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as nextCursor by the previous page",
+                        "name": "after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size when paging by cursor",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "facets": {
+                                            "$ref": "#/definitions/dto.VideoFacets"
+                                        },
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Video"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Update a video by ID. Without categoriaID the video goes to the FREE category. The status and publishAt are set as on creation, except that leaving both out keeps the ones stored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Update a video by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "New video values",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InsertVideo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:videos, and to own the video or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Create a new Video. Without categoriaID the video goes to the FREE category. The status is draft, scheduled, published or unpublished; without one the video is scheduled when it has a publishAt and published otherwise. Only scheduled videos take a publishAt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Create a new Video",
+                "parameters": [
+                    {
+                        "description": "New video",
+                        "name": "video",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.InsertVideo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Delete a video by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Delete a video by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires delete:videos, and to own the video or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/free": {
+            "get": {
+                "description": "Get all the published videos of the FREE category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get all free videos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get details of a video by ID. Only tokens granting write:videos find the videos not published yet.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get details of a video by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached version",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Apply a JSON Merge Patch (RFC 7396) to a video. Only the fields present are validated and changed. A null categoriaID moves the video to the FREE category. A status or publishAt sets the publication as a whole, so the one left out is reset.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Partially update a video by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "video",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PatchVideo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:videos, and to own the video or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get a page of the audit entries of a video, newest first, deleted or not. Each entry holds who made the write, when, and the value of every field it changed before and after it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get the change history of a video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/vnd.aluraflix.array+json for the bare array",
+                        "name": "Accept",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/resources.Page"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.AuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Links to the first, previous, next and last pages"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/revisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Get the versions of a video replaced by its updates, newest first. Each revision is the version it was.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get the revisions of a video",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.VideoRevision"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires read:videos",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}/revisions/{rev}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "APIKeyHeader": []
+                    }
+                ],
+                "description": "Update a video back to the values it had at a revision, with the same validation as any other update. The restore keeps the replaced version as a new revision.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Restore a video to one of its revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version being changed",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Video"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the video"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Requires write:videos, and to own the video or admin:content",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "404": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/resources.ErrorMessage"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.CategoryHit": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.Category"
+                },
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number",
+                    "example": 1.5
+                }
+            }
+        },
+        "dto.DevToken": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "dto.DevTokenRequest": {
+            "type": "object",
+            "properties": {
+                "expiresIn": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read:videos",
+                        "write:videos"
+                    ]
+                },
+                "sub": {
+                    "type": "string",
+                    "example": "dev|aluraflix"
+                }
+            }
+        },
+        "dto.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "value": {
+                    "type": "string",
+                    "example": "youtube.com"
+                }
+            }
+        },
+        "dto.InsertAPIKey": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2021-11-12T04:46:49Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nightly export"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read:videos",
+                        "read:categories"
+                    ]
+                }
+            }
+        },
+        "dto.InsertCategory": {
+            "type": "object",
+            "properties": {
+                "cor": {
+                    "type": "string",
+                    "example": "blue"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example video"
+                }
+            }
+        },
+        "dto.InsertPlaylist": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "type": "string",
+                    "example": "Example description"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example playlist"
+                },
+                "visibility": {
+                    "type": "string",
+                    "example": "private"
+                }
+            }
+        },
+        "dto.InsertPlaylistItem": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "videoID": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                }
+            }
+        },
+        "dto.InsertVideo": {
+            "type": "object",
+            "properties": {
+                "categoriaID": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "descricao": {
+                    "type": "string",
+                    "example": "Example description"
+                },
+                "publishAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "scheduled"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example video"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.example-url.com"
+                }
+            }
+        },
+        "dto.MovePlaylistItem": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.PatchCategory": {
+            "type": "object",
+            "properties": {
+                "cor": {
+                    "type": "string",
+                    "example": "blue"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example category"
+                }
+            }
+        },
+        "dto.PatchVideo": {
+            "type": "object",
+            "properties": {
+                "categoriaID": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "descricao": {
+                    "type": "string",
+                    "example": "Example description"
+                },
+                "publishAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "scheduled"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example video"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://www.example-url.com"
+                }
+            }
+        },
+        "dto.SearchResults": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.CategoryHit"
+                    }
+                },
+                "query": {
+                    "type": "string",
+                    "example": "programacao"
+                },
+                "videos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.VideoHit"
+                    }
+                }
+            }
+        },
+        "dto.Suggestion": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example video"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "video",
+                        "category"
+                    ],
+                    "example": "video"
+                }
+            }
+        },
+        "dto.VideoFacets": {
+            "type": "object",
+            "properties": {
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FacetCount"
+                    }
+                },
+                "hosts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.FacetCount"
+                    }
+                }
+            }
+        },
+        "dto.VideoHit": {
+            "type": "object",
+            "properties": {
+                "highlights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "video": {
+                    "$ref": "#/definitions/models.Video"
+                }
+            }
+        },
+        "jwt.IssuerStats": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "keys": {
+                    "type": "integer"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "refreshedAt": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "jwt.KeyCacheStats": {
+            "type": "object",
+            "properties": {
+                "hits": {
+                    "type": "integer"
+                },
+                "issuers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/jwt.IssuerStats"
+                    }
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "refreshErrors": {
+                    "type": "integer"
+                },
+                "refreshes": {
+                    "type": "integer"
+                },
+                "staleHits": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2021-11-12T04:46:49Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "lastUsedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nightly export"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "afk_Xq3v"
+                },
+                "revokedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "revokedBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read:videos",
+                        "read:categories"
+                    ]
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "videos"
+                },
+                "resourceID": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "cor": {
+                    "type": "string",
+                    "example": "Red"
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
                 "id": {
                     "type": "string",
                     "example": "000000000000000000000000"
                 },
+                "ownerId": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
                 "titulo": {
                     "type": "string",
                     "example": "Example category"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "updatedBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.CategoryRevision": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.Category"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string",
+                    "example": "titulo"
+                }
+            }
+        },
+        "models.Playlist": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "descricao": {
+                    "type": "string",
+                    "example": "Example description"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistItem"
+                    }
+                },
+                "ownerId": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "titulo": {
+                    "type": "string",
+                    "example": "Example playlist"
+                },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "updatedBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "visibility": {
+                    "type": "string",
+                    "example": "private"
+                }
+            }
+        },
+        "models.PlaylistItem": {
+            "type": "object",
+            "properties": {
+                "addedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "videoID": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "alice@example.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "lastSeenAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Alice Example"
+                },
+                "nickname": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "picture": {
+                    "type": "string",
+                    "example": "https://example.com/alice.png"
                 }
             }
         },
@@ -812,6 +4614,18 @@ var doc = `{
                     "type": "string",
                     "example": "000000000000000000000000"
                 },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "deletedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
                 "descricao": {
                     "type": "string",
                     "example": "Example description"
@@ -820,13 +4634,104 @@ var doc = `{
                     "type": "string",
                     "example": "000000000000000000000000"
                 },
+                "ownerId": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "publishAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "published"
+                },
                 "titulo": {
                     "type": "string",
                     "example": "Example video"
                 },
+                "updatedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "updatedBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
                 "url": {
                     "type": "string",
                     "example": "https://www.example-url.com"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.VideoRevision": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "video": {
+                    "$ref": "#/definitions/models.Video"
+                }
+            }
+        },
+        "resources.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "createdBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2021-11-12T04:46:49Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "000000000000000000000000"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "afk_Xq3vN2yq2a1cQ5fJ0mH9sW8dT7uR6eY4iO3pL2kJ1hG"
+                },
+                "lastUsedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Nightly export"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "afk_Xq3v"
+                },
+                "revokedAt": {
+                    "type": "string",
+                    "example": "2021-08-14T04:46:49Z"
+                },
+                "revokedBy": {
+                    "type": "string",
+                    "example": "auth0|611743c4a8e4b1006a7e5e8c"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "read:videos",
+                        "read:categories"
+                    ]
                 }
             }
         },
@@ -838,9 +4743,46 @@ var doc = `{
                     "example": "example error"
                 }
             }
+        },
+        "resources.Page": {
+            "type": "object",
+            "properties": {
+                "facets": {},
+                "items": {},
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pageSize": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "totalPages": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "resources.PurgeResult": {
+            "type": "object",
+            "properties": {
+                "purged": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "APIKeyHeader": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "ApiKeyAuth": {
             "type": "apiKey",
             "name": "Authorization",
@@ -849,56 +4791,18 @@ var doc = `{
     }
 }`
 
-type swaggerInfo struct {
-	Version     string
-	Host        string
-	BasePath    string
-	Schemes     []string
-	Title       string
-	Description string
-}
-
-// SwaggerInfo holds exported Swagger Info so clients can modify it
-var SwaggerInfo = swaggerInfo{
-	Version:     "1.0",
-	Host:        "cristovao-aluraflix-api.herokuapp.com",
-	BasePath:    "/api/v1",
-	Schemes:     []string{"https", "http"},
-	Title:       "Aluraflix API",
-	Description: "This is a sample service for managing videos and categories",
-}
-
-type s struct{}
-
-func (s *s) ReadDoc() string {
-	sInfo := SwaggerInfo
-	sInfo.Description = strings.Replace(sInfo.Description, "\n", "\\n", -1)
-
-	t, err := template.New("swagger_info").Funcs(template.FuncMap{
-		"marshal": func(v interface{}) string {
-			a, _ := json.Marshal(v)
-			return string(a)
-		},
-		"escape": func(v interface{}) string {
-			// escape tabs
-			str := strings.Replace(v.(string), "\t", "\\t", -1)
-			// replace " with \", and if that results in \\", replace that with \\\"
-			str = strings.Replace(str, "\"", "\\\"", -1)
-			return strings.Replace(str, "\\\\\"", "\\\\\\\"", -1)
-		},
-	}).Parse(doc)
-	if err != nil {
-		return doc
-	}
-
-	var tpl bytes.Buffer
-	if err := t.Execute(&tpl, sInfo); err != nil {
-		return doc
-	}
-
-	return tpl.String()
+// SwaggerInfo_swagger holds exported Swagger Info so clients can modify it
+var SwaggerInfo_swagger = &swag.Spec{
+	Version:          "1.0",
+	Host:             "cristovao-aluraflix-api.herokuapp.com",
+	BasePath:         "/api/v1",
+	Schemes:          []string{"https", "http"},
+	Title:            "Aluraflix API",
+	Description:      "This is a sample service for managing videos and categories",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate_swagger,
 }
 
 func init() {
-	swag.Register("swagger", &s{})
+	swag.Register(SwaggerInfo_swagger.InstanceName(), SwaggerInfo_swagger)
 }
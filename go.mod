@@ -4,12 +4,12 @@ module github.com/cristovaoolegario/aluraflix-api
 go 1.16
 
 require (
-	github.com/auth0/go-jwt-middleware v1.0.1
 	github.com/form3tech-oss/jwt-go v3.2.5+incompatible
 	github.com/google/wire v0.5.0
 	github.com/gorilla/mux v1.8.0
 	github.com/jarcoal/httpmock v1.0.8
 	github.com/joho/godotenv v1.4.0
+	github.com/lib/pq v1.10.4
 	github.com/mailru/easyjson v0.7.7 // indirect
 	github.com/rs/cors v1.8.0
 	github.com/stretchr/testify v1.7.0
@@ -18,4 +18,6 @@ require (
 	go.mongodb.org/mongo-driver v1.8.0
 	golang.org/x/net v0.0.0-20211123203042-d83791d6bcd9 // indirect
 	golang.org/x/sys v0.0.0-20211124211545-fe61309f8881 // indirect
+	golang.org/x/text v0.3.7
+	modernc.org/sqlite v1.14.8
 )
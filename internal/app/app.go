@@ -1,8 +1,9 @@
 package app
 
 import (
+	"context"
 	"fmt"
-	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/events"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 	"log"
@@ -10,21 +11,28 @@ import (
 )
 
 type App struct {
-	router   *mux.Router
-	database services.DatabaseService
+	router    *mux.Router
+	storage   Storage
+	bus       *events.Bus
+	scheduler Scheduler
 }
 
-func ProvideApp(router mux.Router, db services.DatabaseService) App {
-	return App{&router, db}
+func ProvideApp(router mux.Router, storage Storage, bus *events.Bus, scheduler Scheduler) App {
+	bus.Subscribe(events.VideoPublished, func(event events.Event) {
+		log.Printf("video %s published", event.ResourceID.Hex())
+	})
+	return App{&router, storage, bus, scheduler}
 }
 
 func (a *App) Run(port, env string) {
+	go a.scheduler.Run(context.Background())
 	fmt.Println("Server running in port:", port)
 	stringedPort := fmt.Sprintf(":%s", port)
 	if env == "dev" {
 		corsWrapper := cors.New(cors.Options{
-			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 			AllowedHeaders: []string{"Content-Type", "Origin", "Accept", "*"},
+			ExposedHeaders: []string{"ETag", "Link"},
 		})
 		log.Fatal(http.ListenAndServe(stringedPort, corsWrapper.Handler(a.router)))
 	} else {
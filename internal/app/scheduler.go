@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/events"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+const defaultSchedulerIntervalSeconds = 60
+
+// Scheduler publishes the scheduled videos once their publishAt has come and
+// announces each of them on the event bus.
+type Scheduler struct {
+	videoService interfaces.IVideoService
+	bus          *events.Bus
+	interval     time.Duration
+}
+
+func ProvideScheduler(storage Storage, bus *events.Bus) Scheduler {
+	return Scheduler{storage.VideoService, bus, GetSchedulerInterval()}
+}
+
+// GetSchedulerInterval returns how often the scheduler runs, configured in
+// seconds through SCHEDULER_INTERVAL_SECONDS.
+func GetSchedulerInterval() time.Duration {
+	seconds := defaultSchedulerIntervalSeconds
+	if n, err := strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL_SECONDS")); err == nil && n > 0 {
+		seconds = n
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// Run publishes the due videos right away and then at every interval, until
+// ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.tick(domain.SystemContext(ctx, domain.SchedulerActor), time.Now()); err != nil {
+			log.Printf("could not publish the scheduled videos: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick publishes the videos due by now on behalf of the principal of ctx,
+// announcing even those published before a later one failed.
+func (s *Scheduler) tick(ctx context.Context, now time.Time) error {
+	published, err := s.videoService.PublishDue(ctx, now)
+	for _, video := range published {
+		s.bus.Publish(events.Event{
+			Name:       events.VideoPublished,
+			Resource:   models.VideoResource,
+			ResourceID: video.ID,
+			At:         video.UpdatedAt,
+			Payload:    video,
+		})
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/events"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	t.Run("tick Should publish the due videos and announce each of them", func(t *testing.T) {
+		storage := provideStorage(MemoryDriver)
+		bus := events.ProvideBus()
+		scheduler := ProvideScheduler(storage, bus)
+		var announced []events.Event
+		bus.Subscribe(events.VideoPublished, func(event events.Event) { announced = append(announced, event) })
+		now := time.Now()
+		due, later := now.Add(-time.Minute), now.Add(time.Hour)
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.PublishAt = &due
+		dueVideo, _ := storage.VideoService.Create(context.TODO(), insertVideo)
+		insertVideo.PublishAt = &later
+		_, _ = storage.VideoService.Create(context.TODO(), insertVideo)
+
+		assert.Nil(t, scheduler.tick(domain.SystemContext(context.TODO(), domain.SchedulerActor), now))
+
+		assert.Equal(t, 1, len(announced))
+		assert.Equal(t, dueVideo.ID, announced[0].ResourceID)
+		assert.Equal(t, models.VideoResource, announced[0].Resource)
+		assert.Equal(t, models.PublishedStatus, announced[0].Payload.(models.Video).Status)
+		stored, _ := storage.VideoService.GetByID(dueVideo.ID)
+		assert.Equal(t, models.PublishedStatus, stored.Status)
+		assert.Equal(t, domain.SchedulerActor, stored.UpdatedBy)
+	})
+
+	t.Run("GetSchedulerInterval Should read SCHEDULER_INTERVAL_SECONDS", func(t *testing.T) {
+		os.Setenv("SCHEDULER_INTERVAL_SECONDS", "5")
+		defer os.Unsetenv("SCHEDULER_INTERVAL_SECONDS")
+
+		assert.Equal(t, 5*time.Second, GetSchedulerInterval())
+	})
+
+	t.Run("GetSchedulerInterval Should default to a minute When the setting is invalid", func(t *testing.T) {
+		os.Setenv("SCHEDULER_INTERVAL_SECONDS", "0")
+		defer os.Unsetenv("SCHEDULER_INTERVAL_SECONDS")
+
+		assert.Equal(t, time.Minute, GetSchedulerInterval())
+	})
+}
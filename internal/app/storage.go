@@ -0,0 +1,67 @@
+package app
+
+import (
+	"os"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
+	memory "github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/memory/db/services"
+	relational "github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/sql/db/services"
+)
+
+const (
+	MongoDriver    = "mongo"
+	MemoryDriver   = "memory"
+	SQLiteDriver   = relational.SQLiteDriver
+	PostgresDriver = relational.PostgresDriver
+)
+
+// Storage groups the service implementations of the backend selected by APP_DB_DRIVER
+type Storage struct {
+	CategoryService interfaces.ICategoryService
+	VideoService    interfaces.IVideoService
+	SearchService   interfaces.ISearchService
+	AuditService    interfaces.IAuditService
+	APIKeyService   interfaces.IAPIKeyService
+	UserService     interfaces.IUserService
+	PlaylistService interfaces.IPlaylistService
+}
+
+func ProvideStorage() Storage {
+	return provideStorage(os.Getenv("APP_DB_DRIVER"))
+}
+
+func provideStorage(driver string) Storage {
+	switch driver {
+	case MemoryDriver:
+		database := memory.ProvideDatabaseService()
+		categoryService := memory.ProvideCategoryService(database)
+		videoService := memory.ProvideVideoService(categoryService, database)
+		searchService := memory.ProvideSearchService(database)
+		auditService := memory.ProvideAuditService(database)
+		apiKeyService := memory.ProvideAPIKeyService(database)
+		userService := memory.ProvideUserService(database)
+		playlistService := memory.ProvidePlaylistService(videoService, database)
+		return Storage{&categoryService, &videoService, &searchService, &auditService, &apiKeyService, &userService, &playlistService}
+	case SQLiteDriver, PostgresDriver:
+		database := relational.ProvideDatabaseService(driver)
+		categoryService := relational.ProvideCategoryService(database)
+		videoService := relational.ProvideVideoService(categoryService, database)
+		searchService := relational.ProvideSearchService(database)
+		auditService := relational.ProvideAuditService(database)
+		apiKeyService := relational.ProvideAPIKeyService(database)
+		userService := relational.ProvideUserService(database)
+		playlistService := relational.ProvidePlaylistService(videoService, database)
+		return Storage{&categoryService, &videoService, &searchService, &auditService, &apiKeyService, &userService, &playlistService}
+	default:
+		database := services.ProvideDatabaseService()
+		categoryService := services.ProvideCategoryService(database)
+		videoService := services.ProvideVideoService(categoryService, database)
+		searchService := services.ProvideSearchService(database)
+		auditService := services.ProvideAuditService(database)
+		apiKeyService := services.ProvideAPIKeyService(database)
+		userService := services.ProvideUserService(database)
+		playlistService := services.ProvidePlaylistService(videoService, database)
+		return Storage{&categoryService, &videoService, &searchService, &auditService, &apiKeyService, &userService, &playlistService}
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	memory "github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/memory/db/services"
+	relational "github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/sql/db/services"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProvideStorage(t *testing.T) {
+	t.Run("Should provide the in-memory services when driver is memory", func(t *testing.T) {
+		storage := provideStorage(MemoryDriver)
+
+		assert.IsType(t, &memory.CategoryService{}, storage.CategoryService)
+		assert.IsType(t, &memory.VideoService{}, storage.VideoService)
+		assert.IsType(t, &memory.SearchService{}, storage.SearchService)
+		assert.IsType(t, &memory.APIKeyService{}, storage.APIKeyService)
+		assert.IsType(t, &memory.UserService{}, storage.UserService)
+		assert.IsType(t, &memory.PlaylistService{}, storage.PlaylistService)
+	})
+
+	t.Run("Should provide the sql services when driver is sqlite", func(t *testing.T) {
+		os.Setenv("APP_DB_NAME", "unit_test")
+		os.Setenv("ENV", "test")
+		defer os.Remove("unit_test.db")
+		storage := provideStorage(SQLiteDriver)
+
+		assert.IsType(t, &relational.CategoryService{}, storage.CategoryService)
+		assert.IsType(t, &relational.VideoService{}, storage.VideoService)
+		assert.IsType(t, &relational.SearchService{}, storage.SearchService)
+		assert.IsType(t, &relational.APIKeyService{}, storage.APIKeyService)
+		assert.IsType(t, &relational.UserService{}, storage.UserService)
+		assert.IsType(t, &relational.PlaylistService{}, storage.PlaylistService)
+	})
+
+	t.Run("Should share the same data between the in-memory services", func(t *testing.T) {
+		storage := provideStorage(MemoryDriver)
+
+		video, err := storage.VideoService.Create(context.TODO(), mocked_data.GetValidInsertVideoDto())
+		assert.Nil(t, err)
+
+		videos, err := storage.CategoryService.GetVideosByCategoryId(video.CategoryID, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(videos))
+		assert.Equal(t, video.ID, videos[0].ID)
+	})
+}
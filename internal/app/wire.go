@@ -1,18 +1,28 @@
 package app
 
 import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/events"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest/resources"
-	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
 	"github.com/google/wire"
 )
 
 func initApp() App {
-	wire.Build(services.ProvideDatabaseService,
-		services.ProvideCategoryService,
-		services.ProvideVideoService,
+	wire.Build(ProvideStorage,
+		wire.FieldsOf(new(Storage), "CategoryService", "VideoService", "SearchService", "AuditService", "APIKeyService", "UserService",
+			"PlaylistService"),
 		resources.ProvideCategoryRouter,
 		resources.ProvideVideoRouter,
-		rest.ProvideRouter, ProvideApp)
+		resources.ProvideSearchRouter,
+		resources.ProvideAuditRouter,
+		resources.ProvideAPIKeyRouter,
+		resources.ProvideUserRouter,
+		resources.ProvidePlaylistRouter,
+		jwt.ProvideDevIssuer,
+		resources.ProvideDevRouter,
+		rest.ProvideRouter,
+		events.ProvideBus,
+		ProvideScheduler, ProvideApp)
 	return App{}
 }
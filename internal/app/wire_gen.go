@@ -7,20 +7,35 @@
 package app
 
 import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/events"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest/resources"
-	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
 )
 
 // Injectors from wire.go:
 
 func InitApp() App {
-	databaseService := services.ProvideDatabaseService()
-	categoryService := services.ProvideCategoryService(databaseService)
-	videoService := services.ProvideVideoService(categoryService, databaseService)
-	videoRouter := resources.ProvideVideoRouter(videoService)
-	categoryRouter := resources.ProvideCategoryRouter(categoryService)
-	router := rest.ProvideRouter(videoRouter, categoryRouter)
-	app := ProvideApp(router, databaseService)
+	storage := ProvideStorage()
+	iVideoService := storage.VideoService
+	videoRouter := resources.ProvideVideoRouter(iVideoService)
+	iCategoryService := storage.CategoryService
+	categoryRouter := resources.ProvideCategoryRouter(iCategoryService)
+	iSearchService := storage.SearchService
+	searchRouter := resources.ProvideSearchRouter(iSearchService)
+	iAuditService := storage.AuditService
+	auditRouter := resources.ProvideAuditRouter(iAuditService)
+	iapiKeyService := storage.APIKeyService
+	apiKeyRouter := resources.ProvideAPIKeyRouter(iapiKeyService)
+	iUserService := storage.UserService
+	userRouter := resources.ProvideUserRouter(iUserService)
+	iPlaylistService := storage.PlaylistService
+	playlistRouter := resources.ProvidePlaylistRouter(iPlaylistService)
+	devIssuer := jwt.ProvideDevIssuer()
+	devRouter := resources.ProvideDevRouter(devIssuer)
+	router := rest.ProvideRouter(videoRouter, categoryRouter, searchRouter, auditRouter, apiKeyRouter, userRouter, playlistRouter, devRouter, devIssuer)
+	bus := events.ProvideBus()
+	scheduler := ProvideScheduler(storage, bus)
+	app := ProvideApp(router, storage, bus, scheduler)
 	return app
 }
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// unaudited are the fields every write changes, which the actor and timestamp
+// of an audit entry already record.
+var unaudited = map[string]bool{"version": true, "createdAt": true, "updatedAt": true, "createdBy": true, "updatedBy": true}
+
+// SchedulerActor is the author of the writes made by the background
+// scheduler rather than on behalf of a request.
+const SchedulerActor = "scheduler"
+
+// Stamp returns the subject of ctx and the current UTC time, truncated to the
+// milliseconds every backend keeps.
+func Stamp(ctx context.Context) (string, time.Time) {
+	return jwt.Subject(ctx), time.Now().UTC().Truncate(time.Millisecond)
+}
+
+// NewAuditEntry records a write of the resource made by author at the given
+// time. Before is nil for a creation.
+func NewAuditEntry(author string, at time.Time, resource string, id primitive.ObjectID, operation string, before, after interface{}) models.AuditEntry {
+	return models.AuditEntry{
+		ID:         primitive.NewObjectID(),
+		Resource:   resource,
+		ResourceID: id,
+		Operation:  operation,
+		Actor:      author,
+		Timestamp:  at,
+		Changes:    DiffFields(before, after),
+	}
+}
+
+// DiffFields compares the JSON fields of two versions of a document, in field
+// order, leaving out the unaudited ones.
+func DiffFields(before, after interface{}) []models.FieldChange {
+	beforeFields, afterFields := jsonFields(before), jsonFields(after)
+	var names []string
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []models.FieldChange{}
+	for _, name := range names {
+		if !unaudited[name] && !reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			changes = append(changes, models.FieldChange{Field: name, Before: beforeFields[name], After: afterFields[name]})
+		}
+	}
+	return changes
+}
+
+func jsonFields(document interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if document == nil {
+		return fields
+	}
+	raw, _ := json.Marshal(document)
+	_ = json.Unmarshal(raw, &fields)
+	return fields
+}
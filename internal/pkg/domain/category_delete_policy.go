@@ -0,0 +1,30 @@
+// Package domain holds the rules every storage backend applies alike to the
+// resources of the API, apart from how each of them stores the resources.
+package domain
+
+import (
+	"os"
+	"strings"
+)
+
+// CategoryDeletePolicy decides what happens to the videos of a category being deleted.
+type CategoryDeletePolicy string
+
+const (
+	// RejectPolicy refuses to delete a category that still has videos.
+	RejectPolicy CategoryDeletePolicy = "reject"
+	// CascadePolicy deletes the videos together with their category.
+	CascadePolicy CategoryDeletePolicy = "cascade"
+	// ReassignPolicy moves the videos to the FREE category.
+	ReassignPolicy CategoryDeletePolicy = "reassign"
+)
+
+// GetCategoryDeletePolicy reads CATEGORY_DELETE_POLICY, falling back to RejectPolicy.
+func GetCategoryDeletePolicy() CategoryDeletePolicy {
+	switch policy := CategoryDeletePolicy(strings.ToLower(os.Getenv("CATEGORY_DELETE_POLICY"))); policy {
+	case CascadePolicy, ReassignPolicy:
+		return policy
+	default:
+		return RejectPolicy
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PrepareCategoryPatch normalises and validates the fields of a category
+// patch, refusing to rename the FREE category.
+func PrepareCategoryPatch(id primitive.ObjectID, category *dto.PatchCategory) error {
+	category.Normalize()
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	if id.IsZero() && category.Titulo != nil && *category.Titulo != models.GetFreeCategory().Titulo {
+		return interfaces.ErrProtectedCategory
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// AdminPermission lets a token change the videos and categories of every
+// user, not only its own.
+const AdminPermission = "admin:content"
+
+// SystemContext returns a context for writes by actor, such as the scheduler,
+// that may change every resource.
+func SystemContext(ctx context.Context, actor string) context.Context {
+	return jwt.WithPrincipal(ctx, actor, []string{AdminPermission})
+}
+
+// Authorize returns ErrNotOwner unless the principal of ctx is owner or has the
+// AdminPermission.
+func Authorize(ctx context.Context, owner string) error {
+	if owner != "" && jwt.Subject(ctx) == owner {
+		return nil
+	}
+	for _, permission := range jwt.Permissions(ctx) {
+		if permission == AdminPermission {
+			return nil
+		}
+	}
+	return interfaces.ErrNotOwner
+}
+
+// AuthorizeVideos returns ErrNotOwner unless ctx may change every video.
+func AuthorizeVideos(ctx context.Context, videos []models.Video) error {
+	for _, video := range videos {
+		if err := Authorize(ctx, video.OwnerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrashOwner returns the owner whose trash ctx may purge, empty for admins.
+func TrashOwner(ctx context.Context) (string, error) {
+	if Authorize(ctx, "") == nil {
+		return "", nil
+	}
+	if subject := jwt.Subject(ctx); subject != "" {
+		return subject, nil
+	}
+	return "", interfaces.ErrNotOwner
+}
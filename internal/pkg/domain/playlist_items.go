@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PreparePlaylist is the normalisation and validation pipeline shared by
+// playlist creation and update.
+func PreparePlaylist(playlist *dto.InsertPlaylist) error {
+	playlist.Normalize()
+	return playlist.Validate()
+}
+
+// PreparePlaylistItem validates a video being added to a playlist and checks
+// it is active, returning interfaces.ErrVideoNotFound otherwise.
+func PreparePlaylistItem(videoService interfaces.IVideoService, item dto.InsertPlaylistItem) error {
+	if err := item.Validate(); err != nil {
+		return err
+	}
+	if _, err := videoService.GetByID(item.VideoID); errors.Is(err, interfaces.ErrNotFound) {
+		return interfaces.ErrVideoNotFound
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// AuthorizePlaylist checks that ctx may change the playlist at the expected
+// version, if any.
+func AuthorizePlaylist(ctx context.Context, playlist models.Playlist, version int64) error {
+	if err := Authorize(ctx, playlist.OwnerID); err != nil {
+		return err
+	}
+	if version != 0 && playlist.Version != version {
+		return interfaces.ErrVersionMismatch
+	}
+	return nil
+}
+
+// VisiblePlaylists returns the filter matching the playlists ctx may see.
+func VisiblePlaylists(ctx context.Context) dto.PlaylistFilter {
+	if !jwt.Authenticated(ctx) {
+		return dto.PlaylistFilter{PublicOnly: true}
+	}
+	if Authorize(ctx, "") == nil {
+		return dto.PlaylistFilter{}
+	}
+	return dto.PlaylistFilter{Viewer: jwt.Subject(ctx)}
+}
+
+// CanView reports whether ctx may see the playlist.
+func CanView(ctx context.Context, playlist models.Playlist) bool {
+	return VisiblePlaylists(ctx).Matches(playlist)
+}
+
+// AddPlaylistItem returns items with the video of item added at its position,
+// or at the end without one.
+func AddPlaylistItem(items []models.PlaylistItem, item dto.InsertPlaylistItem, addedAt time.Time) ([]models.PlaylistItem, error) {
+	if indexOfItem(items, item.VideoID) >= 0 {
+		return nil, interfaces.ErrVideoInPlaylist
+	}
+	position := item.Position
+	if position == 0 {
+		position = len(items) + 1
+	}
+	if position > len(items)+1 {
+		return nil, dto.InvalidPositionError(position)
+	}
+	added := make([]models.PlaylistItem, 0, len(items)+1)
+	added = append(added, items[:position-1]...)
+	added = append(added, models.PlaylistItem{VideoID: item.VideoID, AddedAt: addedAt})
+	return append(added, items[position-1:]...), nil
+}
+
+// RemovePlaylistItem returns items without the video.
+func RemovePlaylistItem(items []models.PlaylistItem, videoID primitive.ObjectID) ([]models.PlaylistItem, error) {
+	index := indexOfItem(items, videoID)
+	if index < 0 {
+		return nil, interfaces.ErrVideoNotInPlaylist
+	}
+	removed := make([]models.PlaylistItem, 0, len(items)-1)
+	removed = append(removed, items[:index]...)
+	return append(removed, items[index+1:]...), nil
+}
+
+// MovePlaylistItem returns items with the video moved to position.
+func MovePlaylistItem(items []models.PlaylistItem, videoID primitive.ObjectID, position int) ([]models.PlaylistItem, error) {
+	index := indexOfItem(items, videoID)
+	if index < 0 {
+		return nil, interfaces.ErrVideoNotInPlaylist
+	}
+	if position < 1 || position > len(items) {
+		return nil, dto.InvalidPositionError(position)
+	}
+	item := items[index]
+	moved, _ := RemovePlaylistItem(items, videoID)
+	return AddPlaylistItem(moved, dto.InsertPlaylistItem{VideoID: videoID, Position: position}, item.AddedAt)
+}
+
+func indexOfItem(items []models.PlaylistItem, videoID primitive.ObjectID) int {
+	for i, item := range items {
+		if item.VideoID == videoID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PrepareVideo is the normalisation and validation pipeline shared by video
+// creation and update. A video without category falls back to the FREE one,
+// and any other category must exist.
+func PrepareVideo(categoryService interfaces.ICategoryService, video *dto.InsertVideo) error {
+	video.Normalize()
+	if err := video.Validate(); err != nil {
+		return err
+	}
+	return resolveCategory(categoryService, &video.CategoryID)
+}
+
+// PrepareVideoUpdate runs PrepareVideo over a full update. Without a status
+// nor a publishAt, the status is left empty, for the update to keep the
+// stored publication rather than publish a draft.
+func PrepareVideoUpdate(categoryService interfaces.ICategoryService, video *dto.InsertVideo) error {
+	keep := strings.TrimSpace(video.Status) == "" && video.PublishAt == nil
+	if err := PrepareVideo(categoryService, video); err != nil {
+		return err
+	}
+	if keep {
+		video.Status = ""
+	}
+	return nil
+}
+
+// PrepareVideoPatch runs the same pipeline over the fields of a patch only.
+func PrepareVideoPatch(categoryService interfaces.ICategoryService, video *dto.PatchVideo) error {
+	video.Normalize()
+	if err := video.Validate(); err != nil {
+		return err
+	}
+	if video.CategoryID == nil {
+		return nil
+	}
+	return resolveCategory(categoryService, video.CategoryID)
+}
+
+func resolveCategory(categoryService interfaces.ICategoryService, categoryID *primitive.ObjectID) error {
+	if categoryID.IsZero() {
+		freeCategory := categoryService.GetFreeCategory()
+		if freeCategory == nil {
+			return errors.New("could not load the free category")
+		}
+		*categoryID = freeCategory.ID
+		return nil
+	}
+	if _, err := categoryService.GetById(*categoryID); errors.Is(err, interfaces.ErrNotFound) {
+		return interfaces.CategoryNotFoundError{ID: *categoryID}
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
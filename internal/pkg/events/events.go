@@ -0,0 +1,56 @@
+// Package events carries what happened to the resources of the API to the
+// parts of the application interested in it, within the process.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Names of the events published by the application.
+const (
+	VideoPublished = "video.published"
+)
+
+// Event tells that something happened to a resource at the given time.
+type Event struct {
+	Name       string             `json:"name"`
+	Resource   string             `json:"resource"`
+	ResourceID primitive.ObjectID `json:"resourceID"`
+	At         time.Time          `json:"at"`
+	Payload    interface{}        `json:"payload,omitempty"`
+}
+
+// Handler is called with every event published under the name it was
+// subscribed to.
+type Handler func(event Event)
+
+// Bus delivers each event to the handlers subscribed to its name, in the
+// order they subscribed. It is safe for concurrent use.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func ProvideBus() *Bus {
+	return &Bus{handlers: map[string][]Handler{}}
+}
+
+// Subscribe calls handler with every event published under name from now on.
+func (bus *Bus) Subscribe(name string, handler Handler) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[name] = append(bus.handlers[name], handler)
+}
+
+// Publish delivers the event to its handlers before returning.
+func (bus *Bus) Publish(event Event) {
+	bus.mu.RLock()
+	handlers := bus.handlers[event.Name]
+	bus.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus(t *testing.T) {
+	t.Run("Publish Should deliver the event to its handlers in the order they subscribed", func(t *testing.T) {
+		bus := ProvideBus()
+		var delivered []string
+		bus.Subscribe(VideoPublished, func(event Event) { delivered = append(delivered, "first "+event.Resource) })
+		bus.Subscribe(VideoPublished, func(event Event) { delivered = append(delivered, "second "+event.Resource) })
+
+		bus.Publish(Event{Name: VideoPublished, Resource: "video"})
+
+		assert.Equal(t, []string{"first video", "second video"}, delivered)
+	})
+
+	t.Run("Publish Should leave out the handlers of other events", func(t *testing.T) {
+		bus := ProvideBus()
+		called := false
+		bus.Subscribe("video.deleted", func(Event) { called = true })
+
+		bus.Publish(Event{Name: VideoPublished})
+
+		assert.False(t, called)
+	})
+}
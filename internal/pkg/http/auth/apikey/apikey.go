@@ -0,0 +1,42 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	// Header is the request header carrying an API key.
+	Header = "X-API-Key"
+	// keyPrefix starts every key, so that leaked keys are easy to recognise.
+	keyPrefix    = "afk_"
+	keyBytes     = 32
+	prefixLength = len(keyPrefix) + 4
+)
+
+// Generate returns a new random API key.
+func Generate() (string, error) {
+	secret := make([]byte, keyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// Hash returns the hash a key is stored and looked up by. Keys are random
+// enough that a fast hash is as safe as a slow one.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix returns the start of a key that is kept in clear to tell the keys
+// apart.
+func Prefix(key string) string {
+	if len(key) < prefixLength {
+		return key
+	}
+	return key[:prefixLength]
+}
@@ -0,0 +1,35 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Run("Should return distinct keys starting with the key prefix", func(t *testing.T) {
+		first, err := Generate()
+		second, _ := Generate()
+
+		assert.Nil(t, err)
+		assert.True(t, strings.HasPrefix(first, "afk_"))
+		assert.Len(t, first, 47)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestHash(t *testing.T) {
+	t.Run("Should return the same hex hash for the same key only", func(t *testing.T) {
+		assert.Equal(t, Hash("afk_one"), Hash("afk_one"))
+		assert.NotEqual(t, Hash("afk_one"), Hash("afk_two"))
+		assert.Len(t, Hash("afk_one"), 64)
+	})
+}
+
+func TestPrefix(t *testing.T) {
+	t.Run("Should keep the start of the key", func(t *testing.T) {
+		assert.Equal(t, "afk_abcd", Prefix("afk_abcdefgh"))
+		assert.Equal(t, "afk", Prefix("afk"))
+	})
+}
@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/jwt-go"
+)
+
+const devKeyBits = 2048
+
+// DevIssuer mints tokens signed with its own RSA key, so that the API can run
+// in development without reaching a remote issuer. The API trusts the tokens
+// it mints next to those of ISS.
+type DevIssuer struct {
+	URL      string
+	Audience string
+	key      *rsa.PrivateKey
+	kid      string
+	cert     []byte
+}
+
+// ProvideDevIssuer returns the development issuer when ENV is dev, or nil
+// otherwise, which leaves it out of the routes and the middleware. Its key is
+// read from DEV_ISSUER_KEY_FILE, which is created with a new key when missing,
+// or generated for the process without one. Its tokens are issued by
+// DEV_ISSUER_URL, http://localhost:<PORT>/ by default, for AUD.
+func ProvideDevIssuer() *DevIssuer {
+	if os.Getenv("ENV") != "dev" {
+		return nil
+	}
+	key, err := loadDevKey(os.Getenv("DEV_ISSUER_KEY_FILE"))
+	if err != nil {
+		log.Printf("could not load the development issuer key: %v", err)
+		return nil
+	}
+	url := os.Getenv("DEV_ISSUER_URL")
+	if url == "" {
+		url = "http://localhost:" + os.Getenv("PORT") + "/"
+	}
+	issuer, err := NewDevIssuer(url, os.Getenv("AUD"), key)
+	if err != nil {
+		log.Printf("could not create the development issuer: %v", err)
+		return nil
+	}
+	return issuer
+}
+
+// NewDevIssuer returns an issuer signing with key, whose public half it
+// publishes in a self-signed certificate.
+func NewDevIssuer(url, audience string, key *rsa.PrivateKey) (*DevIssuer, error) {
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "aluraflix-api development issuer"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(10, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint := sha256.Sum256(x509.MarshalPKCS1PublicKey(&key.PublicKey))
+	return &DevIssuer{
+		URL:      url,
+		Audience: audience,
+		key:      key,
+		kid:      base64.RawURLEncoding.EncodeToString(thumbprint[:12]),
+		cert:     cert,
+	}, nil
+}
+
+// loadDevKey reads the PEM RSA key of the file, creating the file with a new
+// key when it does not exist. Without a file the key only lives in memory.
+func loadDevKey(file string) (*rsa.PrivateKey, error) {
+	if file != "" {
+		if data, err := ioutil.ReadFile(file); err == nil {
+			return jwt.ParseRSAPrivateKeyFromPEM(data)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	key, err := rsa.GenerateKey(rand.Reader, devKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+		if err = ioutil.WriteFile(file, data, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Mint signs a token for subject granting the scopes, valid for ttl.
+func (issuer *DevIssuer) Mint(subject string, scopes []string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   issuer.URL,
+		"sub":   subject,
+		"scope": strings.Join(scopes, " "),
+		"iat":   now.Unix(),
+		"exp":   now.Add(ttl).Unix(),
+	}
+	if issuer.Audience != "" {
+		claims["aud"] = issuer.Audience
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = issuer.kid
+	return token.SignedString(issuer.key)
+}
+
+// JWKS returns the key set publishing the public key of the issuer.
+func (issuer *DevIssuer) JWKS() Jwks {
+	return Jwks{Keys: []JSONWebKeys{{
+		Kty: "RSA",
+		Kid: issuer.kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(issuer.key.PublicKey.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.PublicKey.E)).Bytes()),
+		X5c: []string{base64.StdEncoding.EncodeToString(issuer.cert)},
+	}}}
+}
+
+// ValidateToken is the ValidateToken of the package, which also accepts the
+// tokens the issuer minted. A nil issuer accepts only the tokens of ISS.
+func (issuer *DevIssuer) ValidateToken(token *jwt.Token) (interface{}, error) {
+	return validateToken(token, issuer)
+}
+
+// verificationKey returns the public key checking the signature of a token
+// the issuer minted.
+func (issuer *DevIssuer) verificationKey(token *jwt.Token) (interface{}, error) {
+	if kid, _ := token.Header["kid"].(string); kid != issuer.kid {
+		return nil, ErrKeyNotFound
+	}
+	return &issuer.key.PublicKey, nil
+}
@@ -0,0 +1,127 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDevIssuer(t *testing.T) *DevIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	assert.Nil(t, err)
+	issuer, err := NewDevIssuer("http://localhost:3000/", "https://unit-test-audience/", key)
+	assert.Nil(t, err)
+	return issuer
+}
+
+func TestProvideDevIssuer(t *testing.T) {
+	t.Run("Should not enable the issuer when ENV is not dev", func(t *testing.T) {
+		os.Setenv("ENV", "prod")
+		defer os.Unsetenv("ENV")
+
+		assert.Nil(t, ProvideDevIssuer())
+	})
+
+	t.Run("Should create the key file and load it back when ENV is dev", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "dev-issuer.pem")
+		os.Setenv("ENV", "dev")
+		os.Setenv("PORT", "3000")
+		os.Setenv("DEV_ISSUER_KEY_FILE", file)
+		defer func() {
+			os.Unsetenv("ENV")
+			os.Unsetenv("DEV_ISSUER_KEY_FILE")
+		}()
+
+		first := ProvideDevIssuer()
+		_, err := os.Stat(file)
+		second := ProvideDevIssuer()
+
+		assert.Nil(t, err)
+		assert.NotNil(t, first)
+		assert.Equal(t, "http://localhost:3000/", first.URL)
+		assert.Equal(t, first.kid, second.kid)
+	})
+}
+
+func TestDevIssuer_Mint(t *testing.T) {
+	t.Run("Should mint a token the issuer validates with the subject and scopes", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		os.Setenv("ISS", "https://unit-test-issuer.us.auth0.com/")
+		issuer := newTestDevIssuer(t)
+
+		signed, err := issuer.Mint("dev|alice", []string{"read:videos", "write:videos"}, time.Hour)
+		assert.Nil(t, err)
+		token, err := jwt.Parse(signed, issuer.ValidateToken)
+
+		assert.Nil(t, err)
+		assert.True(t, token.Valid)
+		claims := token.Claims.(jwt.MapClaims)
+		assert.Equal(t, "dev|alice", claims["sub"])
+		assert.Equal(t, "read:videos write:videos", claims["scope"])
+		assert.Equal(t, "http://localhost:3000/", claims["iss"])
+	})
+
+	t.Run("Should reject a token of the development issuer signed with another key", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		issuer := newTestDevIssuer(t)
+		other, _ := rsa.GenerateKey(rand.Reader, 1024)
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss": issuer.URL,
+			"aud": issuer.Audience,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = "unknown"
+		signed, _ := token.SignedString(other)
+
+		_, err := jwt.Parse(signed, issuer.ValidateToken)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should be rejected by ValidateToken, which only trusts ISS", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		os.Setenv("ISS", "https://unit-test-issuer.us.auth0.com/")
+		issuer := newTestDevIssuer(t)
+
+		signed, _ := issuer.Mint("dev|alice", nil, time.Hour)
+		_, err := jwt.Parse(signed, ValidateToken)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should reject an expired token", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		issuer := newTestDevIssuer(t)
+
+		signed, _ := issuer.Mint("dev|alice", nil, -time.Minute)
+		_, err := jwt.Parse(signed, issuer.ValidateToken)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestDevIssuer_JWKS(t *testing.T) {
+	t.Run("Should publish the public key of the issuer", func(t *testing.T) {
+		issuer := newTestDevIssuer(t)
+
+		jwks := issuer.JWKS()
+
+		assert.Len(t, jwks.Keys, 1)
+		key := jwks.Keys[0]
+		n, _ := base64.RawURLEncoding.DecodeString(key.N)
+		e, _ := base64.RawURLEncoding.DecodeString(key.E)
+		assert.Equal(t, issuer.kid, key.Kid)
+		assert.Equal(t, "RS256", key.Alg)
+		assert.Equal(t, issuer.key.PublicKey.N, new(big.Int).SetBytes(n))
+		assert.Equal(t, int64(issuer.key.PublicKey.E), new(big.Int).SetBytes(e).Int64())
+		assert.Len(t, key.X5c, 1)
+	})
+}
@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encodeBigInt(n *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(n.Bytes())
+}
+
+func TestJSONWebKeys_PublicKey(t *testing.T) {
+	t.Run("Should read the key of the certificate When the key has an x5c chain", func(t *testing.T) {
+		issuer := newTestDevIssuer(t)
+		jwk := issuer.JWKS().Keys[0]
+		jwk.N, jwk.E = "", ""
+
+		key, err := jwk.PublicKey()
+
+		assert.Nil(t, err)
+		assert.Equal(t, &issuer.key.PublicKey, key)
+	})
+
+	t.Run("Should read an RSA key from n and e", func(t *testing.T) {
+		private, _ := rsa.GenerateKey(rand.Reader, 1024)
+		jwk := JSONWebKeys{Kty: "RSA", N: encodeBigInt(private.N), E: encodeBigInt(big.NewInt(int64(private.E)))}
+
+		key, err := jwk.PublicKey()
+
+		assert.Nil(t, err)
+		assert.Equal(t, &private.PublicKey, key)
+	})
+
+	t.Run("Should read an EC key from crv, x and y", func(t *testing.T) {
+		private, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		jwk := JSONWebKeys{Kty: "EC", Crv: "P-256", X: encodeBigInt(private.X), Y: encodeBigInt(private.Y)}
+
+		key, err := jwk.PublicKey()
+
+		assert.Nil(t, err)
+		assert.Equal(t, &private.PublicKey, key)
+	})
+
+	t.Run("Should reject an EC point that is not on the curve", func(t *testing.T) {
+		jwk := JSONWebKeys{Kty: "EC", Crv: "P-256", X: encodeBigInt(big.NewInt(1)), Y: encodeBigInt(big.NewInt(2))}
+
+		_, err := jwk.PublicKey()
+
+		assert.Equal(t, "invalid EC key", err.Error())
+	})
+
+	t.Run("Should read an Ed25519 key from an OKP key", func(t *testing.T) {
+		public, _, _ := ed25519.GenerateKey(rand.Reader)
+		jwk := JSONWebKeys{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(public)}
+
+		key, err := jwk.PublicKey()
+
+		assert.Nil(t, err)
+		assert.Equal(t, public, key)
+	})
+
+	t.Run("Should reject a key type it cannot verify signatures with", func(t *testing.T) {
+		_, err := JSONWebKeys{Kty: "oct"}.PublicKey()
+
+		assert.Equal(t, `unsupported key type "oct"`, err.Error())
+	})
+}
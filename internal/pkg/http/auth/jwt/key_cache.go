@@ -0,0 +1,241 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultKeyCacheTTLSeconds     = 600
+	defaultKeyRefreshLimitSeconds = 30
+	keyFetchTimeout               = 5 * time.Second
+)
+
+// ErrKeyNotFound is returned when the issuer publishes no key with the kid of
+// the token, even after refreshing its keys.
+var ErrKeyNotFound = errors.New("unable to find appropriate key")
+
+// KeyCacheStats tells operators how the cache of signing keys is doing.
+type KeyCacheStats struct {
+	Hits          int64         `json:"hits"`
+	Misses        int64         `json:"misses"`
+	Refreshes     int64         `json:"refreshes"`
+	RefreshErrors int64         `json:"refreshErrors"`
+	StaleHits     int64         `json:"staleHits"`
+	Issuers       []IssuerStats `json:"issuers"`
+}
+
+// IssuerStats describes the keys cached for one issuer.
+type IssuerStats struct {
+	URL         string     `json:"url"`
+	Keys        int        `json:"keys"`
+	RefreshedAt *time.Time `json:"refreshedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastError   string     `json:"lastError,omitempty"`
+}
+
+// KeyCache keeps the signing keys of the issuers, fetched from their
+// jwks.json, so that validating a token does not fetch them every time. Keys
+// are kept for the max-age of the response, or JWKS_CACHE_TTL_SECONDS without
+// one. A token signed with an unknown kid refreshes them early, at most once
+// every JWKS_REFRESH_LIMIT_SECONDS, and a failed refresh keeps serving the
+// last keys fetched. A kid the keys at hand lack waits for the refresh in
+// flight, if any. It is safe for concurrent use.
+type KeyCache struct {
+	mu      sync.Mutex
+	client  *http.Client
+	now     func() time.Time
+	entries map[string]*keySet
+	stats   KeyCacheStats
+}
+
+// keySet holds the public keys of one issuer by kid.
+type keySet struct {
+	keys        map[string]interface{}
+	refreshedAt time.Time
+	expiresAt   time.Time
+	attemptedAt time.Time
+	refreshing  bool
+	refreshed   chan struct{}
+	lastErr     error
+}
+
+func ProvideKeyCache() *KeyCache {
+	return &KeyCache{
+		client:  &http.Client{Timeout: keyFetchTimeout},
+		now:     time.Now,
+		entries: map[string]*keySet{},
+	}
+}
+
+// keyCache is the cache ValidateToken looks the signing keys up in.
+var keyCache = ProvideKeyCache()
+
+// KeyCacheStatistics returns the statistics of the cache ValidateToken uses.
+func KeyCacheStatistics() KeyCacheStats {
+	return keyCache.Stats()
+}
+
+// Key returns the public key kid of the issuer, refreshing the keys when they
+// expired or do not hold kid yet. Callers looking up a kid the keys lack while
+// they are being refreshed wait for that refresh rather than fetching again.
+func (c *KeyCache) Key(issuer string, kid string) (interface{}, error) {
+	url := fmt.Sprintf("%s.well-known/jwks.json", issuer)
+	c.mu.Lock()
+	entry, ok := c.entries[url]
+	if !ok {
+		entry = &keySet{}
+		c.entries[url] = entry
+	}
+	now := c.now()
+	key, known := entry.keys[kid]
+	fresh := now.Before(entry.expiresAt)
+	if known && fresh {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return key, nil
+	}
+	if entry.refreshing && !known {
+		refreshed := entry.refreshed
+		c.mu.Unlock()
+		<-refreshed
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, known := entry.keys[kid]; !known && entry.lastErr != nil {
+			c.stats.Misses++
+			return nil, entry.lastErr
+		}
+		return c.lookup(entry, kid, c.now().Before(entry.expiresAt))
+	}
+	if entry.refreshing || now.Sub(entry.attemptedAt) < getKeyRefreshLimit() {
+		defer c.mu.Unlock()
+		return c.lookup(entry, kid, fresh)
+	}
+	entry.refreshing, entry.refreshed, entry.attemptedAt = true, make(chan struct{}), now
+	c.stats.Refreshes++
+	c.mu.Unlock()
+
+	keys, ttl, err := c.fetch(url)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry.refreshing = false
+	defer close(entry.refreshed)
+	if err != nil {
+		c.stats.RefreshErrors++
+		entry.lastErr = err
+		if _, known := entry.keys[kid]; !known {
+			c.stats.Misses++
+			return nil, err
+		}
+		return c.lookup(entry, kid, false)
+	}
+	now = c.now()
+	entry.keys, entry.refreshedAt, entry.expiresAt, entry.lastErr = keys, now, now.Add(ttl), nil
+	return c.lookup(entry, kid, true)
+}
+
+// lookup serves kid from the keys at hand, counting a stale hit when they
+// expired.
+func (c *KeyCache) lookup(entry *keySet, kid string, fresh bool) (interface{}, error) {
+	key, known := entry.keys[kid]
+	if !known {
+		c.stats.Misses++
+		return nil, ErrKeyNotFound
+	}
+	if fresh {
+		c.stats.Hits++
+	} else {
+		c.stats.StaleHits++
+	}
+	return key, nil
+}
+
+// fetch downloads the keys of the issuer and how long they may be kept. Keys
+// meant for encryption, or of a type ValidateToken cannot use, are left out.
+func (c *KeyCache) fetch(url string) (map[string]interface{}, time.Duration, error) {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("fetching %s answered %s", url, resp.Status)
+	}
+
+	var jwks = Jwks{}
+	if err = json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return nil, 0, err
+	}
+	keys := map[string]interface{}{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		if key, err := jwk.PublicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, cacheTTL(resp.Header.Get("Cache-Control")), nil
+}
+
+// Stats returns a snapshot of the statistics of the cache.
+func (c *KeyCache) Stats() KeyCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Issuers = []IssuerStats{}
+	for url, entry := range c.entries {
+		issuer := IssuerStats{URL: url, Keys: len(entry.keys)}
+		if entry.lastErr != nil {
+			issuer.LastError = entry.lastErr.Error()
+		}
+		if !entry.refreshedAt.IsZero() {
+			refreshedAt, expiresAt := entry.refreshedAt, entry.expiresAt
+			issuer.RefreshedAt, issuer.ExpiresAt = &refreshedAt, &expiresAt
+		}
+		stats.Issuers = append(stats.Issuers, issuer)
+	}
+	sort.Slice(stats.Issuers, func(i, j int) bool { return stats.Issuers[i].URL < stats.Issuers[j].URL })
+	return stats
+}
+
+// cacheTTL reads for how long a response may be kept from its Cache-Control
+// header: its max-age, none for no-cache or no-store, and
+// JWKS_CACHE_TTL_SECONDS otherwise.
+func cacheTTL(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return 0
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return getSecondsSetting("JWKS_CACHE_TTL_SECONDS", defaultKeyCacheTTLSeconds)
+}
+
+// getKeyRefreshLimit returns how long to wait between two refreshes of the
+// keys of an issuer, configured in seconds through JWKS_REFRESH_LIMIT_SECONDS.
+func getKeyRefreshLimit() time.Duration {
+	return getSecondsSetting("JWKS_REFRESH_LIMIT_SECONDS", defaultKeyRefreshLimitSeconds)
+}
+
+func getSecondsSetting(name string, fallback int) time.Duration {
+	seconds := fallback
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n >= 0 {
+		seconds = n
+	}
+	return time.Duration(seconds) * time.Second
+}
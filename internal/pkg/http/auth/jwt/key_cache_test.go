@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// provideTestIssuer serves a jwks.json holding the given kids with the
+// Cache-Control header, counting the fetches, and answers 500 while failing
+// is set.
+func provideTestIssuer(t *testing.T, cacheControl string, fetches *int32, failing *int32, kids ...string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(fetches, 1)
+		if atomic.LoadInt32(failing) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		keys := ""
+		for i, kid := range kids {
+			if i > 0 {
+				keys += ","
+			}
+			x := base64.RawURLEncoding.EncodeToString(provideTestKey(kid).Public().(ed25519.PublicKey))
+			keys += `{"kty":"OKP","crv":"Ed25519","kid":"` + kid + `","x":"` + x + `"}`
+		}
+		w.Header().Set("Cache-Control", cacheControl)
+		_, _ = w.Write([]byte(`{"keys":[` + keys + `]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/"
+}
+
+// provideTestKey returns the Ed25519 key the test issuers sign with for kid.
+func provideTestKey(kid string) ed25519.PrivateKey {
+	seed := sha256.Sum256([]byte(kid))
+	return ed25519.NewKeyFromSeed(seed[:])
+}
+
+func provideTestKeyCache(now *time.Time) *KeyCache {
+	cache := ProvideKeyCache()
+	cache.now = func() time.Time { return *now }
+	return cache
+}
+
+func TestKeyCache(t *testing.T) {
+	t.Run("Key Should serve the keys from the cache until their max-age passes", func(t *testing.T) {
+		var fetches, failing int32
+		issuer := provideTestIssuer(t, "public, max-age=60", &fetches, &failing, "first")
+		now := time.Now()
+		cache := provideTestKeyCache(&now)
+
+		key, err := cache.Key(issuer, "first")
+		assert.Nil(t, err)
+		assert.Equal(t, provideTestKey("first").Public(), key)
+		_, _ = cache.Key(issuer, "first")
+		assert.Equal(t, int32(1), fetches)
+
+		now = now.Add(61 * time.Second)
+		_, err = cache.Key(issuer, "first")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), fetches)
+
+		stats := cache.Stats()
+		assert.Equal(t, int64(3), stats.Hits)
+		assert.Equal(t, int64(2), stats.Refreshes)
+		assert.Equal(t, 1, stats.Issuers[0].Keys)
+		assert.Equal(t, now.Add(time.Minute), *stats.Issuers[0].ExpiresAt)
+	})
+
+	t.Run("Key Should refresh once for an unknown kid and then wait for the refresh limit", func(t *testing.T) {
+		var fetches, failing int32
+		issuer := provideTestIssuer(t, "max-age=600", &fetches, &failing, "first")
+		now := time.Now()
+		cache := provideTestKeyCache(&now)
+		_, _ = cache.Key(issuer, "first")
+
+		now = now.Add(time.Minute)
+		_, err := cache.Key(issuer, "rotated")
+		assert.Equal(t, ErrKeyNotFound, err)
+		_, err = cache.Key(issuer, "rotated")
+		assert.Equal(t, ErrKeyNotFound, err)
+		assert.Equal(t, int32(2), fetches)
+
+		now = now.Add(getKeyRefreshLimit())
+		_, _ = cache.Key(issuer, "rotated")
+		assert.Equal(t, int32(3), fetches)
+		assert.Equal(t, int64(3), cache.Stats().Misses)
+	})
+
+	t.Run("Key Should keep serving the last keys When a refresh fails", func(t *testing.T) {
+		var fetches, failing int32
+		issuer := provideTestIssuer(t, "max-age=60", &fetches, &failing, "first")
+		now := time.Now()
+		cache := provideTestKeyCache(&now)
+		_, _ = cache.Key(issuer, "first")
+
+		atomic.StoreInt32(&failing, 1)
+		now = now.Add(2 * time.Minute)
+		key, err := cache.Key(issuer, "first")
+		assert.Nil(t, err)
+		assert.Equal(t, provideTestKey("first").Public(), key)
+		_, err = cache.Key(issuer, "first")
+		assert.Nil(t, err)
+		assert.Equal(t, int32(2), fetches)
+
+		stats := cache.Stats()
+		assert.Equal(t, int64(2), stats.StaleHits)
+		assert.Equal(t, int64(1), stats.RefreshErrors)
+		assert.Contains(t, stats.Issuers[0].LastError, "500")
+	})
+
+	t.Run("Key Should return the error When the first fetch fails", func(t *testing.T) {
+		var fetches int32
+		failing := int32(1)
+		issuer := provideTestIssuer(t, "", &fetches, &failing)
+		now := time.Now()
+		cache := provideTestKeyCache(&now)
+
+		_, err := cache.Key(issuer, "first")
+		assert.NotNil(t, err)
+		assert.NotEqual(t, ErrKeyNotFound, err)
+	})
+
+	t.Run("Key Should wait for the first fetch in flight instead of failing", func(t *testing.T) {
+		var fetches, failing int32
+		issuer := provideTestIssuer(t, "max-age=60", &fetches, &failing, "first")
+		started, release := make(chan struct{}), make(chan struct{})
+		var once sync.Once
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			once.Do(func() { close(started) })
+			<-release
+			http.Redirect(w, r, issuer+".well-known/jwks.json", http.StatusFound)
+		}))
+		t.Cleanup(slow.Close)
+		now := time.Now()
+		cache := provideTestKeyCache(&now)
+
+		errs := make(chan error, 2)
+		lookup := func() {
+			_, err := cache.Key(slow.URL+"/", "first")
+			errs <- err
+		}
+		go lookup()
+		<-started
+		go lookup()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+
+		assert.Nil(t, <-errs)
+		assert.Nil(t, <-errs)
+		assert.Equal(t, int32(1), fetches)
+		assert.Equal(t, int64(1), cache.Stats().Refreshes)
+	})
+}
+
+func TestCacheTTL(t *testing.T) {
+	t.Run("Should read the max-age of the response", func(t *testing.T) {
+		assert.Equal(t, 90*time.Second, cacheTTL("public, max-age=90, must-revalidate"))
+	})
+
+	t.Run("Should not keep a response that must not be cached", func(t *testing.T) {
+		assert.Equal(t, time.Duration(0), cacheTTL("no-store"))
+	})
+
+	t.Run("Should fall back to JWKS_CACHE_TTL_SECONDS When the response has no max-age", func(t *testing.T) {
+		assert.Equal(t, 10*time.Minute, cacheTTL(""))
+
+		os.Setenv("JWKS_CACHE_TTL_SECONDS", "120")
+		defer os.Unsetenv("JWKS_CACHE_TTL_SECONDS")
+		assert.Equal(t, 2*time.Minute, cacheTTL("public"))
+	})
+}
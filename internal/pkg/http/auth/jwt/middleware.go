@@ -1,70 +1,197 @@
 package jwt
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"time"
 
-	jwtmiddleware "github.com/auth0/go-jwt-middleware"
 	"github.com/form3tech-oss/jwt-go"
 )
 
-var JwtMiddleware = jwtmiddleware.New(jwtmiddleware.Options{
-	ValidationKeyGetter: ValidateToken,
-	SigningMethod:       jwt.SigningMethodRS256,
-})
+// Middleware authenticates requests with the bearer token of their
+// Authorization header. Tokens are signed with one of the accepted algorithms
+// by a key ValidateToken finds, and must pass ValidateClaims. The token is
+// kept in the context of the request under "user".
+type Middleware struct {
+	// CredentialsOptional serves the requests without a token anonymously.
+	CredentialsOptional bool
+	// DevIssuer is the development issuer whose tokens are trusted next to
+	// those of ISS, if any.
+	DevIssuer *DevIssuer
+}
 
-func ValidateToken(token *jwt.Token) (interface{}, error) {
-	// Verify 'aud' claim
-	audience := os.Getenv("AUD")
-	checkAudience := token.Claims.(jwt.MapClaims).VerifyAudience(audience, false)
-	if !checkAudience {
-		return token, errors.New("invalid audience")
+var JwtMiddleware = &Middleware{}
+
+// OptionalJwtMiddleware lets requests without a token through anonymously,
+// while still rejecting the ones carrying an invalid token.
+var OptionalJwtMiddleware = &Middleware{CredentialsOptional: true}
+
+// Handler answers 401 to the requests the middleware does not authenticate,
+// and serves the others with next.
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		token, err := m.authenticate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if token != nil {
+			r = r.WithContext(context.WithValue(r.Context(), "user", token))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authenticate returns the validated token of the request, or none when the
+// request carries none and may go without.
+func (m *Middleware) authenticate(r *http.Request) (*jwt.Token, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		if m.CredentialsOptional {
+			return nil, nil
+		}
+		return nil, errors.New("Required authorization token not found")
 	}
-	// Verify 'issuer' claim
-	issuer := os.Getenv("ISS")
-	checkIssuer := token.Claims.(jwt.MapClaims).VerifyIssuer(issuer, false)
-	if !checkIssuer {
-		return token, errors.New("invalid issuer")
+	parts := strings.Fields(header)
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return nil, errors.New("Authorization header format must be Bearer {token}")
 	}
-
-	cert, err := getPemCert(issuer, token)
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.Parse(parts[1], m.DevIssuer.ValidateToken)
 	if err != nil {
 		return nil, err
 	}
+	if err = ValidateClaims(token.Claims.(jwt.MapClaims), time.Now()); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
 
-	result, _ := jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
-	return result, nil
+// IsAuthenticated reports whether a middleware validated a token for the request.
+func IsAuthenticated(r *http.Request) bool {
+	return Authenticated(r.Context())
 }
 
-func getPemCert(issuer string, token *jwt.Token) (string, error) {
-	cert := ""
-	resp, err := http.Get(fmt.Sprintf("%s.well-known/jwks.json", issuer))
+// Authenticated reports whether a middleware validated a token for the
+// request the context belongs to.
+func Authenticated(ctx context.Context) bool {
+	_, ok := ctx.Value("user").(*jwt.Token)
+	return ok
+}
 
-	if err != nil {
-		return cert, err
+// Claim returns the string claim of the token a middleware validated for the
+// request the context belongs to, or an empty string when it has none.
+func Claim(ctx context.Context, name string) string {
+	token, ok := ctx.Value("user").(*jwt.Token)
+	if !ok {
+		return ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
 	}
-	defer resp.Body.Close()
+	value, _ := claims[name].(string)
+	return value
+}
 
-	var jwks = Jwks{}
-	err = json.NewDecoder(resp.Body).Decode(&jwks)
+// WithPrincipal returns a copy of ctx authenticated as subject with the
+// permissions, as if a middleware had validated a token granting them, so that
+// requests authenticated otherwise are authorized and audited alike.
+func WithPrincipal(ctx context.Context, subject string, permissions []string) context.Context {
+	claims := jwt.MapClaims{"sub": subject, "scope": strings.Join(permissions, " ")}
+	return context.WithValue(ctx, "user", &jwt.Token{Claims: claims, Valid: true})
+}
 
-	if err != nil {
-		return cert, err
+// Subject returns the "sub" claim of the token a middleware validated for the
+// request the context belongs to, or an empty string for anonymous requests.
+func Subject(ctx context.Context) string {
+	return Claim(ctx, "sub")
+}
+
+// Permissions returns the permissions granted by the token a middleware
+// validated for the request the context belongs to, read from both its space
+// separated "scope" claim and its "permissions" claim.
+func Permissions(ctx context.Context) []string {
+	token, ok := ctx.Value("user").(*jwt.Token)
+	if !ok {
+		return nil
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+	var permissions []string
+	if scope, ok := claims["scope"].(string); ok {
+		permissions = append(permissions, strings.Fields(scope)...)
+	}
+	granted, _ := claims["permissions"].([]interface{})
+	for _, permission := range granted {
+		if permission, ok := permission.(string); ok {
+			permissions = append(permissions, permission)
+		}
 	}
+	return permissions
+}
 
-	for k := range jwks.Keys {
-		if token.Header["kid"] == jwks.Keys[k].Kid {
-			cert = "-----BEGIN CERTIFICATE-----\n" + jwks.Keys[k].X5c[0] + "\n-----END CERTIFICATE-----"
+// HasPermission reports whether the token a middleware validated for the
+// request grants permission.
+func HasPermission(r *http.Request, permission string) bool {
+	for _, granted := range Permissions(r.Context()) {
+		if granted == permission {
+			return true
 		}
 	}
+	return false
+}
 
-	if cert == "" {
-		err := errors.New("unable to find appropriate key")
-		return cert, err
+// ValidateToken returns the key checking the signature of the token: the
+// JWT_HS256_SECRET for HS256 tokens, and otherwise the key its kid names in
+// the JWKS of its issuer. The token must be signed with an accepted algorithm,
+// for AUD, and issued by ISS.
+func ValidateToken(token *jwt.Token) (interface{}, error) {
+	return validateToken(token, nil)
+}
+
+// validateToken is ValidateToken also accepting the tokens issued by
+// devIssuer, unless it is nil.
+func validateToken(token *jwt.Token, devIssuer *DevIssuer) (interface{}, error) {
+	// Verify the signing algorithm
+	if !isAccepted(token.Method.Alg()) {
+		return nil, fmt.Errorf("signing method %s is not accepted", token.Method.Alg())
+	}
+	// Verify 'aud' claim
+	audience := os.Getenv("AUD")
+	checkAudience := token.Claims.(jwt.MapClaims).VerifyAudience(audience, false)
+	if !checkAudience {
+		return token, errors.New("invalid audience")
+	}
+	// Tokens of the development issuer are checked with its own key
+	if devIssuer != nil && token.Claims.(jwt.MapClaims).VerifyIssuer(devIssuer.URL, true) {
+		return devIssuer.verificationKey(token)
+	}
+	// Verify 'issuer' claim
+	issuer := os.Getenv("ISS")
+	checkIssuer := token.Claims.(jwt.MapClaims).VerifyIssuer(issuer, false)
+	if !checkIssuer {
+		return token, errors.New("invalid issuer")
+	}
+	// Shared-secret tokens are checked with the secret
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		secret := os.Getenv("JWT_HS256_SECRET")
+		if secret == "" {
+			return nil, errors.New("no shared secret is configured")
+		}
+		return []byte(secret), nil
 	}
 
-	return cert, nil
+	kid, _ := token.Header["kid"].(string)
+	return keyCache.Key(issuer, kid)
 }
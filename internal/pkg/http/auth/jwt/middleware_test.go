@@ -1,10 +1,19 @@
 package jwt
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/form3tech-oss/jwt-go"
 	"github.com/jarcoal/httpmock"
@@ -46,6 +55,7 @@ func TestValidateToken(t *testing.T) {
 
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
+		keyCache = ProvideKeyCache()
 
 		httpmock.RegisterResponder(
 			"GET",
@@ -74,6 +84,7 @@ func TestValidateToken(t *testing.T) {
 
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
+		keyCache = ProvideKeyCache()
 
 		httpmock.RegisterResponder(
 			"GET",
@@ -100,6 +111,7 @@ func TestValidateToken(t *testing.T) {
 
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
+		keyCache = ProvideKeyCache()
 
 		httpmock.RegisterResponder(
 			"GET",
@@ -126,6 +138,7 @@ func TestValidateToken(t *testing.T) {
 
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
+		keyCache = ProvideKeyCache()
 		json := `{"keys":[{"alg":"RS256","kty":"RSA","use":"sig","n":"x-N4R5lgHyXWfjf-izlxrrr2LAn7bUq1cL069yB0G4sy9FCM1RBeet1tHeQ3szbCxYxZIZ1ODRu9BuK34TyEkyBNtAOITU5WjUVuMrWd9iK-noIVJwhykLooGwHVSUCMPjeRNd7sxf2WW3uwR1R3PglZeu25pBR0e9PxI8tUU8QWsMOdrCRw5tMyoqC5SQsa1J4HIzuTaYfuOClF4kpv933_c79VquvdrWEJ1MzDHG2Lfrb_wxaFuOMXzPSTnOsINWwG2-0rb0UXXm_emsa8NrDu2Wi-nlw0UYAwVUQEtwK_5KegZWI39pp3aaDR62jdiEiL85BulrEjefxGZVHDUw","e":"AQAB","kid":"M0Xo-5mQq2nlEDgkbZeEm","x5t":"4Qi2aVFszWUy5QBdl52a-BLZjZU","x5c":["MIIDETCCAfmgAwIBAgIJWh9HI7fDZC1/MA0GCSqGSIb3DQEBCwUAMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTAeFw0yMTA4MTQwNDQ2NDlaFw0zNTA0MjMwNDQ2NDlaMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAMfjeEeZYB8l1n43/os5ca669iwJ+21KtXC9OvcgdBuLMvRQjNUQXnrdbR3kN7M2wsWMWSGdTg0bvQbit+E8hJMgTbQDiE1OVo1FbjK1nfYivp6CFScIcpC6KBsB1UlAjD43kTXe7MX9llt7sEdUdz4JWXrtuaQUdHvT8SPLVFPEFrDDnawkcObTMqKguUkLGtSeByM7k2mH7jgpReJKb/d9/3O/Varr3a1hCdTMwxxti362/8MWhbjjF8z0k5zrCDVsBtvtK29FF15v3prGvDaw7tlovp5cNFGAMFVEBLcCv+SnoGViN/aad2mg0eto3YhIi/OQbpaxI3n8RmVRw1MCAwEAAaNCMEAwDwYDVR0TAQH/BAUwAwEB/zAdBgNVHQ4EFgQU/FadD6LzgRq42C86qGuHfwmzlB0wDgYDVR0PAQH/BAQDAgKEMA0GCSqGSIb3DQEBCwUAA4IBAQBNDGgCFs5Wy71637Zon7VDEP8LdnsaeAACedMEJKMxh80AifEQviqSufo9LWgck4vsSfTeAWREDPxJ7rFhh4siHemQpm+8fExPmZc1NSH0+2xaPGJfeBX+GrUAVlHmObzbgChfKXvOI07+41JmxCKqTYAbu5/AHCAwOyF65JS3XEiatmmisuECOoM71+QSMxNhJOFMUK9Rysjb5XidpFB3mC2OLFy7SEvHbZuGUyS+sE4k9xSYl5zxO+DO8e2dCGdDs3MKX8XNIEvnTdR65i6gm0+a1/aastr4GNNvbPxiI7ELBFcn6iWI/0zL54Rvv5rc0WWJ6t772hDG3+JCJqs9"]},{"alg":"RS256","kty":"RSA","use":"sig","n":"malZ2q_aHX7VD_ykryOYQIOHmyKT1Q94rdUKZGLxp0Rw0s_livESCmOgrKqLxVjEQmUUokqThMhAiDi7OPcrzy150iYk5J7wmj-D3eDvFFiABnBDlvt2lSLPmUY4R-NTRQ1wNfbLKmQycOrWTAGT9P4VXp45IARuRdFtjU9lsXmifWpCEcLlv61WPMzL0b9ld_GBAWvvE-sbINOpzm_xBrPwcIsImQNAsN9mFmZSSaiVQ7bQOpExergecF39yaTxXA0PfSorcsVW6XEvi3UQgS9HCdVjX2VXuCdu_HvnC-rRuqXrXPqSMq3QmPvqLwWK53DEhCxroHGKKoG2CKgbTw","e":"AQAB","kid":"GKhfLaIlbtpIESk_Aedrc","x5t":"gvRF9c9nmTlsv0-O0_Oik4lOBjc","x5c":["MIIDETCCAfmgAwIBAgIJb279+r/8IMU9MA0GCSqGSIb3DQEBCwUAMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTAeFw0yMTA4MTQwNDQ2NDlaFw0zNTA0MjMwNDQ2NDlaMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAJmpWdqv2h1+1Q/8pK8jmECDh5sik9UPeK3VCmRi8adEcNLP5YrxEgpjoKyqi8VYxEJlFKJKk4TIQIg4uzj3K88tedImJOSe8Jo/g93g7xRYgAZwQ5b7dpUiz5lGOEfjU0UNcDX2yypkMnDq1kwBk/T+FV6eOSAEbkXRbY1PZbF5on1qQhHC5b+tVjzMy9G/ZXfxgQFr7xPrGyDTqc5v8Qaz8HCLCJkDQLDfZhZmUkmolUO20DqRMXq4HnBd/cmk8VwND30qK3LFVulxL4t1EIEvRwnVY19lV7gnbvx75wvq0bql61z6kjKt0Jj76i8FiudwxIQsa6BxiiqBtgioG08CAwEAAaNCMEAwDwYDVR0TAQH/BAUwAwEB/zAdBgNVHQ4EFgQUdsVi3xAtWNTvD8hYUbjerqtCTbkwDgYDVR0PAQH/BAQDAgKEMA0GCSqGSIb3DQEBCwUAA4IBAQBumE6HlpDk8Gw8KSkkay75qfWzx3meilu3RqpcoKEXausq70Xr5HfVnXl493trW5aBwgZCn5OzPfWWTIi4XpmSMeAwZRM9zJ3WfMQzO/M0ObF7K5s3wYLcc0t+djha/dZggdiOTWaw6i/KpyrJ1DRF3pybhae46I13pGQqGL4c7eJqlGo3l2t75h69H/NjwG+4lFDzoZUK+ca2nuglaHxbIeGoNO/Pm+cSMhl7kqvWZiL4/WKFpDAJVnA1QJ9pnq99/X9kbNMsxbNuOSKSO3pbHzVQetCEGAeYmj7KaCvGSXSbHwcoiFOkHFWfbPrmsHjDwltBziJRjADz1brQ6J/D"]}]}`
 
 		httpmock.RegisterResponder(
@@ -154,6 +167,7 @@ func TestValidateToken(t *testing.T) {
 
 		httpmock.Activate()
 		defer httpmock.DeactivateAndReset()
+		keyCache = ProvideKeyCache()
 		json := `{"keys":[{"alg":"RS256","kty":"RSA","use":"sig","n":"x-N4R5lgHyXWfjf-izlxrrr2LAn7bUq1cL069yB0G4sy9FCM1RBeet1tHeQ3szbCxYxZIZ1ODRu9BuK34TyEkyBNtAOITU5WjUVuMrWd9iK-noIVJwhykLooGwHVSUCMPjeRNd7sxf2WW3uwR1R3PglZeu25pBR0e9PxI8tUU8QWsMOdrCRw5tMyoqC5SQsa1J4HIzuTaYfuOClF4kpv933_c79VquvdrWEJ1MzDHG2Lfrb_wxaFuOMXzPSTnOsINWwG2-0rb0UXXm_emsa8NrDu2Wi-nlw0UYAwVUQEtwK_5KegZWI39pp3aaDR62jdiEiL85BulrEjefxGZVHDUw","e":"AQAB","kid":"M0Xo-5mQq2nlEDgkbZeEm","x5t":"4Qi2aVFszWUy5QBdl52a-BLZjZU","x5c":["MIIDETCCAfmgAwIBAgIJWh9HI7fDZC1/MA0GCSqGSIb3DQEBCwUAMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTAeFw0yMTA4MTQwNDQ2NDlaFw0zNTA0MjMwNDQ2NDlaMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAMfjeEeZYB8l1n43/os5ca669iwJ+21KtXC9OvcgdBuLMvRQjNUQXnrdbR3kN7M2wsWMWSGdTg0bvQbit+E8hJMgTbQDiE1OVo1FbjK1nfYivp6CFScIcpC6KBsB1UlAjD43kTXe7MX9llt7sEdUdz4JWXrtuaQUdHvT8SPLVFPEFrDDnawkcObTMqKguUkLGtSeByM7k2mH7jgpReJKb/d9/3O/Varr3a1hCdTMwxxti362/8MWhbjjF8z0k5zrCDVsBtvtK29FF15v3prGvDaw7tlovp5cNFGAMFVEBLcCv+SnoGViN/aad2mg0eto3YhIi/OQbpaxI3n8RmVRw1MCAwEAAaNCMEAwDwYDVR0TAQH/BAUwAwEB/zAdBgNVHQ4EFgQU/FadD6LzgRq42C86qGuHfwmzlB0wDgYDVR0PAQH/BAQDAgKEMA0GCSqGSIb3DQEBCwUAA4IBAQBNDGgCFs5Wy71637Zon7VDEP8LdnsaeAACedMEJKMxh80AifEQviqSufo9LWgck4vsSfTeAWREDPxJ7rFhh4siHemQpm+8fExPmZc1NSH0+2xaPGJfeBX+GrUAVlHmObzbgChfKXvOI07+41JmxCKqTYAbu5/AHCAwOyF65JS3XEiatmmisuECOoM71+QSMxNhJOFMUK9Rysjb5XidpFB3mC2OLFy7SEvHbZuGUyS+sE4k9xSYl5zxO+DO8e2dCGdDs3MKX8XNIEvnTdR65i6gm0+a1/aastr4GNNvbPxiI7ELBFcn6iWI/0zL54Rvv5rc0WWJ6t772hDG3+JCJqs9"]},{"alg":"RS256","kty":"RSA","use":"sig","n":"malZ2q_aHX7VD_ykryOYQIOHmyKT1Q94rdUKZGLxp0Rw0s_livESCmOgrKqLxVjEQmUUokqThMhAiDi7OPcrzy150iYk5J7wmj-D3eDvFFiABnBDlvt2lSLPmUY4R-NTRQ1wNfbLKmQycOrWTAGT9P4VXp45IARuRdFtjU9lsXmifWpCEcLlv61WPMzL0b9ld_GBAWvvE-sbINOpzm_xBrPwcIsImQNAsN9mFmZSSaiVQ7bQOpExergecF39yaTxXA0PfSorcsVW6XEvi3UQgS9HCdVjX2VXuCdu_HvnC-rRuqXrXPqSMq3QmPvqLwWK53DEhCxroHGKKoG2CKgbTw","e":"AQAB","kid":"GKhfLaIlbtpIESk_Aedrc","x5t":"gvRF9c9nmTlsv0-O0_Oik4lOBjc","x5c":["MIIDETCCAfmgAwIBAgIJb279+r/8IMU9MA0GCSqGSIb3DQEBCwUAMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTAeFw0yMTA4MTQwNDQ2NDlaFw0zNTA0MjMwNDQ2NDlaMCYxJDAiBgNVBAMTG2FsdXJhLWZsaXgtYXBpLnVzLmF1dGgwLmNvbTCCASIwDQYJKoZIhvcNAQEBBQADggEPADCCAQoCggEBAJmpWdqv2h1+1Q/8pK8jmECDh5sik9UPeK3VCmRi8adEcNLP5YrxEgpjoKyqi8VYxEJlFKJKk4TIQIg4uzj3K88tedImJOSe8Jo/g93g7xRYgAZwQ5b7dpUiz5lGOEfjU0UNcDX2yypkMnDq1kwBk/T+FV6eOSAEbkXRbY1PZbF5on1qQhHC5b+tVjzMy9G/ZXfxgQFr7xPrGyDTqc5v8Qaz8HCLCJkDQLDfZhZmUkmolUO20DqRMXq4HnBd/cmk8VwND30qK3LFVulxL4t1EIEvRwnVY19lV7gnbvx75wvq0bql61z6kjKt0Jj76i8FiudwxIQsa6BxiiqBtgioG08CAwEAAaNCMEAwDwYDVR0TAQH/BAUwAwEB/zAdBgNVHQ4EFgQUdsVi3xAtWNTvD8hYUbjerqtCTbkwDgYDVR0PAQH/BAQDAgKEMA0GCSqGSIb3DQEBCwUAA4IBAQBumE6HlpDk8Gw8KSkkay75qfWzx3meilu3RqpcoKEXausq70Xr5HfVnXl493trW5aBwgZCn5OzPfWWTIi4XpmSMeAwZRM9zJ3WfMQzO/M0ObF7K5s3wYLcc0t+djha/dZggdiOTWaw6i/KpyrJ1DRF3pybhae46I13pGQqGL4c7eJqlGo3l2t75h69H/NjwG+4lFDzoZUK+ca2nuglaHxbIeGoNO/Pm+cSMhl7kqvWZiL4/WKFpDAJVnA1QJ9pnq99/X9kbNMsxbNuOSKSO3pbHzVQetCEGAeYmj7KaCvGSXSbHwcoiFOkHFWfbPrmsHjDwltBziJRjADz1brQ6J/D"]}]}`
 
 		httpmock.RegisterResponder(
@@ -176,3 +190,267 @@ func TestValidateToken(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+// provideSigningIssuer serves a JWKS publishing the public halves of an ES256
+// and an EdDSA key as x/y and OKP keys, and makes it the ISS the tokens are
+// validated against.
+func provideSigningIssuer(t *testing.T) (*ecdsa.PrivateKey, ed25519.PrivateKey) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	jwks := Jwks{Keys: []JSONWebKeys{
+		{Kty: "EC", Kid: "ec", Use: "sig", Crv: "P-256", X: encodeBigInt(ecKey.X), Y: encodeBigInt(ecKey.Y)},
+		{Kty: "OKP", Kid: "ed", Use: "sig", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(edPublic)},
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwks)
+	}))
+	t.Cleanup(server.Close)
+	os.Setenv("AUD", "https://unit-test-audience/")
+	os.Setenv("ISS", server.URL+"/")
+	keyCache = ProvideKeyCache()
+	return ecKey, edKey
+}
+
+func signTestToken(method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, _ := token.SignedString(key)
+	return signed
+}
+
+func TestValidateToken_Algorithms(t *testing.T) {
+	t.Run("Should accept ES256 and EdDSA tokens signed with keys published without x5c", func(t *testing.T) {
+		ecKey, edKey := provideSigningIssuer(t)
+		claims := jwt.MapClaims{"iss": os.Getenv("ISS"), "aud": "https://unit-test-audience/"}
+
+		es256, err := jwt.Parse(signTestToken(jwt.SigningMethodES256, "ec", ecKey, claims), ValidateToken)
+		assert.Nil(t, err)
+		assert.True(t, es256.Valid)
+
+		eddsa, err := jwt.Parse(signTestToken(SigningMethodEdDSA, "ed", edKey, claims), ValidateToken)
+		assert.Nil(t, err)
+		assert.True(t, eddsa.Valid)
+	})
+
+	t.Run("Should reject a token signed with the key of another algorithm", func(t *testing.T) {
+		_, edKey := provideSigningIssuer(t)
+		claims := jwt.MapClaims{"iss": os.Getenv("ISS"), "aud": "https://unit-test-audience/"}
+
+		_, err := jwt.Parse(signTestToken(SigningMethodEdDSA, "ec", edKey, claims), ValidateToken)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should only accept the algorithms of JWT_ALGORITHMS", func(t *testing.T) {
+		ecKey, _ := provideSigningIssuer(t)
+		os.Setenv("JWT_ALGORITHMS", "RS256, EdDSA")
+		defer os.Unsetenv("JWT_ALGORITHMS")
+		claims := jwt.MapClaims{"iss": os.Getenv("ISS"), "aud": "https://unit-test-audience/"}
+
+		_, err := jwt.Parse(signTestToken(jwt.SigningMethodES256, "ec", ecKey, claims), ValidateToken)
+
+		assert.Contains(t, err.Error(), "signing method ES256 is not accepted")
+	})
+
+	t.Run("Should check HS256 tokens with JWT_HS256_SECRET", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		os.Setenv("ISS", "https://unit-test-issuer.us.auth0.com/")
+		os.Setenv("JWT_HS256_SECRET", "unit-test-secret")
+		defer os.Unsetenv("JWT_HS256_SECRET")
+		claims := jwt.MapClaims{"iss": "https://unit-test-issuer.us.auth0.com/", "aud": "https://unit-test-audience/"}
+
+		token, err := jwt.Parse(signTestToken(jwt.SigningMethodHS256, "", []byte("unit-test-secret"), claims), ValidateToken)
+		assert.Nil(t, err)
+		assert.True(t, token.Valid)
+
+		_, err = jwt.Parse(signTestToken(jwt.SigningMethodHS256, "", []byte("another-secret"), claims), ValidateToken)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Should reject HS256 tokens When no secret is configured", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		os.Setenv("ISS", "https://unit-test-issuer.us.auth0.com/")
+		claims := jwt.MapClaims{"iss": "https://unit-test-issuer.us.auth0.com/", "aud": "https://unit-test-audience/"}
+
+		_, err := jwt.Parse(signTestToken(jwt.SigningMethodHS256, "", []byte("unit-test-secret"), claims), ValidateToken)
+
+		assert.Contains(t, err.Error(), "signing method HS256 is not accepted")
+	})
+}
+
+func TestValidateClaims(t *testing.T) {
+	// Claims decoded from JSON hold their times as float64
+	now := time.Now()
+
+	t.Run("Should accept a token that is neither expired nor early", func(t *testing.T) {
+		claims := jwt.MapClaims{"iat": float64(now.Unix()), "nbf": float64(now.Unix()), "exp": float64(now.Add(time.Minute).Unix())}
+
+		assert.Nil(t, ValidateClaims(claims, now))
+	})
+
+	t.Run("Should reject an expired token", func(t *testing.T) {
+		claims := jwt.MapClaims{"exp": float64(now.Add(-time.Minute).Unix())}
+
+		assert.Equal(t, "token is expired", ValidateClaims(claims, now).Error())
+	})
+
+	t.Run("Should reject a token not valid yet", func(t *testing.T) {
+		claims := jwt.MapClaims{"nbf": float64(now.Add(time.Minute).Unix())}
+
+		assert.Equal(t, "token is not valid yet", ValidateClaims(claims, now).Error())
+	})
+
+	t.Run("Should allow the clocks to drift by JWT_LEEWAY_SECONDS", func(t *testing.T) {
+		os.Setenv("JWT_LEEWAY_SECONDS", "120")
+		defer os.Unsetenv("JWT_LEEWAY_SECONDS")
+		claims := jwt.MapClaims{
+			"iat": float64(now.Add(time.Minute).Unix()),
+			"nbf": float64(now.Add(time.Minute).Unix()),
+			"exp": float64(now.Add(-time.Minute).Unix()),
+		}
+
+		assert.Nil(t, ValidateClaims(claims, now))
+		assert.NotNil(t, ValidateClaims(claims, now.Add(-2*time.Minute)))
+	})
+
+	t.Run("Should reject a token missing one of JWT_REQUIRED_CLAIMS", func(t *testing.T) {
+		os.Setenv("JWT_REQUIRED_CLAIMS", "sub, exp")
+		defer os.Unsetenv("JWT_REQUIRED_CLAIMS")
+		claims := jwt.MapClaims{"sub": "auth0|alice"}
+
+		assert.Equal(t, "token is missing the exp claim", ValidateClaims(claims, now).Error())
+	})
+}
+
+func TestMiddleware_Handler(t *testing.T) {
+	serve := func(m *Middleware, authorization string) *httptest.ResponseRecorder {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(Subject(r.Context())))
+		})
+		req, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		response := httptest.NewRecorder()
+		m.Handler(next).ServeHTTP(response, req)
+		return response
+	}
+
+	t.Run("Should serve the request with the validated token", func(t *testing.T) {
+		_, edKey := provideSigningIssuer(t)
+		signed := signTestToken(SigningMethodEdDSA, "ed", edKey, jwt.MapClaims{
+			"iss": os.Getenv("ISS"),
+			"aud": "https://unit-test-audience/",
+			"sub": "auth0|alice",
+			"exp": time.Now().Add(time.Hour).Unix(),
+		})
+
+		response := serve(JwtMiddleware, "Bearer "+signed)
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Equal(t, "auth0|alice", response.Body.String())
+	})
+
+	t.Run("Should answer 401 When the token expired", func(t *testing.T) {
+		_, edKey := provideSigningIssuer(t)
+		signed := signTestToken(SigningMethodEdDSA, "ed", edKey, jwt.MapClaims{
+			"iss": os.Getenv("ISS"),
+			"aud": "https://unit-test-audience/",
+			"exp": time.Now().Add(-time.Hour).Unix(),
+		})
+
+		response := serve(JwtMiddleware, "Bearer "+signed)
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.Equal(t, "token is expired\n", response.Body.String())
+	})
+
+	t.Run("Should answer 401 When the request has no token", func(t *testing.T) {
+		response := serve(JwtMiddleware, "")
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.Equal(t, "Required authorization token not found\n", response.Body.String())
+	})
+
+	t.Run("Should answer 401 When the Authorization header is not a bearer token", func(t *testing.T) {
+		response := serve(OptionalJwtMiddleware, "Basic dXNlcjpwYXNz")
+
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.Equal(t, "Authorization header format must be Bearer {token}\n", response.Body.String())
+	})
+
+	t.Run("Should serve anonymous requests When credentials are optional", func(t *testing.T) {
+		response := serve(OptionalJwtMiddleware, "")
+
+		assert.Equal(t, http.StatusOK, response.Code)
+		assert.Empty(t, response.Body.String())
+	})
+}
+
+func TestIsAuthenticated(t *testing.T) {
+	t.Run("Should return false When no middleware validated a token", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/suggest", nil)
+
+		assert.False(t, IsAuthenticated(r))
+	})
+
+	t.Run("Should return true When the request carries a validated token", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/suggest", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "user", &jwt.Token{}))
+
+		assert.True(t, IsAuthenticated(r))
+	})
+}
+
+func TestSubject(t *testing.T) {
+	t.Run("Should return the sub claim of the validated token", func(t *testing.T) {
+		ctx := context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{"sub": "auth0|123"}})
+
+		assert.Equal(t, "auth0|123", Subject(ctx))
+	})
+
+	t.Run("Should return an empty subject When the request is anonymous", func(t *testing.T) {
+		assert.Equal(t, "", Subject(context.TODO()))
+		assert.Equal(t, "", Subject(context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{}})))
+	})
+}
+
+func TestPermissions(t *testing.T) {
+	t.Run("Should join the scope and permissions claims", func(t *testing.T) {
+		ctx := context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{
+			"scope":       "openid read:videos",
+			"permissions": []interface{}{"write:videos", 42},
+		}})
+
+		assert.Equal(t, []string{"openid", "read:videos", "write:videos"}, Permissions(ctx))
+	})
+
+	t.Run("Should return no permissions When the request is anonymous", func(t *testing.T) {
+		assert.Empty(t, Permissions(context.TODO()))
+	})
+}
+
+func TestHasPermission(t *testing.T) {
+	withClaims := func(claims jwt.MapClaims) *http.Request {
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		return r.WithContext(context.WithValue(r.Context(), "user", &jwt.Token{Claims: claims}))
+	}
+
+	t.Run("Should find the permission in the scope claim", func(t *testing.T) {
+		assert.True(t, HasPermission(withClaims(jwt.MapClaims{"scope": "openid write:videos"}), "write:videos"))
+	})
+
+	t.Run("Should find the permission in the permissions claim", func(t *testing.T) {
+		assert.True(t, HasPermission(withClaims(jwt.MapClaims{"permissions": []interface{}{"write:videos"}}), "write:videos"))
+	})
+
+	t.Run("Should return false When the token does not grant the permission", func(t *testing.T) {
+		assert.False(t, HasPermission(withClaims(jwt.MapClaims{"scope": "write:videosx", "permissions": []interface{}{"read:videos"}}), "write:videos"))
+	})
+
+	t.Run("Should return false When the request is anonymous", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+
+		assert.False(t, HasPermission(r, "write:videos"))
+	})
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/jwt-go"
+)
+
+// AcceptedAlgorithms returns the signing algorithms tokens may use, listed
+// comma separated in JWT_ALGORITHMS. By default they are RS256, ES256 and
+// EdDSA, plus HS256 when JWT_HS256_SECRET is set.
+func AcceptedAlgorithms() []string {
+	var algorithms []string
+	for _, algorithm := range strings.Split(os.Getenv("JWT_ALGORITHMS"), ",") {
+		if algorithm = strings.TrimSpace(algorithm); algorithm != "" {
+			algorithms = append(algorithms, algorithm)
+		}
+	}
+	if len(algorithms) > 0 {
+		return algorithms
+	}
+	algorithms = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg(), SigningMethodEdDSA.Alg()}
+	if os.Getenv("JWT_HS256_SECRET") != "" {
+		algorithms = append(algorithms, jwt.SigningMethodHS256.Alg())
+	}
+	return algorithms
+}
+
+// RequiredClaims returns the claims every token must carry, listed comma
+// separated in JWT_REQUIRED_CLAIMS.
+func RequiredClaims() []string {
+	var claims []string
+	for _, claim := range strings.Split(os.Getenv("JWT_REQUIRED_CLAIMS"), ",") {
+		if claim = strings.TrimSpace(claim); claim != "" {
+			claims = append(claims, claim)
+		}
+	}
+	return claims
+}
+
+// getLeeway returns how far the clocks of the issuers and the API may drift
+// apart when checking the times of a token, configured in seconds through
+// JWT_LEEWAY_SECONDS.
+func getLeeway() time.Duration {
+	return getSecondsSetting("JWT_LEEWAY_SECONDS", 0)
+}
+
+// ValidateClaims checks that the claims hold every required claim, and that
+// the token they belong to is valid at now, give or take the leeway: not
+// expired, and neither issued nor valid only after now.
+func ValidateClaims(claims jwt.MapClaims, now time.Time) error {
+	for _, claim := range RequiredClaims() {
+		if _, ok := claims[claim]; !ok {
+			return fmt.Errorf("token is missing the %s claim", claim)
+		}
+	}
+	leeway := getLeeway()
+	if !claims.VerifyExpiresAt(now.Add(-leeway).Unix(), false) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyIssuedAt(now.Add(leeway).Unix(), false) {
+		return errors.New("token used before issued")
+	}
+	if !claims.VerifyNotBefore(now.Add(leeway).Unix(), false) {
+		return errors.New("token is not valid yet")
+	}
+	return nil
+}
+
+// isAccepted reports whether tokens may be signed with the algorithm.
+func isAccepted(algorithm string) bool {
+	for _, accepted := range AcceptedAlgorithms() {
+		if accepted == algorithm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	AuditResources  = []string{models.VideoResource, models.CategoryResource}
+	AuditOperations = []string{models.CreateOperation, models.UpdateOperation, models.DeleteOperation, models.RestoreOperation,
+		models.PublishOperation}
+)
+
+// AuditFilter narrows the audit log. Empty fields do not filter. From is
+// inclusive and Before exclusive.
+type AuditFilter struct {
+	Resource   string
+	ResourceID primitive.ObjectID
+	Actor      string
+	Operation  string
+	From       time.Time
+	Before     time.Time
+}
+
+// Validate rejects a resource or an operation the audit log never records.
+func (filter AuditFilter) Validate() error {
+	if filter.Resource != "" && !containsString(AuditResources, filter.Resource) {
+		return InvalidFieldError("resource must be " + listChoices(AuditResources) + ".")
+	}
+	if filter.Operation != "" && !containsString(AuditOperations, filter.Operation) {
+		return InvalidFieldError("operation must be " + listChoices(AuditOperations) + ".")
+	}
+	return nil
+}
+
+// listChoices joins the allowed values of a field as in "a, b or c".
+func listChoices(values []string) string {
+	last := len(values) - 1
+	if last <= 0 {
+		return strings.Join(values, "")
+	}
+	return strings.Join(values[:last], ", ") + " or " + values[last]
+}
+
+// Matches reports whether the entry passes the filter, for the backends that
+// filter in process.
+func (filter AuditFilter) Matches(entry models.AuditEntry) bool {
+	if filter.Resource != "" && entry.Resource != filter.Resource {
+		return false
+	}
+	if !filter.ResourceID.IsZero() && entry.ResourceID != filter.ResourceID {
+		return false
+	}
+	if filter.Actor != "" && entry.Actor != filter.Actor {
+		return false
+	}
+	if filter.Operation != "" && entry.Operation != filter.Operation {
+		return false
+	}
+	if !filter.From.IsZero() && entry.Timestamp.Before(filter.From) {
+		return false
+	}
+	if !filter.Before.IsZero() && !entry.Timestamp.Before(filter.Before) {
+		return false
+	}
+	return true
+}
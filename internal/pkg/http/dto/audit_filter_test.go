@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuditFilter_Validate(t *testing.T) {
+	t.Run("Should accept an empty filter and the recorded resources and operations", func(t *testing.T) {
+		assert.Nil(t, AuditFilter{}.Validate())
+		assert.Nil(t, AuditFilter{Resource: models.CategoryResource, Operation: models.RestoreOperation}.Validate())
+	})
+
+	t.Run("Should reject an unknown resource or operation", func(t *testing.T) {
+		assert.Equal(t, InvalidFieldError("resource must be videos or categories."), AuditFilter{Resource: "users"}.Validate())
+		assert.Equal(t, InvalidFieldError("operation must be create, update, delete, restore or publish."), AuditFilter{Operation: "purge"}.Validate())
+	})
+}
+
+func TestAuditFilter_Matches(t *testing.T) {
+	at := time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC)
+	entry := models.AuditEntry{
+		ID:         primitive.NewObjectID(),
+		Resource:   models.VideoResource,
+		ResourceID: primitive.NewObjectID(),
+		Operation:  models.UpdateOperation,
+		Actor:      "auth0|123",
+		Timestamp:  at,
+	}
+
+	t.Run("Should match When every field of the filter matches", func(t *testing.T) {
+		assert.True(t, AuditFilter{}.Matches(entry))
+		assert.True(t, AuditFilter{
+			Resource: models.VideoResource, ResourceID: entry.ResourceID, Actor: "auth0|123", Operation: models.UpdateOperation,
+			From: at, Before: at.Add(time.Millisecond),
+		}.Matches(entry))
+	})
+
+	t.Run("Should not match When any field of the filter differs", func(t *testing.T) {
+		assert.False(t, AuditFilter{Resource: models.CategoryResource}.Matches(entry))
+		assert.False(t, AuditFilter{ResourceID: primitive.NewObjectID()}.Matches(entry))
+		assert.False(t, AuditFilter{Actor: "auth0|456"}.Matches(entry))
+		assert.False(t, AuditFilter{Operation: models.DeleteOperation}.Matches(entry))
+		assert.False(t, AuditFilter{From: at.Add(time.Millisecond)}.Matches(entry))
+		assert.False(t, AuditFilter{Before: at}.Matches(entry))
+	})
+}
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cursor marks the last item of a keyset page: its id and, when the listing
+// is sorted by another field, the sort it was taken with and that field's
+// value. Clients only see it as an opaque token.
+type Cursor struct {
+	ID    primitive.ObjectID `json:"id"`
+	Sort  string             `json:"sort,omitempty"`
+	Value string             `json:"value,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	token, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, InvalidFieldError("Invalid cursor.")
+	}
+	var cursor Cursor
+	if err = json.Unmarshal(raw, &cursor); err != nil || cursor.ID.IsZero() {
+		return nil, InvalidFieldError("Invalid cursor.")
+	}
+	return &cursor, nil
+}
+
+// NextVideoPage trims a keyset query that fetched one item past the limit,
+// returning the cursor to the next page or nil when it was the last one.
+func NextVideoPage(videos []models.Video, sort Sort, limit int64) ([]models.Video, *Cursor) {
+	if int64(len(videos)) <= limit {
+		return videos, nil
+	}
+	videos = videos[:limit]
+	last := videos[limit-1]
+	return videos, &Cursor{ID: last.ID, Sort: sort.String(), Value: sort.VideoKey(last)}
+}
+
+// NextCategoryPage is the NextVideoPage counterpart for categories.
+func NextCategoryPage(categories []models.Category, sort Sort, limit int64) ([]models.Category, *Cursor) {
+	if int64(len(categories)) <= limit {
+		return categories, nil
+	}
+	categories = categories[:limit]
+	last := categories[limit-1]
+	return categories, &Cursor{ID: last.ID, Sort: sort.String(), Value: sort.CategoryKey(last)}
+}
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	t.Run("Should decode an encoded cursor", func(t *testing.T) {
+		cursor := Cursor{ID: primitive.NewObjectID()}
+
+		decoded, err := DecodeCursor(cursor.Encode())
+
+		assert.Nil(t, err)
+		assert.Equal(t, cursor, *decoded)
+	})
+
+	t.Run("Should return a validation error When the token is not a cursor", func(t *testing.T) {
+		for _, token := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+			decoded, err := DecodeCursor(token)
+
+			assert.Nil(t, decoded)
+			assert.Equal(t, InvalidFieldError("Invalid cursor."), err)
+		}
+	})
+}
+
+func TestNextVideoPage(t *testing.T) {
+	videos := []models.Video{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}
+
+	t.Run("Should trim the extra item and point to the last one kept", func(t *testing.T) {
+		page, next := NextVideoPage(videos, Sort{}, 2)
+
+		assert.Equal(t, videos[:2], page)
+		assert.Equal(t, &Cursor{ID: videos[1].ID}, next)
+	})
+
+	t.Run("Should return no cursor on the last page", func(t *testing.T) {
+		page, next := NextVideoPage(videos, Sort{}, 3)
+
+		assert.Equal(t, videos, page)
+		assert.Nil(t, next)
+	})
+
+	t.Run("Should keep the sort and the sort key of the last item in the cursor", func(t *testing.T) {
+		sorted := []models.Video{{ID: primitive.NewObjectID(), Titulo: "b"}, {ID: primitive.NewObjectID(), Titulo: "a"}}
+
+		_, next := NextVideoPage(sorted, Sort{Field: "titulo", Descending: true}, 1)
+
+		assert.Equal(t, &Cursor{ID: sorted[0].ID, Sort: "-titulo", Value: "b"}, next)
+	})
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "strconv"
+
+const (
+	DefaultDevTokenSubject   = "dev|aluraflix"
+	DefaultDevTokenExpiresIn = 3600
+	maxDevTokenExpiresIn     = 86400
+)
+
+// DevTokenRequest represents the DTO asking the development issuer for a token
+type DevTokenRequest struct {
+	Subject   string   `json:"sub" example:"dev|aluraflix"`
+	Scopes    []string `json:"scopes" example:"read:videos,write:videos"`
+	ExpiresIn int64    `json:"expiresIn" example:"3600"`
+}
+
+// DevToken represents a token minted by the development issuer, shaped as an
+// OAuth 2.0 token response
+type DevToken struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type" example:"Bearer"`
+	ExpiresIn   int64  `json:"expires_in" example:"3600"`
+}
+
+// Normalize fills the subject and lifetime left out, and grants the
+// defaultScopes when no scope is asked for.
+func (request *DevTokenRequest) Normalize(defaultScopes []string) {
+	if request.Subject == "" {
+		request.Subject = DefaultDevTokenSubject
+	}
+	if request.Scopes == nil {
+		request.Scopes = defaultScopes
+	}
+	if request.ExpiresIn == 0 {
+		request.ExpiresIn = DefaultDevTokenExpiresIn
+	}
+}
+
+func (request *DevTokenRequest) Validate() error {
+	if request.ExpiresIn <= 0 || request.ExpiresIn > maxDevTokenExpiresIn {
+		return InvalidFieldError("expiresIn must be between 1 and " + strconv.Itoa(maxDevTokenExpiresIn) + " seconds.")
+	}
+	return nil
+}
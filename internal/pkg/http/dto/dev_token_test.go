@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDevTokenRequest_Normalize(t *testing.T) {
+	t.Run("Should fill the subject, scopes and lifetime left out", func(t *testing.T) {
+		request := DevTokenRequest{}
+		request.Normalize([]string{"read:videos"})
+
+		assert.Equal(t, DefaultDevTokenSubject, request.Subject)
+		assert.Equal(t, []string{"read:videos"}, request.Scopes)
+		assert.Equal(t, int64(DefaultDevTokenExpiresIn), request.ExpiresIn)
+	})
+
+	t.Run("Should keep an empty list of scopes asked for", func(t *testing.T) {
+		request := DevTokenRequest{Subject: "dev|alice", Scopes: []string{}, ExpiresIn: 60}
+		request.Normalize([]string{"read:videos"})
+
+		assert.Equal(t, "dev|alice", request.Subject)
+		assert.Empty(t, request.Scopes)
+		assert.Equal(t, int64(60), request.ExpiresIn)
+	})
+}
+
+func TestDevTokenRequest_Validate(t *testing.T) {
+	t.Run("Should return error when expiresIn is out of range", func(t *testing.T) {
+		for _, expiresIn := range []int64{-1, 86401} {
+			request := DevTokenRequest{ExpiresIn: expiresIn}
+
+			assert.Equal(t, "expiresIn must be between 1 and 86400 seconds.", request.Validate().Error())
+		}
+	})
+
+	t.Run("Should return nil when expiresIn is in range", func(t *testing.T) {
+		request := DevTokenRequest{ExpiresIn: 3600}
+
+		assert.Nil(t, request.Validate())
+	})
+}
@@ -1,7 +1,18 @@
 package dto
 
-import "errors"
+// ValidationError reports a DTO that did not pass its validation.
+type ValidationError struct {
+	message string
+}
+
+func (e ValidationError) Error() string {
+	return e.message
+}
 
 func MissingFieldError(missingField string) error {
-	return errors.New(missingField + " is required.")
+	return ValidationError{missingField + " is required."}
+}
+
+func InvalidFieldError(message string) error {
+	return ValidationError{message}
 }
@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultAPIKeyLifetime is how long a key created without an expiry lasts.
+const DefaultAPIKeyLifetime = 90 * 24 * time.Hour
+
+// InsertAPIKey represents the DTO of a new API key
+type InsertAPIKey struct {
+	Name      string     `json:"name" example:"Nightly export"`
+	Scopes    []string   `json:"scopes" example:"read:videos,read:categories"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2021-11-12T04:46:49Z"`
+}
+
+// ConvertToAPIKey returns the key stored for the given prefix and hash of its
+// secret, expiring DefaultAPIKeyLifetime after now without an expiry.
+func (key *InsertAPIKey) ConvertToAPIKey(prefix, hash string, now time.Time) models.APIKey {
+	expiresAt := now.Add(DefaultAPIKeyLifetime)
+	if key.ExpiresAt != nil {
+		expiresAt = *key.ExpiresAt
+	}
+	return models.APIKey{
+		ID:        primitive.NewObjectID(),
+		Name:      key.Name,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    key.Scopes,
+		ExpiresAt: expiresAt.UTC().Truncate(time.Millisecond),
+	}
+}
+
+// Validate checks the key has a name, grants at least one of the known
+// scopes and nothing else, and expires after now.
+func (key *InsertAPIKey) Validate(knownScopes []string, now time.Time) error {
+	if len(key.Name) == 0 {
+		return MissingFieldError("name")
+	}
+	if len(key.Scopes) == 0 {
+		return MissingFieldError("scopes")
+	}
+	for _, scope := range key.Scopes {
+		if !containsString(knownScopes, scope) {
+			return InvalidFieldError("scopes must be among " + listChoices(knownScopes) + ".")
+		}
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return InvalidFieldError("expiresAt must be in the future.")
+	}
+	return nil
+}
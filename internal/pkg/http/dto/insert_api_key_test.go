@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertAPIKey_ConvertToAPIKey(t *testing.T) {
+	now := time.Date(2021, 8, 14, 4, 46, 49, 0, time.UTC)
+
+	t.Run("Should keep the prefix, hash, scopes and expiry of the key", func(t *testing.T) {
+		expiresAt := now.AddDate(0, 1, 0)
+		insert := InsertAPIKey{Name: "Nightly export", Scopes: []string{"read:videos"}, ExpiresAt: &expiresAt}
+
+		key := insert.ConvertToAPIKey("afk_abcd", "hash", now)
+
+		assert.False(t, key.ID.IsZero())
+		assert.Equal(t, "Nightly export", key.Name)
+		assert.Equal(t, "afk_abcd", key.Prefix)
+		assert.Equal(t, "hash", key.Hash)
+		assert.Equal(t, []string{"read:videos"}, key.Scopes)
+		assert.Equal(t, expiresAt, key.ExpiresAt)
+	})
+
+	t.Run("Should expire the key after the default lifetime without an expiry", func(t *testing.T) {
+		insert := InsertAPIKey{Name: "Nightly export", Scopes: []string{"read:videos"}}
+
+		key := insert.ConvertToAPIKey("afk_abcd", "hash", now)
+
+		assert.Equal(t, now.Add(DefaultAPIKeyLifetime), key.ExpiresAt)
+	})
+}
+
+func TestInsertAPIKey_Validate(t *testing.T) {
+	now := time.Date(2021, 8, 14, 4, 46, 49, 0, time.UTC)
+	known := []string{"read:videos", "write:videos"}
+
+	t.Run("Should return error when name is empty", func(t *testing.T) {
+		insert := InsertAPIKey{Scopes: []string{"read:videos"}}
+
+		assert.Equal(t, "name is required.", insert.Validate(known, now).Error())
+	})
+
+	t.Run("Should return error when scopes are empty", func(t *testing.T) {
+		insert := InsertAPIKey{Name: "Export"}
+
+		assert.Equal(t, "scopes is required.", insert.Validate(known, now).Error())
+	})
+
+	t.Run("Should return error when a scope is unknown", func(t *testing.T) {
+		insert := InsertAPIKey{Name: "Export", Scopes: []string{"read:videos", "root"}}
+
+		assert.Equal(t, "scopes must be among read:videos or write:videos.", insert.Validate(known, now).Error())
+	})
+
+	t.Run("Should return error when expiresAt is not in the future", func(t *testing.T) {
+		insert := InsertAPIKey{Name: "Export", Scopes: []string{"read:videos"}, ExpiresAt: &now}
+
+		assert.Equal(t, "expiresAt must be in the future.", insert.Validate(known, now).Error())
+	})
+
+	t.Run("Should return nil when the key is valid", func(t *testing.T) {
+		expiresAt := now.Add(time.Hour)
+		insert := InsertAPIKey{Name: "Export", Scopes: []string{"write:videos"}, ExpiresAt: &expiresAt}
+
+		assert.Nil(t, insert.Validate(known, now))
+	})
+}
@@ -13,13 +13,21 @@ type InsertCategory struct {
 
 func (category *InsertCategory) ConvertToCategory() models.Category {
 	return models.Category{
-		ID:     primitive.NewObjectID(),
-		Titulo: category.Titulo,
-		Cor:    category.Cor,
-		Active: true,
+		ID:           primitive.NewObjectID(),
+		Titulo:       category.Titulo,
+		TituloSearch: NormalizeSearch(category.Titulo),
+		Cor:          category.Cor,
+		Active:       true,
+		Version:      1,
 	}
 }
 
+// InsertCategoryFrom holds the fields of a category an update sets, as when
+// restoring one of its revisions.
+func InsertCategoryFrom(category models.Category) InsertCategory {
+	return InsertCategory{Titulo: category.Titulo, Cor: category.Cor}
+}
+
 func (category *InsertCategory) Validate() error {
 	if len(category.Titulo) == 0 {
 		return MissingFieldError("Titulo")
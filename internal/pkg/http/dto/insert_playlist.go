@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var visibilities = []string{models.PublicVisibility, models.PrivateVisibility}
+
+// InsertPlaylist represents the DTO of a new or an updating playlist. Without
+// a visibility, a playlist is private. Its items are changed on their own.
+type InsertPlaylist struct {
+	Titulo     string `json:"titulo" example:"Example playlist"`
+	Descricao  string `json:"descricao" example:"Example description"`
+	Visibility string `json:"visibility,omitempty" example:"private"`
+}
+
+func (playlist *InsertPlaylist) ConvertToPlaylist() models.Playlist {
+	return models.Playlist{
+		ID:         primitive.NewObjectID(),
+		Titulo:     playlist.Titulo,
+		Descricao:  playlist.Descricao,
+		Visibility: playlist.Visibility,
+		Items:      []models.PlaylistItem{},
+		Version:    1,
+	}
+}
+
+// Normalize trims the surrounding spaces of the text fields and fills in the
+// visibility.
+func (playlist *InsertPlaylist) Normalize() {
+	playlist.Titulo = strings.TrimSpace(playlist.Titulo)
+	playlist.Descricao = strings.TrimSpace(playlist.Descricao)
+	playlist.Visibility = strings.ToLower(strings.TrimSpace(playlist.Visibility))
+	if playlist.Visibility == "" {
+		playlist.Visibility = models.PrivateVisibility
+	}
+}
+
+func (playlist *InsertPlaylist) Validate() error {
+	if len(playlist.Titulo) == 0 {
+		return MissingFieldError("Titulo")
+	}
+	if !containsString(visibilities, playlist.Visibility) {
+		return InvalidFieldError("visibility must be one of " + listChoices(visibilities) + ".")
+	}
+	return nil
+}
+
+// InsertPlaylistItem represents the DTO of a video added to a playlist.
+// Without a position, the video is added at the end.
+type InsertPlaylistItem struct {
+	VideoID  primitive.ObjectID `json:"videoID" example:"000000000000000000000000"`
+	Position int                `json:"position,omitempty" example:"1"`
+}
+
+func (item *InsertPlaylistItem) Validate() error {
+	if item.VideoID.IsZero() {
+		return MissingFieldError("videoID")
+	}
+	if item.Position < 0 {
+		return InvalidPositionError(item.Position)
+	}
+	return nil
+}
+
+// MovePlaylistItem represents the DTO of the new position of a video in a
+// playlist.
+type MovePlaylistItem struct {
+	Position int `json:"position" example:"1"`
+}
+
+func (move *MovePlaylistItem) Validate() error {
+	if move.Position < 1 {
+		return InvalidPositionError(move.Position)
+	}
+	return nil
+}
+
+// InvalidPositionError reports a position outside of a playlist.
+func InvalidPositionError(position int) error {
+	return InvalidFieldError("position " + strconv.Itoa(position) + " is not within the playlist.")
+}
+
+// PlaylistFilter narrows a playlist listing. OwnerID is the subject of the
+// user the playlists belong to, and Viewer the one of the user listing them,
+// who only sees the private playlists they own. An empty Viewer sees every
+// playlist, unless PublicOnly leaves out all the private ones.
+type PlaylistFilter struct {
+	OwnerID    string
+	Viewer     string
+	PublicOnly bool
+}
+
+// Matches reports whether the playlist passes the filter, for the backends
+// that filter in process.
+func (filter PlaylistFilter) Matches(playlist models.Playlist) bool {
+	if filter.OwnerID != "" && playlist.OwnerID != filter.OwnerID {
+		return false
+	}
+	if filter.PublicOnly {
+		return playlist.IsPublic()
+	}
+	return filter.Viewer == "" || playlist.IsPublic() || playlist.OwnerID == filter.Viewer
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInsertPlaylist_Normalize(t *testing.T) {
+	t.Run("Should make the playlist private When no visibility is given", func(t *testing.T) {
+		playlist := InsertPlaylist{Titulo: "  Favorites  "}
+
+		playlist.Normalize()
+
+		assert.Equal(t, "Favorites", playlist.Titulo)
+		assert.Equal(t, models.PrivateVisibility, playlist.Visibility)
+	})
+
+	t.Run("Should lower the case of the visibility", func(t *testing.T) {
+		playlist := InsertPlaylist{Titulo: "Favorites", Visibility: " Public "}
+
+		playlist.Normalize()
+
+		assert.Equal(t, models.PublicVisibility, playlist.Visibility)
+	})
+}
+
+func TestInsertPlaylist_Validate(t *testing.T) {
+	t.Run("Should return error when titulo is empty", func(t *testing.T) {
+		playlist := InsertPlaylist{Visibility: models.PrivateVisibility}
+
+		assert.Equal(t, "Titulo is required.", playlist.Validate().Error())
+	})
+
+	t.Run("Should return error when visibility is unknown", func(t *testing.T) {
+		playlist := InsertPlaylist{Titulo: "Favorites", Visibility: "hidden"}
+
+		assert.Equal(t, "visibility must be one of public or private.", playlist.Validate().Error())
+	})
+}
+
+func TestPlaylistItems_Validate(t *testing.T) {
+	t.Run("Should return error when the video or the position is missing", func(t *testing.T) {
+		assert.Equal(t, "videoID is required.", (&InsertPlaylistItem{}).Validate().Error())
+		assert.Equal(t, "position -1 is not within the playlist.",
+			(&InsertPlaylistItem{VideoID: primitive.NewObjectID(), Position: -1}).Validate().Error())
+		assert.Equal(t, "position 0 is not within the playlist.", (&MovePlaylistItem{}).Validate().Error())
+		assert.Nil(t, (&MovePlaylistItem{Position: 1}).Validate())
+	})
+}
+
+func TestPlaylistFilter_Matches(t *testing.T) {
+	private := models.Playlist{Visibility: models.PrivateVisibility, Ownership: models.Ownership{OwnerID: "auth0|123"}}
+	public := models.Playlist{Visibility: models.PublicVisibility, Ownership: models.Ownership{OwnerID: "auth0|123"}}
+
+	t.Run("Should only match the private playlists of the viewer", func(t *testing.T) {
+		assert.True(t, PlaylistFilter{Viewer: "auth0|123"}.Matches(private))
+		assert.False(t, PlaylistFilter{Viewer: "auth0|456"}.Matches(private))
+		assert.True(t, PlaylistFilter{Viewer: "auth0|456"}.Matches(public))
+		assert.True(t, PlaylistFilter{}.Matches(private))
+	})
+
+	t.Run("Should only match the playlists of the owner", func(t *testing.T) {
+		assert.False(t, PlaylistFilter{OwnerID: "auth0|456"}.Matches(public))
+		assert.True(t, PlaylistFilter{OwnerID: "auth0|123"}.Matches(public))
+	})
+}
@@ -1,31 +1,58 @@
 package dto
 
 import (
-	"errors"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"net/url"
+	"strings"
+	"time"
 )
 
-// InsertVideo represents the DTO of a new or an updating video
+// InsertVideo represents the DTO of a new or an updating video. Without a
+// status, a new video with a publishAt is scheduled and any other is
+// published, while an update without either keeps the stored ones.
 type InsertVideo struct {
 	Titulo     string             `json:"titulo" example:"Example video"`
 	Descricao  string             `json:"descricao" example:"Example description"`
 	Url        string             `json:"url" example:"https://www.example-url.com"`
 	CategoryID primitive.ObjectID `json:"categoriaID" example:"000000000000000000000000"`
+	Status     string             `json:"status,omitempty" example:"scheduled"`
+	PublishAt  *time.Time         `json:"publishAt,omitempty" example:"2021-08-14T04:46:49Z"`
 }
 
 func (video *InsertVideo) ConvertToVideo() models.Video {
 	return models.Video{
-		ID:         primitive.NewObjectID(),
-		Titulo:     video.Titulo,
-		Descricao:  video.Descricao,
-		Url:        video.Url,
-		CategoryID: video.CategoryID,
-		Active:     true,
+		ID:           primitive.NewObjectID(),
+		Titulo:       video.Titulo,
+		TituloSearch: NormalizeSearch(video.Titulo),
+		Descricao:    video.Descricao,
+		Url:          video.Url,
+		UrlHost:      URLHost(video.Url),
+		CategoryID:   video.CategoryID,
+		Status:       resolveStatus(video.Status, video.PublishAt),
+		PublishAt:    video.PublishAt,
+		Active:       true,
+		Version:      1,
 	}
 }
 
+// InsertVideoFrom holds the fields of a video an update sets, as when
+// restoring one of its revisions.
+func InsertVideoFrom(video models.Video) InsertVideo {
+	return InsertVideo{Titulo: video.Titulo, Descricao: video.Descricao, Url: video.Url, CategoryID: video.CategoryID,
+		Status: video.Status, PublishAt: video.PublishAt}
+}
+
+// Normalize trims the surrounding spaces of the text fields and fills in the
+// status.
+func (video *InsertVideo) Normalize() {
+	video.Titulo = strings.TrimSpace(video.Titulo)
+	video.Descricao = strings.TrimSpace(video.Descricao)
+	video.Url = strings.TrimSpace(video.Url)
+	video.Status = resolveStatus(video.Status, video.PublishAt)
+	video.PublishAt = normalizePublishAt(video.PublishAt)
+}
+
 func (video *InsertVideo) Validate() error {
 	if len(video.Titulo) == 0 {
 		return MissingFieldError("Titulo")
@@ -37,7 +64,7 @@ func (video *InsertVideo) Validate() error {
 		return MissingFieldError("Url")
 	}
 	if _, err := url.ParseRequestURI(video.Url); err != nil {
-		return errors.New("Url inválida.")
+		return InvalidFieldError("Url inválida.")
 	}
-	return nil
+	return validatePublication(video.Status, video.PublishAt)
 }
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -86,3 +87,60 @@ func TestInsertVideo_Validate(t *testing.T) {
 		assert.Nil(t, err)
 	})
 }
+
+func TestInsertVideo_Normalize(t *testing.T) {
+	videoToInsert := InsertVideo{
+		Titulo:    "  Input video test title ",
+		Descricao: "\tInput video test description\n",
+		Url:       " https://www.url.com ",
+	}
+
+	videoToInsert.Normalize()
+
+	assert.Equal(t, "Input video test title", videoToInsert.Titulo)
+	assert.Equal(t, "Input video test description", videoToInsert.Descricao)
+	assert.Equal(t, "https://www.url.com", videoToInsert.Url)
+}
+
+func TestInsertVideo_Publication(t *testing.T) {
+	publishAt := time.Now().Add(time.Hour)
+
+	t.Run("Should publish the video When neither status nor publishAt is given", func(t *testing.T) {
+		videoToInsert := InsertVideo{Titulo: "Title", Descricao: "Description", Url: "https://www.url.com"}
+
+		videoToInsert.Normalize()
+
+		assert.Nil(t, videoToInsert.Validate())
+		assert.Equal(t, "published", videoToInsert.ConvertToVideo().Status)
+	})
+
+	t.Run("Should schedule the video When only publishAt is given", func(t *testing.T) {
+		videoToInsert := InsertVideo{Titulo: "Title", Descricao: "Description", Url: "https://www.url.com", PublishAt: &publishAt}
+
+		videoToInsert.Normalize()
+
+		assert.Nil(t, videoToInsert.Validate())
+		assert.Equal(t, "scheduled", videoToInsert.Status)
+		assert.Equal(t, time.UTC, videoToInsert.PublishAt.Location())
+		assert.True(t, videoToInsert.PublishAt.Equal(publishAt.Truncate(time.Millisecond)))
+	})
+
+	t.Run("Should return error When the status is unknown", func(t *testing.T) {
+		videoToInsert := InsertVideo{Titulo: "Title", Descricao: "Description", Url: "https://www.url.com", Status: "live"}
+
+		assert.Equal(t, "status must be draft, scheduled, published or unpublished.", videoToInsert.Validate().Error())
+	})
+
+	t.Run("Should return error When a scheduled video has no publishAt", func(t *testing.T) {
+		videoToInsert := InsertVideo{Titulo: "Title", Descricao: "Description", Url: "https://www.url.com", Status: "scheduled"}
+
+		assert.Equal(t, "publishAt is required.", videoToInsert.Validate().Error())
+	})
+
+	t.Run("Should return error When a video not scheduled has a publishAt", func(t *testing.T) {
+		videoToInsert := InsertVideo{Titulo: "Title", Descricao: "Description", Url: "https://www.url.com", Status: "draft",
+			PublishAt: &publishAt}
+
+		assert.Equal(t, "publishAt is only allowed for scheduled videos.", videoToInsert.Validate().Error())
+	})
+}
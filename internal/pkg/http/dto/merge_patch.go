@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// mergePatch holds the members of a JSON Merge Patch (RFC 7396) document.
+// Members left out are kept as they are and null members are removed.
+type mergePatch map[string]json.RawMessage
+
+func parseMergePatch(data []byte) (mergePatch, error) {
+	var patch mergePatch
+	if err := json.Unmarshal(data, &patch); err != nil || patch == nil {
+		return nil, InvalidFieldError("A merge patch must be a JSON object.")
+	}
+	return patch, nil
+}
+
+func (patch mergePatch) isNull(key string) bool {
+	return bytes.Equal(bytes.TrimSpace(patch[key]), []byte("null"))
+}
+
+// requiredString reads a member that cannot be removed from the target.
+func (patch mergePatch) requiredString(key, field string, target **string) error {
+	if _, ok := patch[key]; !ok {
+		return nil
+	}
+	if patch.isNull(key) {
+		return InvalidFieldError(field + " cannot be removed.")
+	}
+	var value string
+	if err := json.Unmarshal(patch[key], &value); err != nil {
+		return InvalidFieldError(field + " must be a string.")
+	}
+	*target = &value
+	return nil
+}
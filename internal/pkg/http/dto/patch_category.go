@@ -0,0 +1,46 @@
+package dto
+
+import "strings"
+
+// PatchCategory represents a JSON Merge Patch of a category. Only the non nil fields change.
+type PatchCategory struct {
+	Titulo *string `json:"titulo,omitempty" example:"Example category"`
+	Cor    *string `json:"cor,omitempty" example:"blue"`
+}
+
+// UnmarshalJSON reads a merge patch document.
+func (category *PatchCategory) UnmarshalJSON(data []byte) error {
+	patch, err := parseMergePatch(data)
+	if err != nil {
+		return err
+	}
+	*category = PatchCategory{}
+	if err = patch.requiredString("titulo", "Titulo", &category.Titulo); err != nil {
+		return err
+	}
+	return patch.requiredString("cor", "Cor", &category.Cor)
+}
+
+func (category *PatchCategory) IsEmpty() bool {
+	return category.Titulo == nil && category.Cor == nil
+}
+
+// Normalize trims the surrounding spaces of the fields being changed.
+func (category *PatchCategory) Normalize() {
+	for _, field := range []*string{category.Titulo, category.Cor} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+}
+
+// Validate checks only the fields being changed.
+func (category *PatchCategory) Validate() error {
+	if category.Titulo != nil && len(*category.Titulo) == 0 {
+		return MissingFieldError("Titulo")
+	}
+	if category.Cor != nil && len(*category.Cor) == 0 {
+		return MissingFieldError("Cor")
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchCategory_UnmarshalJSON(t *testing.T) {
+	t.Run("Should only set the fields present in the patch", func(t *testing.T) {
+		var patch PatchCategory
+
+		err := json.Unmarshal([]byte(`{"cor":"green"}`), &patch)
+
+		assert.Nil(t, err)
+		assert.Nil(t, patch.Titulo)
+		assert.Equal(t, "green", *patch.Cor)
+	})
+
+	t.Run("Should return error when a field has the wrong type", func(t *testing.T) {
+		var patch PatchCategory
+
+		err := json.Unmarshal([]byte(`{"titulo":10}`), &patch)
+
+		assert.Equal(t, "Titulo must be a string.", err.Error())
+	})
+}
+
+func TestPatchCategory_Validate(t *testing.T) {
+	empty := ""
+	patch := PatchCategory{Cor: &empty}
+
+	assert.Equal(t, "Cor is required.", patch.Validate().Error())
+}
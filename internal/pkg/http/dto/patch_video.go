@@ -0,0 +1,112 @@
+package dto
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PatchVideo represents a JSON Merge Patch of a video. Only the non nil fields
+// change, except PublishAt, which changes along with Status: a patch holding
+// either sets the publication as a whole.
+type PatchVideo struct {
+	Titulo     *string             `json:"titulo,omitempty" example:"Example video"`
+	Descricao  *string             `json:"descricao,omitempty" example:"Example description"`
+	Url        *string             `json:"url,omitempty" example:"https://www.example-url.com"`
+	CategoryID *primitive.ObjectID `json:"categoriaID,omitempty" example:"000000000000000000000000"`
+	Status     *string             `json:"status,omitempty" example:"scheduled"`
+	PublishAt  *time.Time          `json:"publishAt,omitempty" example:"2021-08-14T04:46:49Z"`
+}
+
+// UnmarshalJSON reads a merge patch document. Removing categoriaID with null
+// moves the video back to the FREE category. A status or publishAt, removed
+// or not, sets the publication as if both had been given, so that patching
+// only a publishAt schedules the video.
+func (video *PatchVideo) UnmarshalJSON(data []byte) error {
+	patch, err := parseMergePatch(data)
+	if err != nil {
+		return err
+	}
+	*video = PatchVideo{}
+	if err = patch.requiredString("titulo", "Titulo", &video.Titulo); err != nil {
+		return err
+	}
+	if err = patch.requiredString("descricao", "Descricao", &video.Descricao); err != nil {
+		return err
+	}
+	if err = patch.requiredString("url", "Url", &video.Url); err != nil {
+		return err
+	}
+	if _, ok := patch["categoriaID"]; ok {
+		categoryID := primitive.NilObjectID
+		if !patch.isNull("categoriaID") {
+			if err = json.Unmarshal(patch["categoriaID"], &categoryID); err != nil {
+				return InvalidFieldError("categoriaID must be a valid id.")
+			}
+		}
+		video.CategoryID = &categoryID
+	}
+	_, hasStatus := patch["status"]
+	_, hasPublishAt := patch["publishAt"]
+	if !hasStatus && !hasPublishAt {
+		return nil
+	}
+	status := ""
+	if hasStatus && !patch.isNull("status") {
+		if err = json.Unmarshal(patch["status"], &status); err != nil {
+			return InvalidFieldError("status must be a string.")
+		}
+	}
+	video.Status = &status
+	if hasPublishAt && !patch.isNull("publishAt") {
+		var publishAt time.Time
+		if err = json.Unmarshal(patch["publishAt"], &publishAt); err != nil {
+			return InvalidFieldError("publishAt must be an RFC 3339 time.")
+		}
+		video.PublishAt = &publishAt
+	}
+	return nil
+}
+
+func (video *PatchVideo) IsEmpty() bool {
+	return video.Titulo == nil && video.Descricao == nil && video.Url == nil && video.CategoryID == nil && video.Status == nil
+}
+
+// Normalize trims the surrounding spaces of the text fields being changed and
+// fills in the status being set.
+func (video *PatchVideo) Normalize() {
+	for _, field := range []*string{video.Titulo, video.Descricao, video.Url} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+	if video.Status != nil {
+		*video.Status = resolveStatus(*video.Status, video.PublishAt)
+		video.PublishAt = normalizePublishAt(video.PublishAt)
+	}
+}
+
+// Validate checks only the fields being changed.
+func (video *PatchVideo) Validate() error {
+	if video.Titulo != nil && len(*video.Titulo) == 0 {
+		return MissingFieldError("Titulo")
+	}
+	if video.Descricao != nil && len(*video.Descricao) == 0 {
+		return MissingFieldError("Descricao")
+	}
+	if video.Url != nil {
+		if len(*video.Url) == 0 {
+			return MissingFieldError("Url")
+		}
+		if _, err := url.ParseRequestURI(*video.Url); err != nil {
+			return InvalidFieldError("Url inválida.")
+		}
+	}
+	if video.Status != nil {
+		return validatePublication(*video.Status, video.PublishAt)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPatchVideo_UnmarshalJSON(t *testing.T) {
+	t.Run("Should only set the fields present in the patch", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`{"titulo":"New title"}`), &patch)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "New title", *patch.Titulo)
+		assert.Nil(t, patch.Descricao)
+		assert.Nil(t, patch.Url)
+		assert.Nil(t, patch.CategoryID)
+	})
+
+	t.Run("Should move the video to the free category when categoriaID is null", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`{"categoriaID":null}`), &patch)
+
+		assert.Nil(t, err)
+		assert.Equal(t, primitive.NilObjectID, *patch.CategoryID)
+	})
+
+	t.Run("Should return error when removing a required field", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`{"url":null}`), &patch)
+
+		assert.Equal(t, "Url cannot be removed.", err.Error())
+	})
+
+	t.Run("Should schedule the video when the patch only holds a publishAt", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`{"publishAt":"2021-08-14T04:46:49Z"}`), &patch)
+		patch.Normalize()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "scheduled", *patch.Status)
+		assert.Equal(t, time.Date(2021, 8, 14, 4, 46, 49, 0, time.UTC), patch.PublishAt.UTC())
+		assert.Nil(t, patch.Validate())
+	})
+
+	t.Run("Should clear the publishAt when the patch only holds a status", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`{"status":"draft"}`), &patch)
+		patch.Normalize()
+
+		assert.Nil(t, err)
+		assert.Equal(t, "draft", *patch.Status)
+		assert.Nil(t, patch.PublishAt)
+		assert.False(t, patch.IsEmpty())
+	})
+
+	t.Run("Should return error when the patch is not an object", func(t *testing.T) {
+		var patch PatchVideo
+
+		err := json.Unmarshal([]byte(`["titulo"]`), &patch)
+
+		assert.Equal(t, "A merge patch must be a JSON object.", err.Error())
+	})
+}
+
+func TestPatchVideo_Validate(t *testing.T) {
+	t.Run("Should validate only the fields being changed", func(t *testing.T) {
+		url := "https://www.url.com"
+		patch := PatchVideo{Url: &url}
+
+		assert.Nil(t, patch.Validate())
+	})
+
+	t.Run("Should return error when a changed field is blank after normalizing", func(t *testing.T) {
+		title := "   "
+		patch := PatchVideo{Titulo: &title}
+
+		patch.Normalize()
+
+		assert.Equal(t, "Titulo is required.", patch.Validate().Error())
+	})
+
+	t.Run("Should return error when the url is invalid", func(t *testing.T) {
+		url := "www.url.com"
+		patch := PatchVideo{Url: &url}
+
+		assert.Equal(t, "Url inválida.", patch.Validate().Error())
+	})
+}
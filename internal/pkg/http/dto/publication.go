@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// VideoStatuses are the publication statuses a video can be in.
+var VideoStatuses = []string{models.DraftStatus, models.ScheduledStatus, models.PublishedStatus, models.UnpublishedStatus}
+
+// resolveStatus folds the status given for a video. Without one, a video
+// with a publishAt is scheduled and any other is published, as videos were
+// before they had a status.
+func resolveStatus(status string, publishAt *time.Time) string {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if status != "" {
+		return status
+	}
+	if publishAt != nil {
+		return models.ScheduledStatus
+	}
+	return models.PublishedStatus
+}
+
+// normalizePublishAt holds a publishAt in UTC to the millisecond, the
+// precision every backend stores, so that it compares alike everywhere.
+func normalizePublishAt(publishAt *time.Time) *time.Time {
+	if publishAt == nil {
+		return nil
+	}
+	normalized := publishAt.UTC().Truncate(time.Millisecond)
+	return &normalized
+}
+
+// validatePublication checks a status and publishAt given together: only
+// scheduled videos take a publishAt, and they require one.
+func validatePublication(status string, publishAt *time.Time) error {
+	status = resolveStatus(status, publishAt)
+	if !containsString(VideoStatuses, status) {
+		return InvalidFieldError("status must be " + listChoices(VideoStatuses) + ".")
+	}
+	if status == models.ScheduledStatus && publishAt == nil {
+		return MissingFieldError("publishAt")
+	}
+	if status != models.ScheduledStatus && publishAt != nil {
+		return InvalidFieldError("publishAt is only allowed for scheduled videos.")
+	}
+	return nil
+}
+
+// ParseVideoStatuses folds the statuses a listing is filtered by, rejecting
+// unknown ones.
+func ParseVideoStatuses(values []string) ([]string, error) {
+	var statuses []string
+	for _, value := range values {
+		status := strings.ToLower(strings.TrimSpace(value))
+		if !containsString(VideoStatuses, status) {
+			return nil, InvalidFieldError("status must be " + listChoices(VideoStatuses) + ".")
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeSearch folds text for case and accent insensitive matching, so
+// "Programação" and "PROGRAMACAO" are both stored and searched as "programacao".
+func NormalizeSearch(text string) string {
+	// Transformers keep state between calls, so each call builds its own chain.
+	stripMarks := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(stripMarks, text)
+	if err != nil {
+		folded = text
+	}
+	return strings.ToLower(folded)
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SearchResults groups the matches of a full-text search by resource type,
+// each group ranked from the most relevant.
+type SearchResults struct {
+	Query      string        `json:"query" example:"programacao"`
+	Videos     []VideoHit    `json:"videos"`
+	Categories []CategoryHit `json:"categories"`
+}
+
+// VideoHit is a video matched by a search. Highlights holds, for each field
+// that matched, a snippet with the matched words wrapped in <mark>.
+type VideoHit struct {
+	Video      models.Video      `json:"video"`
+	Score      float64           `json:"score" example:"1.5"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// CategoryHit is the VideoHit counterpart for categories.
+type CategoryHit struct {
+	Category   models.Category   `json:"category"`
+	Score      float64           `json:"score" example:"1.5"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+func (results *SearchResults) IsEmpty() bool {
+	return len(results.Videos) == 0 && len(results.Categories) == 0
+}
+
+// Suggestion is a title completing the prefix typed by the user.
+type Suggestion struct {
+	ID     primitive.ObjectID `json:"id" example:"000000000000000000000000"`
+	Titulo string             `json:"titulo" example:"Example video"`
+	Type   string             `json:"type" example:"video" enums:"video,category"`
+}
+
+const (
+	VideoSuggestion    = "video"
+	CategorySuggestion = "category"
+)
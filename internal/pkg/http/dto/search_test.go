@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSearch(t *testing.T) {
+	t.Run("Should fold case and accents", func(t *testing.T) {
+		assert.Equal(t, "programacao", NormalizeSearch("Programação"))
+		assert.Equal(t, "programacao", NormalizeSearch("PROGRAMACAO"))
+		assert.Equal(t, "introducao a logica", NormalizeSearch("Introdução à Lógica"))
+	})
+
+	t.Run("Should keep regex and like metacharacters as they are", func(t *testing.T) {
+		assert.Equal(t, "c++ (100%_off)", NormalizeSearch("C++ (100%_OFF)"))
+	})
+}
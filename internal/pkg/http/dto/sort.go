@@ -0,0 +1,125 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// SortByCreation orders by the ObjectID, which starts with the creation time.
+// SortByCreatedAt is the same order under the name of the audit field.
+const (
+	SortByCreation  = "created"
+	SortByCreatedAt = "createdAt"
+)
+
+// sortKeyLayout formats times with a fixed width, so that their text sorts
+// in time order.
+const sortKeyLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+var (
+	auditSortFields    = []string{SortByCreatedAt, "updatedAt", "createdBy", "updatedBy"}
+	VideoSortFields    = append([]string{"titulo", SortByCreation}, auditSortFields...)
+	CategorySortFields = append([]string{"titulo", "cor", SortByCreation}, auditSortFields...)
+)
+
+// sortColumns maps the sort parameters named after the JSON fields to the
+// name the backends store them under.
+var sortColumns = map[string]string{
+	"updatedAt": "updated_at",
+	"createdBy": "created_by",
+	"updatedBy": "updated_by",
+}
+
+// Sort orders a listing by one field, breaking ties by id in the same
+// direction. An empty Field orders by creation, so the zero value lists the
+// oldest items first. Field holds the name the backends store the field
+// under.
+type Sort struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort reads a sort parameter such as "titulo" or "-created", accepting
+// only the given fields.
+func ParseSort(value string, sortable []string) (Sort, error) {
+	if value == "" {
+		return Sort{}, nil
+	}
+	field := strings.TrimPrefix(value, "-")
+	for _, allowed := range sortable {
+		if field != allowed {
+			continue
+		}
+		if field == SortByCreation || field == SortByCreatedAt {
+			field = ""
+		}
+		if column, ok := sortColumns[field]; ok {
+			field = column
+		}
+		return Sort{Field: field, Descending: strings.HasPrefix(value, "-")}, nil
+	}
+	return Sort{}, InvalidFieldError("Cannot sort by " + field + ". Sortable fields: " + strings.Join(sortable, ", ") + ".")
+}
+
+// String returns the sort parameter that parses back into s.
+func (s Sort) String() string {
+	if s == (Sort{}) {
+		return ""
+	}
+	field := s.Field
+	if field == "" {
+		field = SortByCreation
+	}
+	for parameter, column := range sortColumns {
+		if field == column {
+			field = parameter
+		}
+	}
+	if s.Descending {
+		return "-" + field
+	}
+	return field
+}
+
+// Value converts a key returned by VideoKey or CategoryKey back into the type
+// the field is stored with, for the backends comparing it in their queries.
+func (s Sort) Value(key string) interface{} {
+	if s.Field == "updated_at" {
+		updatedAt, _ := time.Parse(sortKeyLayout, key)
+		return updatedAt
+	}
+	return key
+}
+
+// VideoKey returns the value a video is ordered by besides its id.
+func (s Sort) VideoKey(video models.Video) string {
+	if s.Field == "titulo" {
+		return video.Titulo
+	}
+	return auditKey(s.Field, video.Audit)
+}
+
+// CategoryKey returns the value a category is ordered by besides its id.
+func (s Sort) CategoryKey(category models.Category) string {
+	switch s.Field {
+	case "titulo":
+		return category.Titulo
+	case "cor":
+		return category.Cor
+	}
+	return auditKey(s.Field, category.Audit)
+}
+
+func auditKey(field string, audit models.Audit) string {
+	switch field {
+	case "updated_at":
+		return audit.UpdatedAt.UTC().Format(sortKeyLayout)
+	case "created_by":
+		return audit.CreatedBy
+	case "updated_by":
+		return audit.UpdatedBy
+	}
+	return ""
+}
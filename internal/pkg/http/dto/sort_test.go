@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Run("Should return the default sort When no sort is given", func(t *testing.T) {
+		sort, err := ParseSort("", VideoSortFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{}, sort)
+	})
+
+	t.Run("Should parse ascending and descending fields", func(t *testing.T) {
+		sort, err := ParseSort("titulo", VideoSortFields)
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Field: "titulo"}, sort)
+
+		sort, err = ParseSort("-titulo", VideoSortFields)
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Field: "titulo", Descending: true}, sort)
+	})
+
+	t.Run("Should sort by id When sorting by creation", func(t *testing.T) {
+		sort, err := ParseSort("-created", CategorySortFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Descending: true}, sort)
+		assert.Equal(t, "-created", sort.String())
+	})
+
+	t.Run("Should sort by the stored audit fields", func(t *testing.T) {
+		sort, err := ParseSort("-updatedAt", VideoSortFields)
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Field: "updated_at", Descending: true}, sort)
+		assert.Equal(t, "-updatedAt", sort.String())
+
+		sort, err = ParseSort("createdBy", CategorySortFields)
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Field: "created_by"}, sort)
+		assert.Equal(t, "createdBy", sort.String())
+
+		sort, err = ParseSort("-createdAt", VideoSortFields)
+		assert.Nil(t, err)
+		assert.Equal(t, Sort{Descending: true}, sort)
+	})
+
+	t.Run("Should return a validation error When the field is not sortable", func(t *testing.T) {
+		_, err := ParseSort("-cor", VideoSortFields)
+
+		assert.Equal(t, InvalidFieldError("Cannot sort by cor. Sortable fields: titulo, created, createdAt, updatedAt, createdBy, updatedBy."), err)
+	})
+}
+
+func TestSort_Value(t *testing.T) {
+	t.Run("Should convert the key of the update time back into a time", func(t *testing.T) {
+		updatedAt := time.Date(2021, 8, 14, 12, 0, 0, 5000000, time.UTC)
+		sort := Sort{Field: "updated_at"}
+		key := sort.VideoKey(models.Video{Audit: models.Audit{UpdatedAt: updatedAt}})
+
+		assert.Equal(t, "2021-08-14T12:00:00.005000000Z", key)
+		assert.Equal(t, updatedAt, sort.Value(key))
+	})
+
+	t.Run("Should keep the other keys as text", func(t *testing.T) {
+		sort := Sort{Field: "updated_by"}
+		key := sort.CategoryKey(models.Category{Audit: models.Audit{UpdatedBy: "auth0|123"}})
+
+		assert.Equal(t, "auth0|123", sort.Value(key))
+	})
+}
@@ -0,0 +1,157 @@
+package dto
+
+import (
+	"encoding/binary"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VideoFilter narrows a video listing. Empty fields do not filter, except
+// Active, which lists the active videos when nil. The creation range comes
+// from the ObjectID timestamps: CreatedFrom is inclusive and CreatedBefore
+// exclusive. Statuses are publication statuses, and OwnerID the subject of
+// the user the videos belong to.
+type VideoFilter struct {
+	Search        string
+	CategoryIDs   []primitive.ObjectID
+	Active        *bool
+	CreatedFrom   time.Time
+	CreatedBefore time.Time
+	Hosts         []string
+	Statuses      []string
+	OwnerID       string
+}
+
+// IsActive returns the state of the listed videos.
+func (filter VideoFilter) IsActive() bool {
+	return filter.Active == nil || *filter.Active
+}
+
+// WithoutCategories returns the filter that counts the category facet, which
+// ignores the categories being filtered so that the others remain selectable.
+func (filter VideoFilter) WithoutCategories() VideoFilter {
+	filter.CategoryIDs = nil
+	return filter
+}
+
+// WithoutHosts is the WithoutCategories counterpart for the host facet.
+func (filter VideoFilter) WithoutHosts() VideoFilter {
+	filter.Hosts = nil
+	return filter
+}
+
+// Matches reports whether the video passes the filter, for the backends that
+// filter in process.
+func (filter VideoFilter) Matches(video models.Video) bool {
+	if video.Active != filter.IsActive() {
+		return false
+	}
+	if filter.Search != "" && !strings.Contains(video.TituloSearch, NormalizeSearch(filter.Search)) {
+		return false
+	}
+	created := video.ID.Timestamp()
+	if !filter.CreatedFrom.IsZero() && created.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedBefore.IsZero() && !created.Before(filter.CreatedBefore) {
+		return false
+	}
+	if len(filter.CategoryIDs) > 0 && !containsID(filter.CategoryIDs, video.CategoryID) {
+		return false
+	}
+	if len(filter.Hosts) > 0 && !containsString(filter.Hosts, video.UrlHost) {
+		return false
+	}
+	if len(filter.Statuses) > 0 && !containsString(filter.Statuses, video.Status) {
+		return false
+	}
+	if filter.OwnerID != "" && video.OwnerID != filter.OwnerID {
+		return false
+	}
+	return true
+}
+
+// CreatedBound returns the smallest ObjectID created at t, to bound a range
+// of ids by creation time. Unlike primitive.NewObjectIDFromTimestamp, its
+// bytes after the timestamp are all zero.
+func CreatedBound(t time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+	return id
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// URLHost folds the host of a video URL, so that "https://WWW.YouTube.com/x"
+// and "http://youtube.com" both belong to "youtube.com".
+func URLHost(rawURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return NormalizeHost(parsed.Hostname())
+}
+
+// NormalizeHost folds a host given by the user the same way as URLHost.
+func NormalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(strings.TrimSpace(host)), "www.")
+}
+
+// FacetCount is how many videos of a listing share a value.
+type FacetCount struct {
+	Value string `json:"value" example:"youtube.com"`
+	Count int64  `json:"count" example:"3"`
+}
+
+// VideoFacets counts the videos of a listing per category and per URL host.
+// Each facet applies every filter but its own, so the values not selected yet
+// still show how many videos selecting them would add.
+type VideoFacets struct {
+	Categories []FacetCount `json:"categorias"`
+	Hosts      []FacetCount `json:"hosts"`
+}
+
+// SortFacet orders the counts from the most common value, then by value.
+func SortFacet(counts []FacetCount) []FacetCount {
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Value < counts[j].Value
+	})
+	return counts
+}
+
+// CountFacet counts the values returned for each item, in SortFacet order.
+func CountFacet(values []string) []FacetCount {
+	counts := map[string]int64{}
+	for _, value := range values {
+		counts[value]++
+	}
+	facet := []FacetCount{}
+	for value, count := range counts {
+		facet = append(facet, FacetCount{value, count})
+	}
+	return SortFacet(facet)
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestURLHost(t *testing.T) {
+	t.Run("Should fold the host of the url without www and port", func(t *testing.T) {
+		assert.Equal(t, "youtube.com", URLHost("https://WWW.YouTube.com:443/watch?v=1"))
+		assert.Equal(t, "youtube.com", URLHost("http://youtube.com"))
+		assert.Equal(t, "m.youtube.com", URLHost("https://m.youtube.com/x"))
+	})
+
+	t.Run("Should return an empty host When the url cannot be parsed", func(t *testing.T) {
+		assert.Equal(t, "", URLHost("://"))
+	})
+}
+
+func TestCreatedBound(t *testing.T) {
+	t.Run("Should sort before every id created at the same second and after the older ones", func(t *testing.T) {
+		createdAt := time.Now().Truncate(time.Second)
+		bound := CreatedBound(createdAt)
+
+		assert.Equal(t, createdAt.Unix(), bound.Timestamp().Unix())
+		assert.Equal(t, "0000000000000000", bound.Hex()[8:])
+		assert.True(t, bound.Hex() <= primitive.NewObjectIDFromTimestamp(createdAt).Hex())
+		assert.True(t, bound.Hex() > primitive.NewObjectIDFromTimestamp(createdAt.Add(-time.Second)).Hex())
+	})
+}
+
+func TestVideoFilter_Matches(t *testing.T) {
+	category := primitive.NewObjectID()
+	createdAt := time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC)
+	video := models.Video{
+		ID:           primitive.NewObjectIDFromTimestamp(createdAt),
+		CategoryID:   category,
+		TituloSearch: "go concorrente",
+		UrlHost:      "youtube.com",
+		Active:       true,
+		Status:       models.PublishedStatus,
+	}
+	inactive := false
+
+	t.Run("Should match the active videos When the filter is empty", func(t *testing.T) {
+		assert.True(t, VideoFilter{}.Matches(video))
+		assert.False(t, VideoFilter{Active: &inactive}.Matches(video))
+	})
+
+	t.Run("Should match any of the categories and hosts", func(t *testing.T) {
+		assert.True(t, VideoFilter{CategoryIDs: []primitive.ObjectID{primitive.NewObjectID(), category}}.Matches(video))
+		assert.False(t, VideoFilter{CategoryIDs: []primitive.ObjectID{primitive.NewObjectID()}}.Matches(video))
+		assert.True(t, VideoFilter{Hosts: []string{"vimeo.com", "youtube.com"}}.Matches(video))
+		assert.False(t, VideoFilter{Hosts: []string{"vimeo.com"}}.Matches(video))
+	})
+
+	t.Run("Should match any of the statuses", func(t *testing.T) {
+		assert.True(t, VideoFilter{Statuses: []string{models.DraftStatus, models.PublishedStatus}}.Matches(video))
+		assert.False(t, VideoFilter{Statuses: []string{models.ScheduledStatus}}.Matches(video))
+	})
+
+	t.Run("Should include the start and exclude the end of the creation range", func(t *testing.T) {
+		assert.True(t, VideoFilter{CreatedFrom: createdAt, CreatedBefore: createdAt.Add(time.Second)}.Matches(video))
+		assert.False(t, VideoFilter{CreatedFrom: createdAt.Add(time.Second)}.Matches(video))
+		assert.False(t, VideoFilter{CreatedBefore: createdAt}.Matches(video))
+	})
+
+	t.Run("Should match the folded search", func(t *testing.T) {
+		assert.True(t, VideoFilter{Search: "CONCORRENTE"}.Matches(video))
+		assert.False(t, VideoFilter{Search: "python"}.Matches(video))
+	})
+}
+
+func TestCountFacet(t *testing.T) {
+	t.Run("Should count each value from the most common one", func(t *testing.T) {
+		facet := CountFacet([]string{"vimeo.com", "youtube.com", "alura.com.br", "youtube.com"})
+
+		assert.Equal(t, []FacetCount{{"youtube.com", 2}, {"alura.com.br", 1}, {"vimeo.com", 1}}, facet)
+	})
+
+	t.Run("Should return an empty facet When there are no values", func(t *testing.T) {
+		assert.Equal(t, []FacetCount{}, CountFacet(nil))
+	})
+}
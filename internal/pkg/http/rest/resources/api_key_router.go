@@ -0,0 +1,185 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/apikey"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// apiKeyTouchInterval spares a write per request by only recording the use of
+// a key when it was last recorded longer ago.
+const apiKeyTouchInterval = time.Minute
+
+// Reasons an existing API key is refused.
+var (
+	errAPIKeyRevoked = errors.New("The API key was revoked.")
+	errAPIKeyExpired = errors.New("The API key expired.")
+)
+
+// CreatedAPIKey represents a new API key along with its secret, which is only
+// ever shown in this response
+type CreatedAPIKey struct {
+	models.APIKey
+	Key string `json:"key" example:"afk_Xq3vN2yq2a1cQ5fJ0mH9sW8dT7uR6eY4iO3pL2kJ1hG"`
+}
+
+type APIKeyRouter struct {
+	service interfaces.IAPIKeyService
+}
+
+func ProvideAPIKeyRouter(s interfaces.IAPIKeyService) APIKeyRouter {
+	return APIKeyRouter{s}
+}
+
+// Authenticate authenticates the requests carrying an X-API-Key header with
+// the key, as its subject and with its scopes, and any other with the token
+// middleware checks.
+func (kr *APIKeyRouter) Authenticate(middleware *jwt.Middleware) Authenticator {
+	return func(next http.Handler) http.Handler {
+		withToken := middleware.Handler(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret := r.Header.Get(apikey.Header)
+			if secret == "" {
+				withToken.ServeHTTP(w, r)
+				return
+			}
+			key, err := kr.authenticate(secret, time.Now())
+			switch {
+			case errors.Is(err, interfaces.ErrNotFound):
+				RespondWithError(w, http.StatusUnauthorized, "Invalid API key.")
+				return
+			case errors.Is(err, errAPIKeyRevoked), errors.Is(err, errAPIKeyExpired):
+				RespondWithError(w, http.StatusUnauthorized, err.Error())
+				return
+			case err != nil:
+				RespondWithServiceError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(jwt.WithPrincipal(r.Context(), key.Subject(), key.Scopes)))
+		})
+	}
+}
+
+// authenticate returns the key with the secret when it can be used at now,
+// recording that it was.
+func (kr *APIKeyRouter) authenticate(secret string, now time.Time) (*models.APIKey, error) {
+	key, err := kr.service.GetByHash(apikey.Hash(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, errAPIKeyRevoked
+	}
+	if !now.Before(key.ExpiresAt) {
+		return nil, errAPIKeyExpired
+	}
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err = kr.service.Touch(key.ID, now); err != nil {
+			log.Printf("could not record the use of the API key %s: %v", key.ID.Hex(), err)
+		}
+	}
+	return key, nil
+}
+
+// GetAllAPIKeys godoc
+// @Summary Get all API keys
+// @Description Get every API key, revoked or not, newest first. Keys are only identified by their prefix: their secret is never shown again after they were created.
+// @Tags api-keys
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.APIKey
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires manage:api-keys"
+// @Failure 500 {object} ErrorMessage
+// @Router /api-keys [get]
+func (kr *APIKeyRouter) GetAllAPIKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := kr.service.GetAll()
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+	RespondWithJson(w, http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary Create a new API key
+// @Description Create an API key granting the scopes, which the token creating it must grant too, until expiresAt (90 days without one). The key is sent in the X-API-Key header in place of a token, and is only shown in this response.
+// @Tags api-keys
+// @Accept  json
+// @Produce  json
+// @Param key body dto.InsertAPIKey true "New API key"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 201 {object} CreatedAPIKey
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires manage:api-keys and the scopes granted"
+// @Failure 500 {object} ErrorMessage
+// @Router /api-keys [post]
+func (kr *APIKeyRouter) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var insert dto.InsertAPIKey
+	if err := json.NewDecoder(r.Body).Decode(&insert); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	now := time.Now()
+	if err := insert.Validate(AllPermissions, now); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if missing := missingPermissions(r, insert.Scopes); len(missing) > 0 {
+		RespondWithError(w, http.StatusForbidden, "The token lacks the permissions the key would grant: "+strings.Join(missing, ", ")+".")
+		return
+	}
+	secret, err := apikey.Generate()
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	key, err := kr.service.Create(r.Context(), insert.ConvertToAPIKey(apikey.Prefix(secret), apikey.Hash(secret), now))
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusCreated, CreatedAPIKey{APIKey: *key, Key: secret})
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key by ID
+// @Description Revoke an API key, which is refused from then on. Revoking a key already revoked leaves it as it was.
+// @Tags api-keys
+// @Produce  json
+// @Param id path string true "API key ID"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.APIKey
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires manage:api-keys"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /api-keys/{id} [delete]
+func (kr *APIKeyRouter) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id, _ := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	key, err := kr.service.Revoke(r.Context(), id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusOK, key)
+}
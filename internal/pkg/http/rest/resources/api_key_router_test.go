@@ -0,0 +1,247 @@
+package resources
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/apikey"
+	authjwt "github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// authenticated answers the subject and permissions the request was
+// authenticated with.
+var authenticated = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	RespondWithJson(w, http.StatusOK, map[string]interface{}{
+		"sub":         authjwt.Subject(r.Context()),
+		"permissions": authjwt.Permissions(r.Context()),
+	})
+})
+
+func TestAPIKeyRouter_Authenticate(t *testing.T) {
+	t.Run("Should authenticate the request as the key with its scopes and record its use", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		key := mocked_data.GetValidAPIKey()
+		var touched primitive.ObjectID
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			assert.Equal(t, apikey.Hash(mocked_data.ValidAPIKeySecret), hash)
+			return &key, nil
+		}
+		mocked_services.APIKeyServiceMockTouch = func(id primitive.ObjectID, usedAt time.Time) error {
+			touched = id
+			return nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		r.Header.Set(apikey.Header, mocked_data.ValidAPIKeySecret)
+		w := httptest.NewRecorder()
+
+		router.Authenticate(authjwt.JwtMiddleware)(authenticated).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"sub":"apikey|`+key.ID.Hex()+`","permissions":["read:videos","read:categories"]}`, w.Body.String())
+		assert.Equal(t, key.ID, touched)
+	})
+
+	t.Run("Should not record the use again within the touch interval", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		key := mocked_data.GetValidAPIKey()
+		lastUsedAt := time.Now().Add(-time.Second)
+		key.LastUsedAt = &lastUsedAt
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return &key, nil
+		}
+		mocked_services.APIKeyServiceMockTouch = func(id primitive.ObjectID, usedAt time.Time) error {
+			t.Fatal("the use was recorded again")
+			return nil
+		}
+
+		_, err := router.authenticate(mocked_data.ValidAPIKeySecret, time.Now())
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Should return unauthorized (401) When the key is unknown, revoked or expired", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		revokedAt := time.Now()
+		revoked, expired := mocked_data.GetValidAPIKey(), mocked_data.GetValidAPIKey()
+		revoked.RevokedAt = &revokedAt
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		for expected, found := range map[string]*models.APIKey{
+			`{"error":"Invalid API key."}`:         nil,
+			`{"error":"The API key was revoked."}`: &revoked,
+			`{"error":"The API key expired."}`:     &expired,
+		} {
+			found := found
+			mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+				if found == nil {
+					return nil, interfaces.ErrNotFound
+				}
+				return found, nil
+			}
+			r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+			r.Header.Set(apikey.Header, "afk_unknown")
+			w := httptest.NewRecorder()
+
+			router.Authenticate(authjwt.JwtMiddleware)(authenticated).ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, expected, w.Body.String())
+		}
+	})
+
+	t.Run("Should return internal server error (500) When the key cannot be looked up", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return nil, errors.New("connection refused")
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		r.Header.Set(apikey.Header, "afk_unknown")
+		w := httptest.NewRecorder()
+
+		router.Authenticate(authjwt.JwtMiddleware)(authenticated).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("Should fall back to the token middleware Without an API key", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.Authenticate(authjwt.JwtMiddleware)(authenticated).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestAPIKeyRouter_GetAllAPIKeys(t *testing.T) {
+	t.Run("Should return the keys without their hash and ok (200) status response", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		key := mocked_data.GetValidAPIKey()
+		mocked_services.APIKeyServiceMockGetAll = func() ([]models.APIKey, error) {
+			return []models.APIKey{key}, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/api-keys", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllAPIKeys(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), key.Prefix)
+		assert.NotContains(t, w.Body.String(), key.Hash)
+	})
+
+	t.Run("Should return an empty array When there are no keys", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		mocked_services.APIKeyServiceMockGetAll = func() ([]models.APIKey, error) {
+			return nil, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/api-keys", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllAPIKeys(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "[]", w.Body.String())
+	})
+}
+
+func TestAPIKeyRouter_CreateAPIKey(t *testing.T) {
+	t.Run("Should return the key with its secret and created (201) status response", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		var stored models.APIKey
+		mocked_services.APIKeyServiceMockCreate = func(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+			stored = key
+			return &key, nil
+		}
+		body := `{"name":"Nightly export","scopes":["read:videos"]}`
+		r, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		router.CreateAPIKey(w, withPermissions(r, ReadVideosPermission, ManageAPIKeysPermission))
+
+		var created CreatedAPIKey
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+		assert.Equal(t, apikey.Hash(created.Key), stored.Hash)
+		assert.Equal(t, []string{ReadVideosPermission}, created.Scopes)
+		assert.WithinDuration(t, time.Now().Add(90*24*time.Hour), created.ExpiresAt, time.Minute)
+	})
+
+	t.Run("Should return bad request (400) status response When the key is invalid", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		for body, expected := range map[string]string{
+			`{`:                                   `{"error":"Invalid request payload"}`,
+			`{"scopes":["read:videos"]}`:          `{"error":"name is required."}`,
+			`{"name":"Export","scopes":["root"]}`: `{"error":"scopes must be among ` + strings.Join(AllPermissions[:len(AllPermissions)-1], ", ") + ` or admin:content."}`,
+			`{"name":"Export","scopes":["read:videos"],"expiresAt":"2021-08-14T04:46:49Z"}`: `{"error":"expiresAt must be in the future."}`,
+		} {
+			r, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewBufferString(body))
+			w := httptest.NewRecorder()
+
+			router.CreateAPIKey(w, withPermissions(r, ManageAPIKeysPermission))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, expected, w.Body.String())
+		}
+	})
+
+	t.Run("Should return forbidden (403) status response When the token lacks a scope of the key", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		body := `{"name":"Export","scopes":["read:videos","delete:videos"]}`
+		r, _ := http.NewRequest("POST", "/api/v1/api-keys", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+
+		router.CreateAPIKey(w, withPermissions(r, ReadVideosPermission, ManageAPIKeysPermission))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, `{"error":"The token lacks the permissions the key would grant: delete:videos."}`, w.Body.String())
+	})
+}
+
+func TestAPIKeyRouter_RevokeAPIKey(t *testing.T) {
+	t.Run("Should return the revoked key and ok (200) status response", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		key := mocked_data.GetValidAPIKey()
+		mocked_services.APIKeyServiceMockRevoke = func(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+			revokedAt := time.Now()
+			key.RevokedAt = &revokedAt
+			return &key, nil
+		}
+		r, _ := http.NewRequest("DELETE", "/api/v1/api-keys/"+key.ID.Hex(), nil)
+		r = mux.SetURLVars(r, map[string]string{"id": key.ID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.RevokeAPIKey(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "revokedAt")
+	})
+
+	t.Run("Should return not found (404) status response When the key does not exist", func(t *testing.T) {
+		router := ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		mocked_services.APIKeyServiceMockRevoke = func(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		r, _ := http.NewRequest("DELETE", "/api/v1/api-keys/61186c6fb3b4be6cd6fa8f4f", nil)
+		w := httptest.NewRecorder()
+
+		router.RevokeAPIKey(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
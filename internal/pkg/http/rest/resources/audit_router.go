@@ -0,0 +1,152 @@
+package resources
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditRouter struct {
+	service interfaces.IAuditService
+}
+
+func ProvideAuditRouter(s interfaces.IAuditService) AuditRouter {
+	return AuditRouter{s}
+}
+
+// GetVideoHistory godoc
+// @Summary Get the change history of a video
+// @Description Get a page of the audit entries of a video, newest first, deleted or not. Each entry holds who made the write, when, and the value of every field it changed before and after it.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Video ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.AuditEntry}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /videos/{id}/history [get]
+func (ar *AuditRouter) GetVideoHistory(w http.ResponseWriter, r *http.Request) {
+	ar.respondWithHistory(w, r, models.VideoResource)
+}
+
+// GetCategoryHistory godoc
+// @Summary Get the change history of a category
+// @Description Get a page of the audit entries of a category, newest first, deleted or not. Each entry holds who made the write, when, and the value of every field it changed before and after it.
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Category ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.AuditEntry}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:categories"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /categories/{id}/history [get]
+func (ar *AuditRouter) GetCategoryHistory(w http.ResponseWriter, r *http.Request) {
+	ar.respondWithHistory(w, r, models.CategoryResource)
+}
+
+// GetAuditLog godoc
+// @Summary Get the audit log
+// @Description Get a page of the audit entries of every video and category write, newest first. Entries record creations, updates, deletes and restores, including the videos a category delete removes or moves to FREE.
+// @Tags audit
+// @Accept  json
+// @Produce  json
+// @Param resource query string false "videos or categories"
+// @Param resourceID query string false "ID of the video or category"
+// @Param actor query string false "Subject of the token that made the write"
+// @Param operation query string false "create, update, delete, restore or publish"
+// @Param from query string false "Written on or after this date (2006-01-02) or RFC 3339 time"
+// @Param to query string false "Written on or before this date (2006-01-02) or RFC 3339 time"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.AuditEntry}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:audit"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /audit [get]
+func (ar *AuditRouter) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := getPageParams(r.URL.Query())
+	filter, err := GetAuditQueryParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ar.respondWithEntries(w, r, filter, page, pageSize)
+}
+
+// respondWithHistory lists the entries of the resource named by the id path
+// variable. A malformed id names nothing, rather than no filter at all.
+func (ar *AuditRouter) respondWithHistory(w http.ResponseWriter, r *http.Request, resource string) {
+	page, pageSize := getPageParams(r.URL.Query())
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		RespondWithPage(w, r, http.StatusNotFound, []models.AuditEntry{}, page, pageSize, 0, nil)
+		return
+	}
+	ar.respondWithEntries(w, r, dto.AuditFilter{Resource: resource, ResourceID: id}, page, pageSize)
+}
+
+func (ar *AuditRouter) respondWithEntries(w http.ResponseWriter, r *http.Request, filter dto.AuditFilter, page int64, pageSize int64) {
+	entries, total, err := ar.service.GetAll(filter, page, pageSize)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if entries == nil {
+		RespondWithPage(w, r, http.StatusNotFound, []models.AuditEntry{}, page, pageSize, total, nil)
+		return
+	}
+	RespondWithPage(w, r, http.StatusOK, entries, page, pageSize, total, nil)
+}
+
+// GetAuditQueryParams reads the audit log filter: resource, resourceID, actor
+// and operation, and the from and to bounds, inclusive, given as a date or an
+// RFC 3339 time. Entries are timestamped to the millisecond.
+func GetAuditQueryParams(queryParams url.Values) (filter dto.AuditFilter, err error) {
+	filter.Resource = queryParams.Get("resource")
+	filter.Actor = queryParams.Get("actor")
+	filter.Operation = queryParams.Get("operation")
+	if value := queryParams.Get("resourceID"); value != "" {
+		if filter.ResourceID, err = primitive.ObjectIDFromHex(value); err != nil {
+			return filter, dto.InvalidFieldError("Invalid resourceID " + value + ".")
+		}
+	}
+	if value := queryParams.Get("from"); value != "" {
+		if filter.From, _, err = parseTimeParam("from", value, time.Millisecond); err != nil {
+			return filter, err
+		}
+	}
+	if value := queryParams.Get("to"); value != "" {
+		if _, filter.Before, err = parseTimeParam("to", value, time.Millisecond); err != nil {
+			return filter, err
+		}
+	}
+	return filter, filter.Validate()
+}
@@ -0,0 +1,166 @@
+package resources
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func getValidAuditEntry() models.AuditEntry {
+	return models.AuditEntry{
+		ID:         primitive.NewObjectID(),
+		Resource:   models.VideoResource,
+		ResourceID: primitive.NewObjectID(),
+		Operation:  models.UpdateOperation,
+		Actor:      "auth0|123",
+		Timestamp:  time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC),
+		Changes:    []models.FieldChange{{Field: "titulo", Before: "old", After: "new"}},
+	}
+}
+
+func TestGetVideoHistory(t *testing.T) {
+	t.Run("Should return the entries of the video and ok (200) status response When it has history", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+		entries := []models.AuditEntry{getValidAuditEntry()}
+		pageJson, _ := json.Marshal(Page{entries, 1, 5, 1, 1, nil})
+		id := primitive.NewObjectID()
+		var receivedFilter dto.AuditFilter
+
+		mocked_services.AuditServiceMockGetAll = func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+			receivedFilter = filter
+			return entries, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+id.Hex()+"/history", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": id.Hex()})
+		w := httptest.NewRecorder()
+
+		router.GetVideoHistory(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pageJson, w.Body.Bytes())
+		assert.Equal(t, dto.AuditFilter{Resource: models.VideoResource, ResourceID: id}, receivedFilter)
+	})
+
+	t.Run("Should return not found (404) status response When the id is malformed", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+		mocked_services.AuditServiceMockGetAll = func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+			t.Fatal("the audit log should not be read")
+			return nil, 0, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/foo/history", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": "foo"})
+		w := httptest.NewRecorder()
+
+		router.GetVideoHistory(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetCategoryHistory(t *testing.T) {
+	t.Run("Should return not found (404) status response When the category has no history", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+		id := primitive.NewObjectID()
+		var receivedFilter dto.AuditFilter
+
+		mocked_services.AuditServiceMockGetAll = func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+			receivedFilter = filter
+			return nil, 0, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories/"+id.Hex()+"/history", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": id.Hex()})
+		w := httptest.NewRecorder()
+
+		router.GetCategoryHistory(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, models.CategoryResource, receivedFilter.Resource)
+		assert.Equal(t, id, receivedFilter.ResourceID)
+	})
+}
+
+func TestGetAuditLog(t *testing.T) {
+	t.Run("Should pass the filter and pagination to the service and ok (200) status response When entries match", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+		entries := []models.AuditEntry{getValidAuditEntry()}
+		resourceID := primitive.NewObjectID()
+		var receivedFilter dto.AuditFilter
+		var receivedPage, receivedPageSize int64
+
+		mocked_services.AuditServiceMockGetAll = func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+			receivedFilter, receivedPage, receivedPageSize = filter, page, pageSize
+			return entries, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/audit?resource=videos&resourceID="+resourceID.Hex()+
+			"&actor=auth0%7C123&operation=delete&from=2021-08-01&to=2021-08-14T12:00:00.250Z&page=2&pageSize=10", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAuditLog(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dto.AuditFilter{
+			Resource:   models.VideoResource,
+			ResourceID: resourceID,
+			Actor:      "auth0|123",
+			Operation:  models.DeleteOperation,
+			From:       time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+			Before:     time.Date(2021, 8, 14, 12, 0, 0, 251000000, time.UTC),
+		}, receivedFilter)
+		assert.Equal(t, int64(2), receivedPage)
+		assert.Equal(t, int64(10), receivedPageSize)
+	})
+
+	t.Run("Should return bad request (400) status response When a filter is invalid", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+
+		for query, message := range map[string]string{
+			"resource=users":  "resource must be videos or categories.",
+			"operation=purge": "operation must be create, update, delete, restore or publish.",
+			"resourceID=foo":  "Invalid resourceID foo.",
+			"from=yesterday":  "from must be a date (2006-01-02) or an RFC 3339 time.",
+			"to=2021-13-01":   "to must be a date (2006-01-02) or an RFC 3339 time.",
+		} {
+			r, _ := http.NewRequest("GET", "/api/v1/audit?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.GetAuditLog(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "{\"error\":\""+message+"\"}", w.Body.String())
+		}
+	})
+
+	t.Run("Should return internal server error (500) status response When theres an error", func(t *testing.T) {
+		var router = AuditRouter{}
+		router.service = &mocked_services.AuditServiceMock{}
+		mocked_services.AuditServiceMockGetAll = func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+			return nil, 0, errors.New("audit log unavailable")
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/audit", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAuditLog(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
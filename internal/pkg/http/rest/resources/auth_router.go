@@ -0,0 +1,22 @@
+package resources
+
+import (
+	"net/http"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+)
+
+// GetKeyCacheStats godoc
+// @Summary Get the statistics of the signing key cache
+// @Description Get how the cache of the token signing keys fetched from the jwks.json of the issuer is doing: the lookups served fresh or stale, the misses, the refreshes and their errors, and per issuer the number of keys, when they were refreshed and until when they are kept.
+// @Tags auth
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} jwt.KeyCacheStats
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:stats"
+// @Router /auth/jwks/stats [get]
+func GetKeyCacheStats(w http.ResponseWriter, r *http.Request) {
+	RespondWithJson(w, http.StatusOK, jwt.KeyCacheStatistics())
+}
@@ -0,0 +1,25 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetKeyCacheStats(t *testing.T) {
+	t.Run("Should return the statistics of the key cache and ok (200) status response", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/auth/jwks/stats", nil)
+		w := httptest.NewRecorder()
+
+		GetKeyCacheStats(w, r)
+
+		var stats jwt.KeyCacheStats
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		assert.NotNil(t, stats.Issuers)
+	})
+}
@@ -0,0 +1,81 @@
+package resources
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+)
+
+// Permissions a token grants in its scope or permissions claims, checked per
+// route by Authorize.
+const (
+	ReadVideosPermission       = "read:videos"
+	WriteVideosPermission      = "write:videos"
+	DeleteVideosPermission     = "delete:videos"
+	ReadCategoriesPermission   = "read:categories"
+	WriteCategoriesPermission  = "write:categories"
+	DeleteCategoriesPermission = "delete:categories"
+	ReadAuditPermission        = "read:audit"
+	ReadStatsPermission        = "read:stats"
+	ManageAPIKeysPermission    = "manage:api-keys"
+	ReadUsersPermission        = "read:users"
+	ReadPlaylistsPermission    = "read:playlists"
+	WritePlaylistsPermission   = "write:playlists"
+	DeletePlaylistsPermission  = "delete:playlists"
+	AdminPermission            = domain.AdminPermission
+)
+
+// AllPermissions lists every permission a route can require.
+var AllPermissions = []string{
+	ReadVideosPermission, WriteVideosPermission, DeleteVideosPermission,
+	ReadCategoriesPermission, WriteCategoriesPermission, DeleteCategoriesPermission,
+	ReadAuditPermission, ReadStatsPermission, ManageAPIKeysPermission,
+	ReadUsersPermission, ReadPlaylistsPermission, WritePlaylistsPermission, DeletePlaylistsPermission,
+	AdminPermission,
+}
+
+// Authenticator wraps the handler of a protected route, letting through only
+// the requests it authenticated.
+type Authenticator func(next http.Handler) http.Handler
+
+// Authorize lets the request through to next only when the token validated
+// for it grants every one of the permissions, and answers 403 naming the
+// missing ones otherwise. It runs behind a JWT middleware.
+func Authorize(next http.Handler, permissions ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if missing := missingPermissions(r, permissions); len(missing) > 0 {
+			RespondWithError(w, http.StatusForbidden, "The token lacks the permissions this action requires: "+strings.Join(missing, ", ")+".")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AuthorizeAuthenticated is Authorize for the routes also serving requests
+// without credentials, which it lets through unchecked. It runs behind a
+// middleware with credentials optional, and next only serves the anonymous
+// requests the free content.
+func AuthorizeAuthenticated(next http.Handler, permissions ...string) http.Handler {
+	authorized := Authorize(next, permissions...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !jwt.IsAuthenticated(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		authorized.ServeHTTP(w, r)
+	})
+}
+
+// missingPermissions returns the permissions the token of the request does
+// not grant.
+func missingPermissions(r *http.Request, permissions []string) []string {
+	var missing []string
+	for _, permission := range permissions {
+		if !jwt.HasPermission(r, permission) {
+			missing = append(missing, permission)
+		}
+	}
+	return missing
+}
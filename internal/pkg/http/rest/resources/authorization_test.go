@@ -0,0 +1,71 @@
+package resources
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("Should call the handler When the token grants every permission", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/search", nil)
+		w := httptest.NewRecorder()
+
+		Authorize(next, ReadVideosPermission, ReadCategoriesPermission).
+			ServeHTTP(w, withPermissions(r, ReadVideosPermission, ReadCategoriesPermission))
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Should return forbidden (403) naming the missing permissions When the token lacks any", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/search", nil)
+		w := httptest.NewRecorder()
+
+		Authorize(next, ReadVideosPermission, ReadCategoriesPermission, ReadAuditPermission).
+			ServeHTTP(w, withPermissions(r, ReadVideosPermission))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		assert.Equal(t, []byte(`{"error":"The token lacks the permissions this action requires: read:categories, read:audit."}`), w.Body.Bytes())
+	})
+
+	t.Run("Should return forbidden (403) When the request carries no token", func(t *testing.T) {
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/1", nil)
+		w := httptest.NewRecorder()
+
+		Authorize(next, DeleteCategoriesPermission).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestAuthorizeAuthenticated(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	t.Run("Should call the handler When the request carries no token", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/suggest", nil)
+		w := httptest.NewRecorder()
+
+		AuthorizeAuthenticated(next, ReadVideosPermission).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Should return forbidden (403) When the token lacks any permission", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "/api/v1/suggest", nil)
+		w := httptest.NewRecorder()
+
+		AuthorizeAuthenticated(next, ReadVideosPermission, ReadCategoriesPermission).
+			ServeHTTP(w, withPermissions(r, ReadVideosPermission))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
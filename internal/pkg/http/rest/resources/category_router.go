@@ -3,11 +3,12 @@ package resources
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
-	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,38 +17,73 @@ type CategoryRouter struct {
 	service interfaces.ICategoryService
 }
 
-func ProvideCategoryRouter(s services.CategoryService) CategoryRouter {
-	return CategoryRouter{&s}
+func ProvideCategoryRouter(s interfaces.ICategoryService) CategoryRouter {
+	return CategoryRouter{s}
 }
 
 // GetAllCategories godoc
 // @Summary Get details of all categories
-// @Description Get details of all categories
+// @Description Get a page of categories with the total count. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param search query string false "Search by name"
 // @Param page query int false "Page number"
 // @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort by titulo, cor, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order"
+// @Param after query string false "Cursor returned as nextCursor by the previous page"
+// @Param limit query int false "Page size when paging by cursor"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
 // @Security ApiKeyAuth
-// @Success 200 {array} models.Category
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.Category}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:categories"
 // @Failure 404
 // @Failure 500 {object} ErrorMessage
 // @Router /categories [get]
 func (cs *CategoryRouter) GetAllCategories(w http.ResponseWriter, r *http.Request) {
-	filter, page, pageSize := GetQueryParams(r.URL.Query())
-	categories, err := cs.service.GetAll(filter, page, pageSize)
+	// The other parameters of the filter only narrow video listings.
+	filter, page, pageSize, err := GetQueryParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	after, limit, byCursor, err := GetCursorParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sort, err := GetSortParam(r.URL.Query(), dto.CategorySortFields, after)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if byCursor {
+		categories, next, err := cs.service.GetAllAfter(filter.Search, sort, after, limit)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if categories == nil {
+			RespondWithCursorPage(w, r, http.StatusNotFound, []models.Category{}, limit, nil, nil)
+			return
+		}
+		RespondWithCursorPage(w, r, http.StatusOK, categories, limit, next, nil)
+		return
+	}
+	categories, total, err := cs.service.GetAll(filter.Search, sort, page, pageSize)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if categories == nil {
-		RespondWithJson(w, http.StatusNotFound, []models.Category{})
+		RespondWithPage(w, r, http.StatusNotFound, []models.Category{}, page, pageSize, total, nil)
 		return
 	}
-	RespondWithJson(w, http.StatusOK, categories)
+	RespondWithPage(w, r, http.StatusOK, categories, page, pageSize, total, nil)
 }
 
 // GetCategoryByID godoc
@@ -57,10 +93,15 @@ func (cs *CategoryRouter) GetAllCategories(w http.ResponseWriter, r *http.Reques
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Category ID"
+// @Param If-None-Match header string false "ETag of a cached version"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Version of the category"
+// @Success 304
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:categories"
 // @Failure 404
 // @Failure 500 {object} ErrorMessage
 // @Router /categories/{id} [get]
@@ -72,6 +113,10 @@ func (cs *CategoryRouter) GetCategoryByID(w http.ResponseWriter, r *http.Request
 		RespondWithJson(w, http.StatusNotFound, nil)
 		return
 	}
+	if RespondNotModified(w, r, category.Version) {
+		return
+	}
+	SetETag(w, category.Version)
 	RespondWithJson(w, http.StatusOK, category)
 }
 
@@ -83,9 +128,12 @@ func (cs *CategoryRouter) GetCategoryByID(w http.ResponseWriter, r *http.Request
 // @Produce  json
 // @Param category body dto.InsertCategory true "New category"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 201 {object} models.Category
+// @Header 201 {string} ETag "Version of the category"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:categories"
 // @Failure 500 {object} ErrorMessage
 // @Router /categories [post]
 func (cs *CategoryRouter) CreateCategory(w http.ResponseWriter, r *http.Request) {
@@ -100,27 +148,34 @@ func (cs *CategoryRouter) CreateCategory(w http.ResponseWriter, r *http.Request)
 		RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	insertedVideo, err := cs.service.Create(category)
+	insertedCategory, err := cs.service.Create(r.Context(), category)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		RespondWithServiceError(w, err)
 		return
 	}
-	RespondWithJson(w, http.StatusCreated, insertedVideo)
+	SetETag(w, insertedCategory.Version)
+	RespondWithJson(w, http.StatusCreated, insertedCategory)
 }
 
 // UpdateCategoryByID godoc
 // @Summary Update a category by ID
-// @Description Update a category by ID
+// @Description Update a category by ID. The FREE category cannot be renamed.
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being changed"
 // @Param category body dto.InsertCategory true "New category values"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Version of the category"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:categories, and to own the category or admin:content"
 // @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
 // @Failure 500 {object} ErrorMessage
 // @Router /categories [put]
 func (cs *CategoryRouter) UpdateCategoryByID(w http.ResponseWriter, r *http.Request) {
@@ -137,34 +192,90 @@ func (cs *CategoryRouter) UpdateCategoryByID(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	id, _ := primitive.ObjectIDFromHex(params["id"])
-	updatedCategory, err := cs.service.Update(id, category)
-
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	updatedCategory, err := cs.service.Update(r.Context(), id, category, version)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		RespondWithServiceError(w, err)
 		return
 	}
+	SetETag(w, updatedCategory.Version)
 	RespondWithJson(w, http.StatusOK, updatedCategory)
 }
 
+// PatchCategoryByID godoc
+// @Summary Partially update a category by ID
+// @Description Apply a JSON Merge Patch (RFC 7396) to a category. Only the fields present are validated and changed. The FREE category cannot be renamed.
+// @Tags categories
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param category body dto.PatchCategory true "Fields to change"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Version of the category"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:categories, and to own the category or admin:content"
+// @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
+// @Failure 415 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /categories/{id} [patch]
+func (cs *CategoryRouter) PatchCategoryByID(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	params := mux.Vars(r)
+	var patch dto.PatchCategory
+	if !DecodeMergePatch(w, r, &patch) {
+		return
+	}
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	patchedCategory, err := cs.service.Patch(r.Context(), id, patch, version)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, patchedCategory.Version)
+	RespondWithJson(w, http.StatusOK, patchedCategory)
+}
+
 // DeleteCategoryByID godoc
 // @Summary Delete a category by ID
-// @Description Delete a category by ID
+// @Description Delete a category by ID. Its videos are handled according to CATEGORY_DELETE_POLICY (reject, cascade or reassign), and the FREE category cannot be deleted.
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Category ID"
+// @Param If-Match header string false "ETag of the version being changed"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires delete:categories, and to own the category or admin:content"
 // @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
 // @Failure 500 {object} ErrorMessage
 // @Router /categories [delete]
 func (cs *CategoryRouter) DeleteCategoryByID(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, _ := primitive.ObjectIDFromHex(params["id"])
-	if err := cs.service.Delete(id); err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	if err := cs.service.Delete(r.Context(), id, version); err != nil {
+		RespondWithServiceError(w, err)
 		return
 	}
 	RespondWithJson(w, http.StatusNoContent, nil)
@@ -172,22 +283,44 @@ func (cs *CategoryRouter) DeleteCategoryByID(w http.ResponseWriter, r *http.Requ
 
 // GetAllVideosByCategoryID godoc
 // @Summary Get all videos by category ID
-// @Description Get all videos by category ID
+// @Description Get all videos by category ID, leaving out the ones not published yet unless the token grants write:videos. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Category ID"
+// @Param after query string false "Cursor returned as nextCursor by the previous page"
+// @Param limit query int false "Page size when paging by cursor"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {array} models.Video
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
 // @Failure 404
 // @Failure 500 {object} ErrorMessage
 // @Router /categories/{id}/videos [get]
 func (cs *CategoryRouter) GetAllVideosByCategoryID(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, _ := primitive.ObjectIDFromHex(params["id"])
-	videos, err := cs.service.GetVideosByCategoryId(id)
+	after, limit, byCursor, err := GetCursorParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if byCursor {
+		videos, next, err := cs.service.GetVideosByCategoryIdAfter(id, !IsEditor(r), after, limit)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if videos == nil {
+			RespondWithCursorPage(w, r, http.StatusNotFound, []models.Video{}, limit, nil, nil)
+			return
+		}
+		RespondWithCursorPage(w, r, http.StatusOK, videos, limit, next, nil)
+		return
+	}
+	videos, err := cs.service.GetVideosByCategoryId(id, !IsEditor(r))
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -198,3 +331,162 @@ func (cs *CategoryRouter) GetAllVideosByCategoryID(w http.ResponseWriter, r *htt
 	}
 	RespondWithJson(w, http.StatusOK, videos)
 }
+
+// GetDeletedCategories godoc
+// @Summary Get all deleted categories
+// @Description Get the categories in the trash, most recently deleted first
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Category
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:categories"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/categories [get]
+func (cs *CategoryRouter) GetDeletedCategories(w http.ResponseWriter, r *http.Request) {
+	_, page, pageSize, err := GetQueryParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	categories, err := cs.service.GetDeleted(page, pageSize)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if categories == nil {
+		RespondWithJson(w, http.StatusNotFound, []models.Category{})
+		return
+	}
+	RespondWithJson(w, http.StatusOK, categories)
+}
+
+// RestoreCategoryByID godoc
+// @Summary Restore a deleted category by ID
+// @Description Restore a deleted category by ID
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Version of the category"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:categories, and to own the category or admin:content"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/categories/{id}/restore [post]
+func (cs *CategoryRouter) RestoreCategoryByID(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	category, err := cs.service.Restore(r.Context(), id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, category.Version)
+	RespondWithJson(w, http.StatusOK, category)
+}
+
+// PurgeDeletedCategories godoc
+// @Summary Purge deleted categories
+// @Description Permanently remove the categories deleted before the trash retention period (TRASH_RETENTION_DAYS). Categories still referenced by videos are kept. Only admin:content purges the categories of every user, others purge their own.
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} PurgeResult
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires delete:categories"
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/categories [delete]
+func (cs *CategoryRouter) PurgeDeletedCategories(w http.ResponseWriter, r *http.Request) {
+	purged, err := cs.service.Purge(r.Context(), time.Now().Add(-GetTrashRetention()))
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusOK, PurgeResult{purged})
+}
+
+// GetCategoryRevisions godoc
+// @Summary Get the revisions of a category
+// @Description Get the versions of a category replaced by its updates, newest first. Each revision is the version it was.
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.CategoryRevision
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:categories"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /categories/{id}/revisions [get]
+func (cr *CategoryRouter) GetCategoryRevisions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	revisions, err := cr.service.GetRevisions(id)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(revisions) == 0 {
+		RespondWithJson(w, http.StatusNotFound, []models.CategoryRevision{})
+		return
+	}
+	RespondWithJson(w, http.StatusOK, revisions)
+}
+
+// RestoreCategoryRevision godoc
+// @Summary Restore a category to one of its revisions
+// @Description Update a category back to the values it had at a revision, with the same validation as any other update. The restore keeps the replaced version as a new revision.
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param rev path int true "Revision"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Category
+// @Header 200 {string} ETag "Version of the category"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:categories, and to own the category or admin:content"
+// @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
+// @Failure 422 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /categories/{id}/revisions/{rev}/restore [post]
+func (cr *CategoryRouter) RestoreCategoryRevision(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	revision, err := strconv.ParseInt(params["rev"], 10, 64)
+	if err != nil {
+		RespondWithJson(w, http.StatusNotFound, nil)
+		return
+	}
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	restored, err := cr.service.RestoreRevision(r.Context(), id, revision, version)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, restored.Version)
+	RespondWithJson(w, http.StatusOK, restored)
+}
@@ -2,16 +2,21 @@ package resources
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -22,10 +27,10 @@ func TestGetAllCategories(t *testing.T) {
 
 		router.service = &mocked_services.CategoryServiceMock{}
 		categoryArray := []models.Category{*mocked_data.GetValidCategory()}
-		categoryArrayJson, _ := json.Marshal(categoryArray)
+		pageJson, _ := json.Marshal(Page{categoryArray, 1, 5, 1, 1, nil})
 
-		mocked_services.CategoryServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Category, error) {
-			return categoryArray, nil
+		mocked_services.CategoryServiceMockGetAll = func(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+			return categoryArray, 1, nil
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/category", nil)
@@ -34,15 +39,41 @@ func TestGetAllCategories(t *testing.T) {
 		router.GetAllCategories(w, r)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Equal(t, categoryArrayJson, w.Body.Bytes())
+		assert.Equal(t, pageJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return bad request (400) status response When the field is not sortable", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories?sort=descricao", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllCategories(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Cannot sort by descricao. Sortable fields: titulo, cor, created, createdAt, updatedAt, createdBy, updatedBy.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return bad request (400) status response When a query param is invalid", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories?active=maybe", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllCategories(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"active must be true or false.\"}"), w.Body.Bytes())
 	})
 
 	t.Run("Should return error and internal server error (500) status response When theres an error", func(t *testing.T) {
 		var router = CategoryRouter{}
 		router.service = &mocked_services.CategoryServiceMock{}
 
-		mocked_services.CategoryServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Category, error) {
-			return nil, errors.New("Error test")
+		mocked_services.CategoryServiceMockGetAll = func(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+			return nil, 0, errors.New("Error test")
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/category", nil)
@@ -58,8 +89,8 @@ func TestGetAllCategories(t *testing.T) {
 		var router = CategoryRouter{}
 		router.service = &mocked_services.CategoryServiceMock{}
 
-		mocked_services.CategoryServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Category, error) {
-			return nil, nil
+		mocked_services.CategoryServiceMockGetAll = func(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+			return nil, 0, nil
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/category", nil)
@@ -68,7 +99,27 @@ func TestGetAllCategories(t *testing.T) {
 		router.GetAllCategories(w, r)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+		assert.Equal(t, []byte(`{"items":[],"page":1,"pageSize":5,"total":0,"totalPages":0}`), w.Body.Bytes())
+	})
+
+	t.Run("Should return the bare category array When the client accepts it", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		categoryArray := []models.Category{*mocked_data.GetValidCategory()}
+		categoryArrayJson, _ := json.Marshal(categoryArray)
+
+		mocked_services.CategoryServiceMockGetAll = func(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+			return categoryArray, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories", nil)
+		r.Header.Set("Accept", ArrayMediaType)
+		w := httptest.NewRecorder()
+
+		router.GetAllCategories(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, categoryArrayJson, w.Body.Bytes())
 	})
 }
 
@@ -109,6 +160,26 @@ func TestGetCategoryByID(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, categoryJson, w.Body.Bytes())
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Should return not modified (304) status response when If-None-Match holds the current version", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		category := mocked_data.GetValidCategory()
+
+		mocked_services.CategoryServiceMockGetByID = func(id primitive.ObjectID) (*models.Category, error) {
+			return category, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories/"+category.ID.Hex(), nil)
+		r.Header.Set("If-None-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.GetCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.Bytes())
 	})
 }
 
@@ -156,6 +227,28 @@ func TestCreateCategory(t *testing.T) {
 		assert.Equal(t, []byte("{\"error\":\"There's an error\"}"), w.Body.Bytes())
 	})
 
+	t.Run("Should map the service error to its status response When the creation is refused", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		categoryDtoJson, _ := json.Marshal(mocked_data.GetValidInsertCategoryDto())
+
+		for err, status := range map[error]int{
+			dto.InvalidFieldError("Cor is invalid."): http.StatusBadRequest,
+			interfaces.ErrNotOwner:                   http.StatusForbidden,
+		} {
+			refused := err
+			mocked_services.CategoryServiceMockCreate = func(insertCategory dto.InsertCategory) (*models.Category, error) {
+				return nil, refused
+			}
+			r, _ := http.NewRequest("POST", "/api/v1/categories", bytes.NewReader(categoryDtoJson))
+			w := httptest.NewRecorder()
+
+			router.CreateCategory(w, r)
+
+			assert.Equal(t, status, w.Code, err.Error())
+		}
+	})
+
 	t.Run("Should return created category and created (201) status response when payload is ok", func(t *testing.T) {
 		var router = CategoryRouter{}
 		router.service = &mocked_services.CategoryServiceMock{}
@@ -213,7 +306,7 @@ func TestUpdateCategoryByID(t *testing.T) {
 		r, _ := http.NewRequest("PUT", "/api/v1/categories"+primitive.NewObjectID().Hex(), bytes.NewReader(categoryDtoJson))
 		w := httptest.NewRecorder()
 
-		mocked_services.CategoryServiceMockUpdate = func(id primitive.ObjectID, insertCategory dto.InsertCategory) (*models.Category, error) {
+		mocked_services.CategoryServiceMockUpdate = func(id primitive.ObjectID, insertCategory dto.InsertCategory, version int64) (*models.Category, error) {
 			return nil, errors.New("There's an error")
 		}
 
@@ -234,7 +327,7 @@ func TestUpdateCategoryByID(t *testing.T) {
 		r, _ := http.NewRequest("PUT", "/api/v1/categories"+primitive.NewObjectID().Hex(), bytes.NewReader(categoryDtoJson))
 		w := httptest.NewRecorder()
 
-		mocked_services.CategoryServiceMockUpdate = func(id primitive.ObjectID, insertCategory dto.InsertCategory) (*models.Category, error) {
+		mocked_services.CategoryServiceMockUpdate = func(id primitive.ObjectID, insertCategory dto.InsertCategory, version int64) (*models.Category, error) {
 			return categoryModel, nil
 		}
 
@@ -254,7 +347,7 @@ func TestDeleteCategoryByID(t *testing.T) {
 		r, _ := http.NewRequest("DELETE", "/api/v1/categories/"+primitive.NewObjectID().Hex(), nil)
 		w := httptest.NewRecorder()
 
-		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID) error {
+		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID, version int64) error {
 			return errors.New("There's an error")
 		}
 
@@ -271,7 +364,7 @@ func TestDeleteCategoryByID(t *testing.T) {
 		r, _ := http.NewRequest("DELETE", "/api/v1/categories/"+primitive.NewObjectID().Hex(), nil)
 		w := httptest.NewRecorder()
 
-		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID) error {
+		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID, version int64) error {
 			return nil
 		}
 
@@ -280,6 +373,56 @@ func TestDeleteCategoryByID(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, w.Code)
 		assert.Nil(t, w.Body.Bytes())
 	})
+
+	t.Run("Should return conflict (409) status response when the category still has videos", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/"+primitive.NewObjectID().Hex(), nil)
+		w := httptest.NewRecorder()
+
+		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			return interfaces.ErrCategoryHasVideos
+		}
+
+		router.DeleteCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"category still has videos\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return conflict (409) status response when deleting the free category", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/"+primitive.NilObjectID.Hex(), nil)
+		w := httptest.NewRecorder()
+
+		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			return interfaces.ErrProtectedCategory
+		}
+
+		router.DeleteCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Should return precondition failed (412) status response when the If-Match version is stale", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/"+primitive.NewObjectID().Hex(), nil)
+		r.Header.Set("If-Match", `"2"`)
+		w := httptest.NewRecorder()
+		var received int64
+
+		mocked_services.CategoryServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			received = version
+			return interfaces.ErrVersionMismatch
+		}
+
+		router.DeleteCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.Equal(t, int64(2), received)
+	})
 }
 
 func TestGetAllVideosByCategoryID(t *testing.T) {
@@ -289,7 +432,7 @@ func TestGetAllVideosByCategoryID(t *testing.T) {
 		videosArray := []models.Video{*mocked_data.GetValidVideo()}
 		videosArrayJson, _ := json.Marshal(videosArray)
 
-		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID) ([]models.Video, error) {
+		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
 			return videosArray, nil
 		}
 
@@ -306,7 +449,7 @@ func TestGetAllVideosByCategoryID(t *testing.T) {
 		var router = CategoryRouter{}
 		router.service = &mocked_services.CategoryServiceMock{}
 
-		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID) ([]models.Video, error) {
+		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
 			return nil, nil
 		}
 
@@ -323,7 +466,7 @@ func TestGetAllVideosByCategoryID(t *testing.T) {
 		var router = CategoryRouter{}
 		router.service = &mocked_services.CategoryServiceMock{}
 
-		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID) ([]models.Video, error) {
+		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
 			return nil, errors.New("Error test")
 		}
 
@@ -337,3 +480,349 @@ func TestGetAllVideosByCategoryID(t *testing.T) {
 
 	})
 }
+
+func TestGetAllVideosByCategoryIDPublication(t *testing.T) {
+	t.Run("Should ask for the published videos only When the token does not grant write:videos", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
+			assert.True(t, publishedOnly)
+			return []models.Video{*mocked_data.GetValidVideo()}, nil
+		}
+		mocked_services.CategoryServiceMockGetVideosByCategoryIdAfter = func(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+			assert.True(t, publishedOnly)
+			return []models.Video{*mocked_data.GetValidVideo()}, nil, nil
+		}
+
+		for _, url := range []string{"/videos", "/videos?limit=5"} {
+			r, _ := http.NewRequest("GET", "/api/v1/category/"+primitive.NewObjectID().Hex()+url, nil)
+			w := httptest.NewRecorder()
+
+			router.GetAllVideosByCategoryID(w, r)
+
+			assert.Equal(t, http.StatusOK, w.Code, url)
+		}
+	})
+
+	t.Run("Should ask for every video When the token grants write:videos", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockGetVideosByCategoryId = func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
+			assert.False(t, publishedOnly)
+			return []models.Video{*mocked_data.GetValidVideo()}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/category/"+primitive.NewObjectID().Hex()+"/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideosByCategoryID(w, withPermissions(r, EditorPermission))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestGetAllVideosByCategoryIDByCursor(t *testing.T) {
+	t.Run("Should return the last page without a next cursor", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		video := mocked_data.GetValidVideo()
+		pageJson, _ := json.Marshal(CursorPage{Items: []models.Video{*video}, Limit: 5})
+
+		mocked_services.CategoryServiceMockGetVideosByCategoryIdAfter = func(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+			return []models.Video{*video}, nil, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories/"+primitive.NewObjectID().Hex()+"/videos?limit=5", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideosByCategoryID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pageJson, w.Body.Bytes())
+		assert.Empty(t, w.Header().Get("Link"))
+	})
+}
+
+func TestGetDeletedCategories(t *testing.T) {
+	t.Run("Should return deleted categories array and ok (200) status response when theres items in the trash", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		array := []models.Category{*mocked_data.GetValidCategory()}
+		arrayJson, _ := json.Marshal(array)
+
+		mocked_services.CategoryServiceMockGetDeleted = func(page int64, pageSize int64) ([]models.Category, error) {
+			return array, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/trash/categories", nil)
+		w := httptest.NewRecorder()
+
+		router.GetDeletedCategories(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, arrayJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return empty array and not found (404) status response when the trash is empty", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockGetDeleted = func(page int64, pageSize int64) ([]models.Category, error) {
+			return nil, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/trash/categories", nil)
+		w := httptest.NewRecorder()
+
+		router.GetDeletedCategories(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+	})
+
+	t.Run("Should return bad request (400) status response When a query param is invalid", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/trash/categories?status=live", nil)
+		w := httptest.NewRecorder()
+
+		router.GetDeletedCategories(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"status must be draft, scheduled, published or unpublished.\"}"), w.Body.Bytes())
+	})
+}
+
+func TestRestoreCategory(t *testing.T) {
+	t.Run("Should return the restored category and ok (200) status response when the item is in the trash", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		model := mocked_data.GetValidCategory()
+		modelJson, _ := json.Marshal(model)
+
+		mocked_services.CategoryServiceMockRestore = func(id primitive.ObjectID) (*models.Category, error) {
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/categories/"+model.ID.Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, modelJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return not found (404) status response when the item is not in the trash", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockRestore = func(id primitive.ObjectID) (*models.Category, error) {
+			return nil, interfaces.ErrNotFound
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/categories/"+primitive.NewObjectID().Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Should return forbidden (403) status response when the category belongs to another user", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockRestore = func(id primitive.ObjectID) (*models.Category, error) {
+			return nil, interfaces.ErrNotOwner
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/categories/"+primitive.NewObjectID().Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestCategoryRevisions(t *testing.T) {
+	t.Run("Should return the revisions and ok (200) status response when the category has revisions", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		model := mocked_data.GetValidCategory()
+		revisions := []models.CategoryRevision{{Revision: model.Version, Category: *model}}
+		revisionsJson, _ := json.Marshal(revisions)
+		var received primitive.ObjectID
+
+		mocked_services.CategoryServiceMockGetRevisions = func(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+			received = id
+			return revisions, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories/"+model.ID.Hex()+"/revisions", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": model.ID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.GetCategoryRevisions(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, model.ID, received)
+		assert.Equal(t, revisionsJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return empty array and not found (404) status response when the category has no revisions", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockGetRevisions = func(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+			return []models.CategoryRevision{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/categories/"+primitive.NewObjectID().Hex()+"/revisions", nil)
+		w := httptest.NewRecorder()
+
+		router.GetCategoryRevisions(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+	})
+}
+
+func TestRestoreCategoryRevision(t *testing.T) {
+	t.Run("Should return the restored category and ok (200) status response when the revision exists", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		model := mocked_data.GetValidCategory()
+		var receivedRevision, receivedVersion int64
+
+		mocked_services.CategoryServiceMockRestoreRevision = func(id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+			receivedRevision, receivedVersion = revision, version
+			model.Version = version + 1
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/categories/"+model.ID.Hex()+"/revisions/2/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": model.ID.Hex(), "rev": "2"})
+		r.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryRevision(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(2), receivedRevision)
+		assert.Equal(t, int64(3), receivedVersion)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Should return not found (404) status response when the revision is not a number", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("POST", "/api/v1/categories/"+primitive.NewObjectID().Hex()+"/revisions/foo/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": primitive.NewObjectID().Hex(), "rev": "foo"})
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryRevision(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Should return precondition failed (412) status response when the version is stale", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		mocked_services.CategoryServiceMockRestoreRevision = func(id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+			return nil, interfaces.ErrVersionMismatch
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/categories/"+primitive.NewObjectID().Hex()+"/revisions/1/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"rev": "1"})
+		r.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.RestoreCategoryRevision(w, r)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+}
+
+func TestPurgeDeletedCategories(t *testing.T) {
+	t.Run("Should purge items older than TRASH_RETENTION_DAYS and return how many were removed", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		os.Setenv("TRASH_RETENTION_DAYS", "7")
+		defer os.Unsetenv("TRASH_RETENTION_DAYS")
+		var cutoff time.Time
+
+		mocked_services.CategoryServiceMockPurge = func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+			cutoff = deletedBefore
+			return 3, nil
+		}
+
+		r, _ := http.NewRequest("DELETE", "/api/v1/trash/categories", nil)
+		w := httptest.NewRecorder()
+
+		router.PurgeDeletedCategories(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []byte("{\"purged\":3}"), w.Body.Bytes())
+		assert.WithinDuration(t, time.Now().Add(-7*24*time.Hour), cutoff, time.Minute)
+	})
+}
+
+func TestPatchCategoryByID(t *testing.T) {
+	t.Run("Should return the patched category and ok (200) status response when the patch is valid", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+		model := mocked_data.GetValidCategory()
+		modelJson, _ := json.Marshal(model)
+		var received dto.PatchCategory
+
+		mocked_services.CategoryServiceMockPatch = func(id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error) {
+			received = patch
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/categories/"+model.ID.Hex(), bytes.NewReader([]byte(`{"cor":"Green"}`)))
+		r.Header.Set("Content-Type", MergePatchContentType)
+		w := httptest.NewRecorder()
+
+		router.PatchCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, modelJson, w.Body.Bytes())
+		assert.Equal(t, "Green", *received.Cor)
+		assert.Nil(t, received.Titulo)
+	})
+
+	t.Run("Should return bad request (400) status response when removing a required field", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/categories/"+primitive.NewObjectID().Hex(), bytes.NewReader([]byte(`{"titulo":null}`)))
+		r.Header.Set("Content-Type", MergePatchContentType)
+		w := httptest.NewRecorder()
+
+		router.PatchCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Titulo cannot be removed.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return unsupported media type (415) status response when the body is not a merge patch", func(t *testing.T) {
+		var router = CategoryRouter{}
+		router.service = &mocked_services.CategoryServiceMock{}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/categories/"+primitive.NewObjectID().Hex(), bytes.NewReader([]byte(`{"cor":"Green"}`)))
+		r.Header.Set("Content-Type", "application/xml")
+		w := httptest.NewRecorder()
+
+		router.PatchCategoryByID(w, r)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+}
@@ -0,0 +1,63 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+)
+
+// DevRouter serves the development issuer, which only exists when ENV is dev.
+type DevRouter struct {
+	issuer *jwt.DevIssuer
+}
+
+func ProvideDevRouter(issuer *jwt.DevIssuer) DevRouter {
+	return DevRouter{issuer: issuer}
+}
+
+// Enabled tells whether there is a development issuer to serve.
+func (dr *DevRouter) Enabled() bool {
+	return dr.issuer != nil
+}
+
+// GetJWKS serves the key set of the development issuer. It is left out of the
+// Swagger documentation, which only covers the routes under /api/v1.
+func (dr *DevRouter) GetJWKS(w http.ResponseWriter, r *http.Request) {
+	RespondWithJson(w, http.StatusOK, dr.issuer.JWKS())
+}
+
+// CreateDevToken godoc
+// @Summary Mint a development token
+// @Description Mint a token of the development issuer for the sub and scopes asked for, valid for expiresIn seconds (up to a day). Without a body it is valid for an hour, for the dev|aluraflix subject, and grants every permission. Its public key is served at /.well-known/jwks.json. Only served when ENV is dev.
+// @Tags dev
+// @Accept  json
+// @Produce  json
+// @Param request body dto.DevTokenRequest false "Subject, scopes and lifetime of the token"
+// @Success 200 {object} dto.DevToken
+// @Failure 400 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /dev/token [post]
+func (dr *DevRouter) CreateDevToken(w http.ResponseWriter, r *http.Request) {
+	var request dto.DevTokenRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		defer r.Body.Close()
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+			return
+		}
+	}
+	request.Normalize(AllPermissions)
+	if err := request.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	token, err := dr.issuer.Mint(request.Subject, request.Scopes, time.Duration(request.ExpiresIn)*time.Second)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	RespondWithJson(w, http.StatusOK, dto.DevToken{AccessToken: token, TokenType: "Bearer", ExpiresIn: request.ExpiresIn})
+}
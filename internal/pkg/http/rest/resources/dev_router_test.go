@@ -0,0 +1,109 @@
+package resources
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	authjwt "github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDevRouter(t *testing.T) DevRouter {
+	key, _ := rsa.GenerateKey(rand.Reader, 1024)
+	issuer, err := authjwt.NewDevIssuer("http://localhost:3000/", "", key)
+	assert.Nil(t, err)
+	return ProvideDevRouter(issuer)
+}
+
+func TestDevRouter_Enabled(t *testing.T) {
+	t.Run("Should only be enabled with an issuer", func(t *testing.T) {
+		disabled := ProvideDevRouter(nil)
+		enabled := newTestDevRouter(t)
+
+		assert.False(t, disabled.Enabled())
+		assert.True(t, enabled.Enabled())
+	})
+}
+
+func TestDevRouter_GetJWKS(t *testing.T) {
+	t.Run("Should return the key set of the issuer and ok (200) status response", func(t *testing.T) {
+		router := newTestDevRouter(t)
+		r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+
+		router.GetJWKS(w, r)
+
+		var jwks authjwt.Jwks
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &jwks))
+		assert.Len(t, jwks.Keys, 1)
+	})
+}
+
+func TestDevRouter_CreateDevToken(t *testing.T) {
+	t.Run("Should mint a token for the subject and scopes asked for and ok (200) status response", func(t *testing.T) {
+		router := newTestDevRouter(t)
+		body, _ := json.Marshal(dto.DevTokenRequest{Subject: "dev|alice", Scopes: []string{ReadVideosPermission}, ExpiresIn: 60})
+		r, _ := http.NewRequest("POST", "/api/v1/dev/token", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		router.CreateDevToken(w, r)
+
+		var token dto.DevToken
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &token))
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, int64(60), token.ExpiresIn)
+		claims := jwt.MapClaims{}
+		_, _, err := new(jwt.Parser).ParseUnverified(token.AccessToken, claims)
+		assert.Nil(t, err)
+		assert.Equal(t, "dev|alice", claims["sub"])
+		assert.Equal(t, ReadVideosPermission, claims["scope"])
+	})
+
+	t.Run("Should grant every permission without a body", func(t *testing.T) {
+		router := newTestDevRouter(t)
+		r, _ := http.NewRequest("POST", "/api/v1/dev/token", nil)
+		w := httptest.NewRecorder()
+
+		router.CreateDevToken(w, r)
+
+		var token dto.DevToken
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &token))
+		claims := jwt.MapClaims{}
+		new(jwt.Parser).ParseUnverified(token.AccessToken, claims)
+		assert.Equal(t, dto.DefaultDevTokenSubject, claims["sub"])
+		for _, permission := range AllPermissions {
+			assert.Contains(t, claims["scope"], permission)
+		}
+	})
+
+	t.Run("Should return bad request (400) status response when the payload is invalid", func(t *testing.T) {
+		router := newTestDevRouter(t)
+		r, _ := http.NewRequest("POST", "/api/v1/dev/token", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+
+		router.CreateDevToken(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Should return bad request (400) status response when expiresIn is out of range", func(t *testing.T) {
+		router := newTestDevRouter(t)
+		r, _ := http.NewRequest("POST", "/api/v1/dev/token", bytes.NewBufferString(`{"expiresIn":90000}`))
+		w := httptest.NewRecorder()
+
+		router.CreateDevToken(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"error":"expiresIn must be between 1 and 86400 seconds."}`, w.Body.String())
+	})
+}
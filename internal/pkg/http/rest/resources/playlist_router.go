@@ -0,0 +1,337 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PlaylistRouter struct {
+	service interfaces.IPlaylistService
+}
+
+func ProvidePlaylistRouter(s interfaces.IPlaylistService) PlaylistRouter {
+	return PlaylistRouter{s}
+}
+
+// GetAllPlaylists godoc
+// @Summary Get all playlists
+// @Description Get a page of the playlists the token may see, in creation order: the public ones and its own, or every one with admin:content. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead.
+// @Tags playlists
+// @Produce  json
+// @Param owner query string false "Only the playlists of the user with this ID"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.Playlist}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:playlists"
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists [get]
+func (pr *PlaylistRouter) GetAllPlaylists(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := getPageParams(r.URL.Query())
+	filter := domain.VisiblePlaylists(r.Context())
+	filter.OwnerID = r.URL.Query().Get("owner")
+	playlists, total, err := pr.service.GetAll(filter, page, pageSize)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if playlists == nil {
+		playlists = []models.Playlist{}
+	}
+	RespondWithPage(w, r, http.StatusOK, playlists, page, pageSize, total, nil)
+}
+
+// GetPlaylistByID godoc
+// @Summary Get details of a playlist by ID
+// @Description Get details of a playlist by ID, with its videos in order. A private playlist is only found by its owner and admin:content.
+// @Tags playlists
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param If-None-Match header string false "ETag of a cached version"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Playlist
+// @Header 200 {string} ETag "Version of the playlist"
+// @Success 304
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:playlists"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id} [get]
+func (pr *PlaylistRouter) GetPlaylistByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	playlist, err := pr.service.GetByID(id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	if !domain.CanView(r.Context(), *playlist) {
+		RespondWithJson(w, http.StatusNotFound, nil)
+		return
+	}
+	if RespondNotModified(w, r, playlist.Version) {
+		return
+	}
+	SetETag(w, playlist.Version)
+	RespondWithJson(w, http.StatusOK, playlist)
+}
+
+// CreatePlaylist godoc
+// @Summary Create a new playlist
+// @Description Create an empty playlist owned by the token subject. The visibility is public or private, private when left out.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param playlist body dto.InsertPlaylist true "New playlist"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 201 {object} models.Playlist
+// @Header 201 {string} ETag "Version of the playlist"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:playlists"
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists [post]
+func (pr *PlaylistRouter) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var playlist dto.InsertPlaylist
+	if err := json.NewDecoder(r.Body).Decode(&playlist); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	created, err := pr.service.Create(r.Context(), playlist)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, created.Version)
+	RespondWithJson(w, http.StatusCreated, created)
+}
+
+// UpdatePlaylistByID godoc
+// @Summary Update a playlist by ID
+// @Description Update the title, description and visibility of a playlist by ID, keeping its videos.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param playlist body dto.InsertPlaylist true "New playlist values"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Playlist
+// @Header 200 {string} ETag "Version of the playlist"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:playlists, and to own the playlist or admin:content"
+// @Failure 404
+// @Failure 412 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id} [put]
+func (pr *PlaylistRouter) UpdatePlaylistByID(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var playlist dto.InsertPlaylist
+	if err := json.NewDecoder(r.Body).Decode(&playlist); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	id, _ := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	pr.respondWithPlaylist(w, r, id, func() (*models.Playlist, error) {
+		return pr.service.Update(r.Context(), id, playlist, version)
+	})
+}
+
+// DeletePlaylistByID godoc
+// @Summary Delete a playlist by ID
+// @Description Delete a playlist by ID for good. Its videos are left as they are.
+// @Tags playlists
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 204
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires delete:playlists, and to own the playlist or admin:content"
+// @Failure 404
+// @Failure 412 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id} [delete]
+func (pr *PlaylistRouter) DeletePlaylistByID(w http.ResponseWriter, r *http.Request) {
+	id, _ := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	if !pr.isVisible(w, r, id) {
+		return
+	}
+	if err := pr.service.Delete(r.Context(), id, version); err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusNoContent, nil)
+}
+
+// AddPlaylistItem godoc
+// @Summary Add a video to a playlist
+// @Description Add an active video to a playlist at a position starting from 1, shifting the videos from there on, or at the end without one. A video is only once in a playlist.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param item body dto.InsertPlaylistItem true "Video to add"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Playlist
+// @Header 200 {string} ETag "Version of the playlist"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:playlists, and to own the playlist or admin:content"
+// @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
+// @Failure 422 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id}/items [post]
+func (pr *PlaylistRouter) AddPlaylistItem(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var item dto.InsertPlaylistItem
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	id, _ := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	pr.respondWithPlaylist(w, r, id, func() (*models.Playlist, error) {
+		return pr.service.AddVideo(r.Context(), id, item, version)
+	})
+}
+
+// MovePlaylistItem godoc
+// @Summary Move a video of a playlist
+// @Description Move a video of a playlist to another position starting from 1, shifting the videos in between.
+// @Tags playlists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param videoID path string true "Video ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param move body dto.MovePlaylistItem true "New position"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Playlist
+// @Header 200 {string} ETag "Version of the playlist"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:playlists, and to own the playlist or admin:content"
+// @Failure 404
+// @Failure 412 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id}/items/{videoID} [patch]
+func (pr *PlaylistRouter) MovePlaylistItem(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var move dto.MovePlaylistItem
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+	if err := move.Validate(); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	videoID, _ := primitive.ObjectIDFromHex(params["videoID"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	pr.respondWithPlaylist(w, r, id, func() (*models.Playlist, error) {
+		return pr.service.MoveVideo(r.Context(), id, videoID, move.Position, version)
+	})
+}
+
+// RemovePlaylistItem godoc
+// @Summary Remove a video from a playlist
+// @Description Remove a video from a playlist, moving up the videos after it.
+// @Tags playlists
+// @Produce  json
+// @Param id path string true "Playlist ID"
+// @Param videoID path string true "Video ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Playlist
+// @Header 200 {string} ETag "Version of the playlist"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:playlists, and to own the playlist or admin:content"
+// @Failure 404
+// @Failure 412 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /playlists/{id}/items/{videoID} [delete]
+func (pr *PlaylistRouter) RemovePlaylistItem(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	videoID, _ := primitive.ObjectIDFromHex(params["videoID"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	pr.respondWithPlaylist(w, r, id, func() (*models.Playlist, error) {
+		return pr.service.RemoveVideo(r.Context(), id, videoID, version)
+	})
+}
+
+// respondWithPlaylist responds with the playlist write returns, once the
+// playlist is known to be visible to the request.
+func (pr *PlaylistRouter) respondWithPlaylist(w http.ResponseWriter, r *http.Request, id primitive.ObjectID,
+	write func() (*models.Playlist, error)) {
+	if !pr.isVisible(w, r, id) {
+		return
+	}
+	playlist, err := write()
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, playlist.Version)
+	RespondWithJson(w, http.StatusOK, playlist)
+}
+
+// isVisible responds with not found (404), as GetPlaylistByID does, unless
+// the request may see the playlist, so that writes do not reveal the private
+// playlists of others by answering forbidden (403).
+func (pr *PlaylistRouter) isVisible(w http.ResponseWriter, r *http.Request, id primitive.ObjectID) bool {
+	playlist, err := pr.service.GetByID(id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return false
+	}
+	if !domain.CanView(r.Context(), *playlist) {
+		RespondWithJson(w, http.StatusNotFound, nil)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,251 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGetPlaylists(t *testing.T) {
+	t.Run("GetAllPlaylists Should list the playlists the token subject may see", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		playlists := []models.Playlist{*mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())}
+		pageJson, _ := json.Marshal(Page{Items: playlists, Page: 1, PageSize: 5, Total: 1, TotalPages: 1})
+		mocked_services.PlaylistServiceMockGetAll = func(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+			assert.Equal(t, dto.PlaylistFilter{OwnerID: "auth0|456", Viewer: "auth0|123"}, filter)
+			return playlists, 1, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/playlists?owner=auth0|456", nil)
+		r = r.WithContext(mocked_data.GetContextWithSubject("auth0|123"))
+		w := httptest.NewRecorder()
+
+		router.GetAllPlaylists(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pageJson, w.Body.Bytes())
+	})
+
+	t.Run("GetAllPlaylists Should list every playlist When the token grants admin:content", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockGetAll = func(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+			assert.Equal(t, "", filter.Viewer)
+			return nil, 0, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/playlists", nil)
+		r = r.WithContext(mocked_data.GetContextWithPermissions("auth0|123", AdminPermission))
+		w := httptest.NewRecorder()
+
+		router.GetAllPlaylists(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("GetAllPlaylists Should list only the public playlists When the request carries no token", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockGetAll = func(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+			assert.Equal(t, dto.PlaylistFilter{PublicOnly: true}, filter)
+			return nil, 0, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/playlists", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllPlaylists(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"items":[]`)
+	})
+
+	t.Run("GetPlaylistByID Should return not found (404) When the playlist is private to someone else", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+		mocked_services.PlaylistServiceMockGetByID = func(id primitive.ObjectID) (*models.Playlist, error) {
+			return playlist, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/playlists/"+playlist.ID.Hex(), nil)
+		r = mux.SetURLVars(r, map[string]string{"id": playlist.ID.Hex()})
+
+		for subject, code := range map[string]int{"auth0|123": http.StatusOK, "auth0|456": http.StatusNotFound} {
+			w := httptest.NewRecorder()
+			router.GetPlaylistByID(w, r.WithContext(mocked_data.GetContextWithSubject(subject)))
+			assert.Equal(t, code, w.Code, subject)
+		}
+		playlist.Visibility = models.PublicVisibility
+		w := httptest.NewRecorder()
+		router.GetPlaylistByID(w, r.WithContext(mocked_data.GetContextWithSubject("auth0|456")))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"1"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("GetPlaylistByID Should tell a missing playlist (404) from a failed read (500)", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		id := primitive.NewObjectID()
+		r, _ := http.NewRequest("GET", "/api/v1/playlists/"+id.Hex(), nil)
+		r = mux.SetURLVars(r, map[string]string{"id": id.Hex()})
+		r = r.WithContext(mocked_data.GetContextWithSubject("auth0|123"))
+
+		for err, code := range map[error]int{interfaces.ErrNotFound: http.StatusNotFound, errors.New("Error test"): http.StatusInternalServerError} {
+			mocked_services.PlaylistServiceMockGetByID = func(id primitive.ObjectID) (*models.Playlist, error) {
+				return nil, err
+			}
+			w := httptest.NewRecorder()
+			router.GetPlaylistByID(w, r)
+			assert.Equal(t, code, w.Code, err.Error())
+		}
+	})
+}
+
+func TestCreatePlaylist(t *testing.T) {
+	t.Run("Should return the created playlist and created (201) status response", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+		mocked_services.PlaylistServiceMockCreate = func(insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+			assert.Equal(t, "Favorites", insertPlaylist.Titulo)
+			return playlist, nil
+		}
+		r, _ := http.NewRequest("POST", "/api/v1/playlists", bytes.NewBufferString(`{"titulo":"Favorites"}`))
+		w := httptest.NewRecorder()
+
+		router.CreatePlaylist(w, r)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Should return bad request (400) status response When the playlist is invalid", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockCreate = func(insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+			return nil, dto.MissingFieldError("Titulo")
+		}
+		for _, body := range []string{`{`, `{"titulo":""}`} {
+			r, _ := http.NewRequest("POST", "/api/v1/playlists", bytes.NewBufferString(body))
+			w := httptest.NewRecorder()
+
+			router.CreatePlaylist(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		}
+	})
+}
+
+func TestPlaylistItems(t *testing.T) {
+	playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+	videoID := primitive.NewObjectID()
+	mocked_services.PlaylistServiceMockGetByID = func(id primitive.ObjectID) (*models.Playlist, error) {
+		return playlist, nil
+	}
+	owner := mocked_data.GetContextWithSubject("auth0|123")
+
+	t.Run("AddPlaylistItem Should pass the item and the If-Match version to the service", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockAddVideo = func(id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+			assert.Equal(t, dto.InsertPlaylistItem{VideoID: videoID, Position: 1}, item)
+			assert.Equal(t, int64(1), version)
+			added := *playlist
+			added.Version = 2
+			return &added, nil
+		}
+		r, _ := http.NewRequest("POST", "/api/v1/playlists/"+playlist.ID.Hex()+"/items",
+			bytes.NewBufferString(`{"videoID":"`+videoID.Hex()+`","position":1}`))
+		r = mux.SetURLVars(r.WithContext(owner), map[string]string{"id": playlist.ID.Hex()})
+		r.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.AddPlaylistItem(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("AddPlaylistItem Should map the errors of the service to their status", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		for err, code := range map[error]int{
+			interfaces.ErrVideoInPlaylist:    http.StatusConflict,
+			interfaces.ErrVideoNotFound:      http.StatusUnprocessableEntity,
+			interfaces.ErrNotOwner:           http.StatusForbidden,
+			interfaces.ErrVersionMismatch:    http.StatusPreconditionFailed,
+			dto.InvalidPositionError(9):      http.StatusBadRequest,
+			interfaces.ErrVideoNotInPlaylist: http.StatusNotFound,
+		} {
+			serviceErr := err
+			mocked_services.PlaylistServiceMockAddVideo = func(id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+				return nil, serviceErr
+			}
+			r, _ := http.NewRequest("POST", "/api/v1/playlists/"+playlist.ID.Hex()+"/items",
+				bytes.NewBufferString(`{"videoID":"`+videoID.Hex()+`"}`))
+			r = mux.SetURLVars(r.WithContext(owner), map[string]string{"id": playlist.ID.Hex()})
+			w := httptest.NewRecorder()
+
+			router.AddPlaylistItem(w, r)
+
+			assert.Equal(t, code, w.Code, err.Error())
+		}
+	})
+
+	t.Run("MovePlaylistItem Should return bad request (400) When the position is not positive", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		r, _ := http.NewRequest("PATCH", "/api/v1/playlists/"+playlist.ID.Hex()+"/items/"+videoID.Hex(), bytes.NewBufferString(`{"position":0}`))
+		r = mux.SetURLVars(r.WithContext(owner), map[string]string{"id": playlist.ID.Hex(), "videoID": videoID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.MovePlaylistItem(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, `{"error":"position 0 is not within the playlist."}`, w.Body.String())
+	})
+
+	t.Run("RemovePlaylistItem Should return not found (404) without writing When the playlist is private to someone else", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockRemoveVideo = func(id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error) {
+			t.Fatal("the playlist was written")
+			return nil, nil
+		}
+		r, _ := http.NewRequest("DELETE", "/api/v1/playlists/"+playlist.ID.Hex()+"/items/"+videoID.Hex(), nil)
+		r = mux.SetURLVars(r.WithContext(mocked_data.GetContextWithSubject("auth0|456")),
+			map[string]string{"id": playlist.ID.Hex(), "videoID": videoID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.RemovePlaylistItem(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("DeletePlaylistByID Should return no content (204) When the playlist is deleted", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			assert.Equal(t, playlist.ID, id)
+			return nil
+		}
+		r, _ := http.NewRequest("DELETE", "/api/v1/playlists/"+playlist.ID.Hex(), nil)
+		r = mux.SetURLVars(r.WithContext(owner), map[string]string{"id": playlist.ID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.DeletePlaylistByID(w, r)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("DeletePlaylistByID Should return not found (404) When the playlist does not exist", func(t *testing.T) {
+		router := ProvidePlaylistRouter(&mocked_services.PlaylistServiceMock{})
+		mocked_services.PlaylistServiceMockGetByID = func(id primitive.ObjectID) (*models.Playlist, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		r, _ := http.NewRequest("DELETE", "/api/v1/playlists/"+playlist.ID.Hex(), nil)
+		r = mux.SetURLVars(r.WithContext(owner), map[string]string{"id": playlist.ID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.DeletePlaylistByID(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -2,9 +2,32 @@ package resources
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	defaultPageSize           = 5
+	MergePatchContentType     = "application/merge-patch+json"
+	// ArrayMediaType asks a list endpoint for the bare array it returned
+	// before the pagination envelope.
+	ArrayMediaType = "application/vnd.aluraflix.array+json"
+	// EditorPermission lets a token see the drafts, scheduled and unpublished
+	// videos in the listings.
+	EditorPermission = WriteVideosPermission
 )
 
 // ErrorMessage represents a error model
@@ -12,10 +35,63 @@ type ErrorMessage struct {
 	Error string `json:"error" example:"example error"`
 }
 
+// PurgeResult represents how many deleted items were permanently removed
+type PurgeResult struct {
+	Purged int64 `json:"purged" example:"3"`
+}
+
+// Page represents a page of a listing and where it sits in the whole result,
+// with the facet counts of the listings that have them
+type Page struct {
+	Items      interface{} `json:"items"`
+	Page       int64       `json:"page" example:"1"`
+	PageSize   int64       `json:"pageSize" example:"5"`
+	Total      int64       `json:"total" example:"12"`
+	TotalPages int64       `json:"totalPages" example:"3"`
+	Facets     interface{} `json:"facets,omitempty"`
+}
+
+// CursorPage represents a keyset page of a listing and the cursor to the next one
+type CursorPage struct {
+	Items      interface{} `json:"items"`
+	Limit      int64       `json:"limit" example:"5"`
+	NextCursor string      `json:"nextCursor,omitempty" example:"eyJpZCI6IjYxMGFjMjkwMDBjZjlmNWRjZjM1NDUzNSJ9"`
+	Facets     interface{} `json:"facets,omitempty"`
+}
+
 func RespondWithError(w http.ResponseWriter, code int, msg string) {
 	RespondWithJson(w, code, map[string]string{"error": msg})
 }
 
+// RespondWithServiceError responds with the status code matching one of the
+// errors shared by the storage backends, or 500 for any other error.
+func RespondWithServiceError(w http.ResponseWriter, err error) {
+	RespondWithError(w, GetErrorStatusCode(err), err.Error())
+}
+
+func GetErrorStatusCode(err error) int {
+	var validationErr dto.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, interfaces.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, interfaces.ErrVideoNotInPlaylist):
+		return http.StatusNotFound
+	case errors.Is(err, interfaces.ErrCategoryHasVideos), errors.Is(err, interfaces.ErrProtectedCategory),
+		errors.Is(err, interfaces.ErrVideoInPlaylist):
+		return http.StatusConflict
+	case errors.Is(err, interfaces.ErrCategoryNotFound), errors.Is(err, interfaces.ErrVideoNotFound):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, interfaces.ErrVersionMismatch):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, interfaces.ErrNotOwner):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func RespondWithJson(w http.ResponseWriter, code int, payload interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -25,15 +101,278 @@ func RespondWithJson(w http.ResponseWriter, code int, payload interface{}) {
 	}
 }
 
-func GetQueryParams(queryParams url.Values) (filter string, page int64, pageSize int64) {
-	filter = queryParams.Get("search")
+// AcceptsArray reports whether the client asked for the bare array of a listing.
+func AcceptsArray(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), ArrayMediaType)
+}
+
+// RespondWithPage responds with items wrapped in a Page, or as a bare array when
+// the client accepts ArrayMediaType. Both carry RFC 8288 Link headers to the
+// first, previous, next and last pages. Facets may be nil.
+func RespondWithPage(w http.ResponseWriter, r *http.Request, code int, items interface{}, page int64, pageSize int64, total int64, facets interface{}) {
+	totalPages := getTotalPages(total, pageSize)
+	if links := makePageLinks(r.URL, page, totalPages); links != "" {
+		w.Header().Set("Link", links)
+	}
+	if AcceptsArray(r) {
+		RespondWithJson(w, code, items)
+		return
+	}
+	RespondWithJson(w, code, Page{items, page, pageSize, total, totalPages, facets})
+}
+
+// RespondWithCursorPage responds with items wrapped in a CursorPage, or as a
+// bare array when the client accepts ArrayMediaType. Both carry a Link header
+// to the next page when there is one. Facets may be nil.
+func RespondWithCursorPage(w http.ResponseWriter, r *http.Request, code int, items interface{}, limit int64, next *dto.Cursor, facets interface{}) {
+	page := CursorPage{Items: items, Limit: limit, Facets: facets}
+	if next != nil {
+		page.NextCursor = next.Encode()
+		query := r.URL.Query()
+		query.Set("after", page.NextCursor)
+		w.Header().Set("Link", fmt.Sprintf("<%s?%s>; rel=\"next\"", r.URL.Path, query.Encode()))
+	}
+	if AcceptsArray(r) {
+		RespondWithJson(w, code, items)
+		return
+	}
+	RespondWithJson(w, code, page)
+}
+
+func getTotalPages(total int64, pageSize int64) int64 {
+	if pageSize <= 0 {
+		if total > 0 {
+			return 1
+		}
+		return 0
+	}
+	return (total + pageSize - 1) / pageSize
+}
+
+func makePageLinks(requestURL *url.URL, page int64, totalPages int64) string {
+	last := totalPages
+	if last < 1 {
+		last = 1
+	}
+	link := func(target int64, rel string) string {
+		query := requestURL.Query()
+		query.Set("page", strconv.FormatInt(target, 10))
+		return fmt.Sprintf("<%s?%s>; rel=\"%s\"", requestURL.Path, query.Encode(), rel)
+	}
+	links := []string{link(1, "first")}
+	if page > 1 {
+		previous := page - 1
+		if previous > last {
+			previous = last
+		}
+		links = append(links, link(previous, "prev"))
+	}
+	if page < totalPages {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+	return strings.Join(links, ", ")
+}
+
+// DecodeMergePatch reads a JSON Merge Patch (RFC 7396) body into patch. When it
+// cannot, it responds with the matching error and returns false.
+func DecodeMergePatch(w http.ResponseWriter, r *http.Request, patch interface{}) bool {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if mediaType != MergePatchContentType && mediaType != "application/json" {
+			RespondWithError(w, http.StatusUnsupportedMediaType, "Content-Type must be "+MergePatchContentType)
+			return false
+		}
+	}
+	if err := json.NewDecoder(r.Body).Decode(patch); err != nil {
+		var validationErr dto.ValidationError
+		if errors.As(err, &validationErr) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+		} else {
+			RespondWithError(w, http.StatusBadRequest, "Invalid request payload")
+		}
+		return false
+	}
+	return true
+}
+
+// SetETag exposes the version of a resource as its entity tag.
+func SetETag(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// GetIfMatchVersion reads the version required by the If-Match header. An
+// absent header or "*" returns 0, which skips the version check. When the
+// header holds no version it responds with 412 and returns false.
+func GetIfMatchVersion(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	version, err := parseETag(header)
+	if err != nil {
+		RespondWithError(w, http.StatusPreconditionFailed, "If-Match must hold the ETag of the resource")
+		return 0, false
+	}
+	return version, true
+}
+
+// RespondNotModified answers with 304 when the If-None-Match header already
+// holds the current version, returning true when it did.
+func RespondNotModified(w http.ResponseWriter, r *http.Request, version int64) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if n, err := parseETag(tag); tag == "*" || (err == nil && n == version) {
+			SetETag(w, version)
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag reads a version from a strong or weak entity tag.
+func parseETag(tag string) (int64, error) {
+	unquoted, err := strconv.Unquote(strings.TrimPrefix(tag, "W/"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(unquoted, 10, 64)
+}
+
+// GetQueryParams reads the filter and the page of a listing. Besides search,
+// the filter holds the video facets: categoriaID, host and status, each taking
+// several values either repeated or comma separated, active, and the
+// createdFrom and createdTo bounds, inclusive, given as a date or an RFC 3339
+// time. Listings of other resources only use filter.Search.
+func GetQueryParams(queryParams url.Values) (filter dto.VideoFilter, page int64, pageSize int64, err error) {
+	filter.Search = queryParams.Get("search")
+	page, pageSize = getPageParams(queryParams)
+
+	for _, value := range getListParam(queryParams, "categoriaID") {
+		id, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			return filter, page, pageSize, dto.InvalidFieldError("Invalid categoriaID " + value + ".")
+		}
+		filter.CategoryIDs = append(filter.CategoryIDs, id)
+	}
+	for _, value := range getListParam(queryParams, "host") {
+		filter.Hosts = append(filter.Hosts, dto.NormalizeHost(value))
+	}
+	if filter.Statuses, err = dto.ParseVideoStatuses(getListParam(queryParams, "status")); err != nil {
+		return filter, page, pageSize, err
+	}
+	if value := queryParams.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, page, pageSize, dto.InvalidFieldError("active must be true or false.")
+		}
+		filter.Active = &active
+	}
+	if value := queryParams.Get("createdFrom"); value != "" {
+		if filter.CreatedFrom, _, err = parseTimeParam("createdFrom", value, time.Second); err != nil {
+			return filter, page, pageSize, err
+		}
+	}
+	if value := queryParams.Get("createdTo"); value != "" {
+		if _, filter.CreatedBefore, err = parseTimeParam("createdTo", value, time.Second); err != nil {
+			return filter, page, pageSize, err
+		}
+	}
+	return filter, page, pageSize, nil
+}
+
+// getPageParams reads the page and pageSize query parameters, falling back to
+// the first page of defaultPageSize items.
+func getPageParams(queryParams url.Values) (page int64, pageSize int64) {
 	page = 1
 	if n, err := strconv.Atoi(queryParams.Get("page")); err == nil {
 		page = int64(n)
 	}
-	pageSize = 5
+	pageSize = defaultPageSize
 	if n, err := strconv.Atoi(queryParams.Get("pageSize")); err == nil {
 		pageSize = int64(n)
 	}
-	return filter, page, pageSize
+	return page, pageSize
+}
+
+// getListParam splits the repeated or comma separated values of a parameter.
+func getListParam(queryParams url.Values, name string) []string {
+	var values []string
+	for _, param := range queryParams[name] {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseTimeParam returns the start of a date or time and the moment right
+// after it. A time spans the precision the compared times are stored with,
+// one second for creation times, which come from ObjectIDs.
+func parseTimeParam(name string, value string, precision time.Duration) (start time.Time, end time.Time, err error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, date.AddDate(0, 0, 1), nil
+	}
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return start, end, dto.InvalidFieldError(name + " must be a date (2006-01-02) or an RFC 3339 time.")
+	}
+	moment = moment.Truncate(precision)
+	return moment, moment.Add(precision), nil
+}
+
+// GetCursorParams reads keyset pagination from the after and limit query
+// parameters. It reports false when neither is present, so the caller falls
+// back to page numbers.
+func GetCursorParams(queryParams url.Values) (after *dto.Cursor, limit int64, requested bool, err error) {
+	token, limitParam := queryParams.Get("after"), queryParams.Get("limit")
+	if token == "" && limitParam == "" {
+		return nil, 0, false, nil
+	}
+	limit = defaultPageSize
+	if n, err := strconv.Atoi(limitParam); err == nil && n > 0 {
+		limit = int64(n)
+	}
+	if token != "" {
+		if after, err = dto.DecodeCursor(token); err != nil {
+			return nil, 0, true, err
+		}
+	}
+	return after, limit, true, nil
+}
+
+// GetSortParam reads the sort query parameter, accepting only the sortable
+// fields of the resource. A cursor only continues the sort it was issued for.
+func GetSortParam(queryParams url.Values, sortable []string, after *dto.Cursor) (dto.Sort, error) {
+	sort, err := dto.ParseSort(queryParams.Get("sort"), sortable)
+	if err != nil {
+		return sort, err
+	}
+	if after != nil && after.Sort != sort.String() {
+		return sort, dto.InvalidFieldError("The cursor was issued for another sort.")
+	}
+	return sort, nil
+}
+
+// IsEditor reports whether the request may see the videos not published yet,
+// which takes a token granting EditorPermission.
+func IsEditor(r *http.Request) bool {
+	return jwt.HasPermission(r, EditorPermission)
+}
+
+// GetTrashRetention returns for how long deleted items are kept in the trash,
+// configured in days through TRASH_RETENTION_DAYS.
+func GetTrashRetention() time.Duration {
+	days := defaultTrashRetentionDays
+	if n, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && n >= 0 {
+		days = n
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
@@ -0,0 +1,106 @@
+package resources
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/search"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+type SearchRouter struct {
+	service interfaces.ISearchService
+}
+
+func ProvideSearchRouter(s interfaces.ISearchService) SearchRouter {
+	return SearchRouter{s}
+}
+
+// Search godoc
+// @Summary Full-text search over videos and categories
+// @Description Search the titles and descriptions of videos and the titles of categories, ignoring case and accents, and leaving out the videos not published yet unless the token grants write:videos. Each group is ranked from the most relevant, and highlights hold the matching fields with the matched words wrapped in <mark>.
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param q query string true "Words to search for"
+// @Param limit query int false "Maximum items of each type, 10 by default and 50 at most"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} dto.SearchResults
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos and read:categories"
+// @Failure 404 {object} dto.SearchResults
+// @Failure 500 {object} ErrorMessage
+// @Router /search [get]
+func (sr *SearchRouter) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(search.Terms(query)) == 0 {
+		RespondWithError(w, http.StatusBadRequest, dto.MissingFieldError("q").Error())
+		return
+	}
+	results, err := sr.service.Search(query, !IsEditor(r), getSearchLimit(r))
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if results.IsEmpty() {
+		RespondWithJson(w, http.StatusNotFound, results)
+		return
+	}
+	RespondWithJson(w, http.StatusOK, results)
+}
+
+// Suggest godoc
+// @Summary Autocomplete titles
+// @Description Complete the typed prefix with the titles of videos and categories, ignoring case and accents, in alphabetical order. Without a token only the videos of the FREE category are suggested, and the videos not published yet only when the token grants write:videos.
+// @Tags search
+// @Accept  json
+// @Produce  json
+// @Param prefix query string true "Beginning of the title"
+// @Param limit query int false "Maximum suggestions, 10 by default and 50 at most"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} dto.Suggestion
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos and read:categories when authenticated"
+// @Failure 404 {array} dto.Suggestion
+// @Failure 500 {object} ErrorMessage
+// @Router /suggest [get]
+func (sr *SearchRouter) Suggest(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.TrimSpace(r.URL.Query().Get("prefix"))
+	if prefix == "" {
+		RespondWithError(w, http.StatusBadRequest, dto.MissingFieldError("prefix").Error())
+		return
+	}
+	suggestions, err := sr.service.Suggest(prefix, !jwt.IsAuthenticated(r), !IsEditor(r), getSearchLimit(r))
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(suggestions) == 0 {
+		RespondWithJson(w, http.StatusNotFound, []dto.Suggestion{})
+		return
+	}
+	RespondWithJson(w, http.StatusOK, suggestions)
+}
+
+func getSearchLimit(r *http.Request) int64 {
+	limit := int64(defaultSearchLimit)
+	if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n > 0 {
+		limit = int64(n)
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	return limit
+}
@@ -0,0 +1,192 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	t.Run("Should return the grouped results and ok (200) status response When something matched", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+		results := dto.SearchResults{
+			Query:      "unit test",
+			Videos:     []dto.VideoHit{{Video: *mocked_data.GetValidVideo(), Score: 1.5, Highlights: map[string]string{"titulo": "<mark>unit</mark> <mark>test</mark> title"}}},
+			Categories: []dto.CategoryHit{},
+		}
+		resultsJson, _ := json.Marshal(results)
+		var receivedQuery string
+		var receivedPublishedOnly bool
+		var receivedLimit int64
+
+		mocked_services.SearchServiceMockSearch = func(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+			receivedQuery, receivedPublishedOnly, receivedLimit = query, publishedOnly, limit
+			return &results, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/search?q=+unit+test+&limit=100", nil)
+		w := httptest.NewRecorder()
+
+		router.Search(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, resultsJson, w.Body.Bytes())
+		assert.Equal(t, "unit test", receivedQuery)
+		assert.True(t, receivedPublishedOnly)
+		assert.Equal(t, int64(maxSearchLimit), receivedLimit)
+	})
+
+	t.Run("Should search the videos not published too When the token grants write:videos", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		mocked_services.SearchServiceMockSearch = func(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+			assert.False(t, publishedOnly)
+			return &dto.SearchResults{Query: query, Videos: []dto.VideoHit{}, Categories: []dto.CategoryHit{}}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/search?q=go", nil)
+		w := httptest.NewRecorder()
+
+		router.Search(w, withPermissions(r, EditorPermission))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Should return the empty groups and not found (404) status response When nothing matched", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		mocked_services.SearchServiceMockSearch = func(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+			return &dto.SearchResults{Query: query, Videos: []dto.VideoHit{}, Categories: []dto.CategoryHit{}}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/search?q=nothing", nil)
+		w := httptest.NewRecorder()
+
+		router.Search(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte(`{"query":"nothing","videos":[],"categories":[]}`), w.Body.Bytes())
+	})
+
+	t.Run("Should return bad request (400) status response When the query has no words", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		for _, url := range []string{"/api/v1/search", "/api/v1/search?q=+de+!"} {
+			r, _ := http.NewRequest("GET", url, nil)
+			w := httptest.NewRecorder()
+
+			router.Search(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, []byte("{\"error\":\"q is required.\"}"), w.Body.Bytes())
+		}
+	})
+
+	t.Run("Should return internal server error (500) status response When the search fails", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		mocked_services.SearchServiceMockSearch = func(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+			return nil, errors.New("text index required")
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/search?q=go", nil)
+		w := httptest.NewRecorder()
+
+		router.Search(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestSuggest(t *testing.T) {
+	t.Run("Should return only FREE suggestions and ok (200) status response When the request is anonymous", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+		suggestions := []dto.Suggestion{{ID: mocked_data.GetValidVideo().ID, Titulo: "Unit test title", Type: dto.VideoSuggestion}}
+		suggestionsJson, _ := json.Marshal(suggestions)
+		var receivedPrefix string
+		var receivedFreeOnly, receivedPublishedOnly bool
+		var receivedLimit int64
+
+		mocked_services.SearchServiceMockSuggest = func(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+			receivedPrefix, receivedFreeOnly, receivedPublishedOnly, receivedLimit = prefix, freeOnly, publishedOnly, limit
+			return suggestions, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/suggest?prefix=+Uni+", nil)
+		w := httptest.NewRecorder()
+
+		router.Suggest(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, suggestionsJson, w.Body.Bytes())
+		assert.Equal(t, "Uni", receivedPrefix)
+		assert.True(t, receivedFreeOnly)
+		assert.True(t, receivedPublishedOnly)
+		assert.Equal(t, int64(defaultSearchLimit), receivedLimit)
+	})
+
+	t.Run("Should suggest from every category When the request carries a validated token", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+		var receivedFreeOnly bool
+
+		mocked_services.SearchServiceMockSuggest = func(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+			receivedFreeOnly = freeOnly
+			return []dto.Suggestion{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/suggest?prefix=uni", nil)
+		r = r.WithContext(context.WithValue(r.Context(), "user", &jwt.Token{}))
+		w := httptest.NewRecorder()
+
+		router.Suggest(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+		assert.False(t, receivedFreeOnly)
+	})
+
+	t.Run("Should return bad request (400) status response When the prefix is blank", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/suggest?prefix=+", nil)
+		w := httptest.NewRecorder()
+
+		router.Suggest(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"prefix is required.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return internal server error (500) status response When the suggest fails", func(t *testing.T) {
+		var router = SearchRouter{}
+		router.service = &mocked_services.SearchServiceMock{}
+
+		mocked_services.SearchServiceMockSuggest = func(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+			return nil, errors.New("database down")
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/suggest?prefix=go", nil)
+		w := httptest.NewRecorder()
+
+		router.Suggest(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
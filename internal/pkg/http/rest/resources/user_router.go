@@ -0,0 +1,140 @@
+package resources
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/gorilla/mux"
+)
+
+// userSeenInterval spares a write per request by only saving a user whose
+// profile did not change when they were last seen longer ago.
+const userSeenInterval = time.Minute
+
+type UserRouter struct {
+	service interfaces.IUserService
+}
+
+func ProvideUserRouter(s interfaces.IUserService) UserRouter {
+	return UserRouter{s}
+}
+
+// Identify saves the user behind the token of the requests authenticate lets
+// through, creating it the first time its subject is seen and refreshing its
+// profile from the name, nickname, email and picture claims. Requests
+// authenticated with an API key are not users.
+func (ur *UserRouter) Identify(authenticate Authenticator) Authenticator {
+	return func(next http.Handler) http.Handler {
+		return authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ur.identify(r.Context(), time.Now())
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+// identify saves the user of the token ctx carries, unless the stored user has
+// the same profile and was seen within userSeenInterval. Save is an upsert, so
+// saving the same user again only refreshes it. Failing to save it only delays
+// the profile, so the request goes on.
+func (ur *UserRouter) identify(ctx context.Context, now time.Time) {
+	subject := jwt.Subject(ctx)
+	if subject == "" || strings.HasPrefix(subject, models.APIKeySubjectPrefix) {
+		return
+	}
+	user := models.User{
+		ID:         subject,
+		Name:       jwt.Claim(ctx, "name"),
+		Nickname:   jwt.Claim(ctx, "nickname"),
+		Email:      jwt.Claim(ctx, "email"),
+		Picture:    jwt.Claim(ctx, "picture"),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if stored, err := ur.service.GetByID(subject); err == nil && stored.SameProfile(user) &&
+		now.Sub(stored.LastSeenAt) < userSeenInterval {
+		return
+	}
+	if _, err := ur.service.Save(user); err != nil {
+		log.Printf("could not save the user %s: %v", subject, err)
+	}
+}
+
+// GetMe godoc
+// @Summary Get the user of the token
+// @Description Get the profile of the token subject, as read from the claims of their latest token. Requests authenticated with an API key have no user.
+// @Tags me
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.User
+// @Failure 401 {string} string
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /me [get]
+func (ur *UserRouter) GetMe(w http.ResponseWriter, r *http.Request) {
+	ur.respondWithUser(w, jwt.Subject(r.Context()))
+}
+
+// GetAllUsers godoc
+// @Summary Get all users
+// @Description Get a page of the users the API has seen a token of, most recently created first.
+// @Tags users
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.User}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:users"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /users [get]
+func (ur *UserRouter) GetAllUsers(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := getPageParams(r.URL.Query())
+	users, total, err := ur.service.GetAll(page, pageSize)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if users == nil {
+		RespondWithPage(w, r, http.StatusNotFound, []models.User{}, page, pageSize, total, nil)
+		return
+	}
+	RespondWithPage(w, r, http.StatusOK, users, page, pageSize, total, nil)
+}
+
+// GetUserByID godoc
+// @Summary Get a user by ID
+// @Description Get the profile of a user by the subject of their tokens
+// @Tags users
+// @Produce  json
+// @Param id path string true "User ID, the subject of their tokens"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.User
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:users"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /users/{id} [get]
+func (ur *UserRouter) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	ur.respondWithUser(w, mux.Vars(r)["id"])
+}
+
+func (ur *UserRouter) respondWithUser(w http.ResponseWriter, id string) {
+	user, err := ur.service.GetByID(id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusOK, user)
+}
@@ -0,0 +1,196 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	authjwt "github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// withProfile returns the context of a request carrying a token for the user
+// with their profile claims.
+func withProfile(user models.User) context.Context {
+	return context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{
+		"sub": user.ID, "name": user.Name, "nickname": user.Nickname, "email": user.Email, "picture": user.Picture,
+	}})
+}
+
+func TestUserRouter_Identify(t *testing.T) {
+	t.Run("Should save the user of the token with the profile of its claims", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		user := mocked_data.GetValidUser()
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		var saved models.User
+		mocked_services.UserServiceMockSave = func(u models.User) (*models.User, error) {
+			saved = u
+			return &u, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/me", nil)
+		w := httptest.NewRecorder()
+		passthrough := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r.WithContext(withProfile(user)))
+			})
+		}
+
+		router.Identify(passthrough)(authenticated).ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, user.ID, saved.ID)
+		assert.True(t, user.SameProfile(saved))
+	})
+
+	t.Run("Should save the user again only When their profile changed or the interval passed", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		user := mocked_data.GetValidUser()
+		var stored *models.User
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			if stored == nil {
+				return nil, interfaces.ErrNotFound
+			}
+			return stored, nil
+		}
+		var saved []models.User
+		mocked_services.UserServiceMockSave = func(u models.User) (*models.User, error) {
+			saved = append(saved, u)
+			stored = &u
+			return &u, nil
+		}
+		now := time.Now()
+
+		router.identify(withProfile(user), now)
+		router.identify(withProfile(user), now.Add(time.Second))
+		user.Name = "Renamed"
+		router.identify(withProfile(user), now.Add(2*time.Second))
+		router.identify(withProfile(user), now.Add(2*time.Second+userSeenInterval))
+
+		assert.Equal(t, 3, len(saved))
+		assert.Equal(t, "Renamed", saved[1].Name)
+		assert.Equal(t, now.Add(2*time.Second), saved[1].LastSeenAt)
+		assert.Equal(t, now.Add(2*time.Second+userSeenInterval), saved[2].LastSeenAt)
+	})
+
+	t.Run("Should not save anonymous requests nor those authenticated with an API key", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		mocked_services.UserServiceMockSave = func(u models.User) (*models.User, error) {
+			t.Fatal("the user was saved")
+			return nil, nil
+		}
+
+		router.identify(context.TODO(), time.Now())
+		router.identify(authjwt.WithPrincipal(context.TODO(), models.APIKeySubjectPrefix+"611743c4a8e4b1006a7e5e8c", nil), time.Now())
+	})
+
+	t.Run("Should try again on the next request When the user could not be saved", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		saves := 0
+		mocked_services.UserServiceMockSave = func(u models.User) (*models.User, error) {
+			saves++
+			return nil, errors.New("Error test")
+		}
+
+		router.identify(withProfile(mocked_data.GetValidUser()), time.Now())
+		router.identify(withProfile(mocked_data.GetValidUser()), time.Now())
+
+		assert.Equal(t, 2, saves)
+	})
+}
+
+func TestGetMe(t *testing.T) {
+	t.Run("Should return the user of the token and ok (200) status response", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		user := mocked_data.GetValidUser()
+		userJson, _ := json.Marshal(user)
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			assert.Equal(t, user.ID, id)
+			return &user, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/me", nil)
+		r = r.WithContext(mocked_data.GetContextWithSubject(user.ID))
+		w := httptest.NewRecorder()
+
+		router.GetMe(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, userJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return not found (404) status response When the token has no user", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/me", nil)
+		w := httptest.NewRecorder()
+
+		router.GetMe(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestGetUsers(t *testing.T) {
+	t.Run("GetAllUsers Should return a page of users and ok (200) status response", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		users := []models.User{mocked_data.GetValidUser()}
+		pageJson, _ := json.Marshal(Page{Items: users, Page: 2, PageSize: 1, Total: 3, TotalPages: 3})
+		mocked_services.UserServiceMockGetAll = func(page int64, pageSize int64) ([]models.User, int64, error) {
+			assert.Equal(t, []int64{2, 1}, []int64{page, pageSize})
+			return users, 3, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/users?page=2&pageSize=1", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllUsers(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, pageJson, w.Body.Bytes())
+	})
+
+	t.Run("GetAllUsers Should return an empty page and not found (404) status response When there are no users", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		mocked_services.UserServiceMockGetAll = func(page int64, pageSize int64) ([]models.User, int64, error) {
+			return nil, 0, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/users", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllUsers(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte(`{"items":[],"page":1,"pageSize":5,"total":0,"totalPages":0}`), w.Body.Bytes())
+	})
+
+	t.Run("GetUserByID Should return the user with the id", func(t *testing.T) {
+		router := ProvideUserRouter(&mocked_services.UserServiceMock{})
+		user := mocked_data.GetValidUser()
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			assert.Equal(t, "auth0|123", id)
+			return &user, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/users/auth0|123", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": "auth0|123"})
+		w := httptest.NewRecorder()
+
+		router.GetUserByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -3,11 +3,13 @@ package resources
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
-	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/services"
 	"github.com/gorilla/mux"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -16,13 +18,13 @@ type VideoRouter struct {
 	service interfaces.IVideoService
 }
 
-func ProvideVideoRouter(s services.VideoService) VideoRouter {
-	return VideoRouter{&s}
+func ProvideVideoRouter(s interfaces.IVideoService) VideoRouter {
+	return VideoRouter{s}
 }
 
 // GetAllFreeVideos godoc
 // @Summary Get all free videos
-// @Description Get all free videos
+// @Description Get all the published videos of the FREE category
 // @Tags videos
 // @Accept  json
 // @Produce  json
@@ -47,45 +49,170 @@ func (vr *VideoRouter) GetAllFreeVideos(w http.ResponseWriter, r *http.Request)
 
 // GetAllVideos godoc
 // @Summary Get details of all videos
-// @Description Get details of all videos
+// @Description Get a page of videos with the total count and the facet counts per category and per URL host. Each facet applies every filter but its own. Link headers point to the first, previous, next and last pages, and Accept: application/vnd.aluraflix.array+json returns the bare array instead. Sending after or limit pages by cursor instead, answering a CursorPage whose nextCursor is the after of the next page.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param search query string false "Search by name"
+// @Param categoriaID query []string false "Category ids, repeated or comma separated" collectionFormat(multi)
+// @Param host query []string false "URL hosts such as youtube.com, repeated or comma separated" collectionFormat(multi)
+// @Param status query []string false "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated. Without write:videos only published videos are listed" collectionFormat(multi)
+// @Param active query bool false "false lists the deleted videos instead of the active ones"
+// @Param createdFrom query string false "Created on or after this date (2006-01-02) or RFC 3339 time"
+// @Param createdTo query string false "Created on or before this date (2006-01-02) or RFC 3339 time"
 // @Param page query int false "Page number"
 // @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order"
+// @Param after query string false "Cursor returned as nextCursor by the previous page"
+// @Param limit query int false "Page size when paging by cursor"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
 // @Security ApiKeyAuth
-// @Success 200 {array} models.Video
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.Video,facets=dto.VideoFacets}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
 // @Failure 404
 // @Failure 500 {object} ErrorMessage
 // @Router /videos [get]
 func (vr *VideoRouter) GetAllVideos(w http.ResponseWriter, r *http.Request) {
-	filter, page, pageSize := GetQueryParams(r.URL.Query())
-	videos, err := vr.service.GetAll(filter, page, pageSize)
+	vr.respondWithVideos(w, r, "", !IsEditor(r))
+}
+
+// GetMyVideos godoc
+// @Summary Get the videos of the token subject
+// @Description Get a page of the videos the token subject owns, whatever their publication status unless filtered by status. Takes the filters, sort and pagination of the video listing.
+// @Tags me
+// @Accept  json
+// @Produce  json
+// @Param search query string false "Search by name"
+// @Param categoriaID query []string false "Category ids, repeated or comma separated" collectionFormat(multi)
+// @Param host query []string false "URL hosts such as youtube.com, repeated or comma separated" collectionFormat(multi)
+// @Param status query []string false "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated" collectionFormat(multi)
+// @Param active query bool false "false lists the deleted videos instead of the active ones"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order"
+// @Param after query string false "Cursor returned as nextCursor by the previous page"
+// @Param limit query int false "Page size when paging by cursor"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.Video,facets=dto.VideoFacets}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /me/videos [get]
+func (vr *VideoRouter) GetMyVideos(w http.ResponseWriter, r *http.Request) {
+	vr.respondWithVideos(w, r, jwt.Subject(r.Context()), false)
+}
+
+// GetUserVideos godoc
+// @Summary Get the videos of a user
+// @Description Get a page of the videos a user owns. Without write:videos only published videos are listed. Takes the filters, sort and pagination of the video listing.
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param id path string true "User ID, the subject of their tokens"
+// @Param search query string false "Search by name"
+// @Param categoriaID query []string false "Category ids, repeated or comma separated" collectionFormat(multi)
+// @Param host query []string false "URL hosts such as youtube.com, repeated or comma separated" collectionFormat(multi)
+// @Param status query []string false "Publication statuses draft, scheduled, published or unpublished, repeated or comma separated" collectionFormat(multi)
+// @Param active query bool false "false lists the deleted videos instead of the active ones"
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Param sort query string false "Sort by titulo, created, createdAt, updatedAt, createdBy or updatedBy, prefixed with - for descending order"
+// @Param after query string false "Cursor returned as nextCursor by the previous page"
+// @Param limit query int false "Page size when paging by cursor"
+// @Param Accept header string false "application/vnd.aluraflix.array+json for the bare array"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} Page{items=[]models.Video,facets=dto.VideoFacets}
+// @Header 200 {string} Link "Links to the first, previous, next and last pages"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:users"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /users/{id}/videos [get]
+func (vr *VideoRouter) GetUserVideos(w http.ResponseWriter, r *http.Request) {
+	vr.respondWithVideos(w, r, mux.Vars(r)["id"], !IsEditor(r))
+}
+
+// respondWithVideos lists the videos matching the query parameters, only
+// those of owner when one is given, and only the published ones when
+// publishedOnly is set.
+func (vr *VideoRouter) respondWithVideos(w http.ResponseWriter, r *http.Request, owner string, publishedOnly bool) {
+	filter, page, pageSize, err := GetQueryParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	after, limit, byCursor, err := GetCursorParams(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	sort, err := GetSortParam(r.URL.Query(), dto.VideoSortFields, after)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	filter.OwnerID = owner
+	if publishedOnly {
+		filter.Statuses = []string{models.PublishedStatus}
+	}
+	var facets interface{}
+	if !AcceptsArray(r) {
+		if facets, err = vr.service.GetFacets(filter); err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if byCursor {
+		videos, next, err := vr.service.GetAllAfter(filter, sort, after, limit)
+		if err != nil {
+			RespondWithError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if videos == nil {
+			RespondWithCursorPage(w, r, http.StatusNotFound, []models.Video{}, limit, nil, facets)
+			return
+		}
+		RespondWithCursorPage(w, r, http.StatusOK, videos, limit, next, facets)
+		return
+	}
+	videos, total, err := vr.service.GetAll(filter, sort, page, pageSize)
 	if err != nil {
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	if videos == nil {
-		RespondWithJson(w, http.StatusNotFound, []models.Video{})
+		RespondWithPage(w, r, http.StatusNotFound, []models.Video{}, page, pageSize, total, facets)
 		return
 	}
-	RespondWithJson(w, http.StatusOK, videos)
+	RespondWithPage(w, r, http.StatusOK, videos, page, pageSize, total, facets)
 }
 
 // GetVideoByID godoc
 // @Summary Get details of a video by ID
-// @Description Get details of a video by ID
+// @Description Get details of a video by ID. Only tokens granting write:videos find the videos not published yet.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Video ID"
+// @Param If-None-Match header string false "ETag of a cached version"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} models.Video
+// @Header 200 {string} ETag "Version of the video"
+// @Success 304
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
 // @Failure 404
 // @Failure 500 {object} ErrorMessage
 // @Router /videos/{id} [get]
@@ -93,24 +220,32 @@ func (vr *VideoRouter) GetVideoByID(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, _ := primitive.ObjectIDFromHex(params["id"])
 	video, err := vr.service.GetByID(id)
-	if err != nil {
+	if err != nil || (!IsEditor(r) && video.Status != models.PublishedStatus) {
 		RespondWithJson(w, http.StatusNotFound, nil)
 		return
 	}
+	if RespondNotModified(w, r, video.Version) {
+		return
+	}
+	SetETag(w, video.Version)
 	RespondWithJson(w, http.StatusOK, video)
 }
 
 // CreateVideo godoc
 // @Summary Create a new Video
-// @Description Create a new Video
+// @Description Create a new Video. Without categoriaID the video goes to the FREE category. The status is draft, scheduled, published or unpublished; without one the video is scheduled when it has a publishAt and published otherwise. Only scheduled videos take a publishAt.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param video body dto.InsertVideo true "New video"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 201 {object} models.Video
+// @Header 201 {string} ETag "Version of the video"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:videos"
+// @Failure 422 {object} ErrorMessage
 // @Failure 500 {object} ErrorMessage
 // @Router /videos [post]
 func (vr *VideoRouter) CreateVideo(w http.ResponseWriter, r *http.Request) {
@@ -125,27 +260,34 @@ func (vr *VideoRouter) CreateVideo(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	createdVideo, err := vr.service.Create(video)
+	createdVideo, err := vr.service.Create(r.Context(), video)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		RespondWithServiceError(w, err)
 		return
 	}
+	SetETag(w, createdVideo.Version)
 	RespondWithJson(w, http.StatusCreated, createdVideo)
 }
 
 // UpdateVideoByID godoc
 // @Summary Update a video by ID
-// @Description Update a video by ID
+// @Description Update a video by ID. Without categoriaID the video goes to the FREE category. The status and publishAt are set as on creation, except that leaving both out keeps the ones stored.
 // @Tags videos
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Video ID"
+// @Param If-Match header string false "ETag of the version being changed"
 // @Param category body dto.InsertVideo true "New video values"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200 {object} models.Video
+// @Header 200 {string} ETag "Version of the video"
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:videos, and to own the video or admin:content"
 // @Failure 404
+// @Failure 422 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
 // @Failure 500 {object} ErrorMessage
 // @Router /videos [put]
 func (vr *VideoRouter) UpdateVideoByID(w http.ResponseWriter, r *http.Request) {
@@ -161,15 +303,62 @@ func (vr *VideoRouter) UpdateVideoByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	id, _ := primitive.ObjectIDFromHex(params["id"])
-	updatedVideo, err := vr.service.Update(id, video)
-
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	updatedVideo, err := vr.service.Update(r.Context(), id, video, version)
 	if err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		RespondWithServiceError(w, err)
 		return
 	}
+	SetETag(w, updatedVideo.Version)
 	RespondWithJson(w, http.StatusOK, updatedVideo)
 }
 
+// PatchVideoByID godoc
+// @Summary Partially update a video by ID
+// @Description Apply a JSON Merge Patch (RFC 7396) to a video. Only the fields present are validated and changed. A null categoriaID moves the video to the FREE category. A status or publishAt sets the publication as a whole, so the one left out is reset.
+// @Tags videos
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Param id path int true "Video ID"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Param video body dto.PatchVideo true "Fields to change"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Video
+// @Header 200 {string} ETag "Version of the video"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:videos, and to own the video or admin:content"
+// @Failure 404
+// @Failure 422 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
+// @Failure 415 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /videos/{id} [patch]
+func (vr *VideoRouter) PatchVideoByID(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	params := mux.Vars(r)
+	var patch dto.PatchVideo
+	if !DecodeMergePatch(w, r, &patch) {
+		return
+	}
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	patchedVideo, err := vr.service.Patch(r.Context(), id, patch, version)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, patchedVideo.Version)
+	RespondWithJson(w, http.StatusOK, patchedVideo)
+}
+
 // DeleteVideoByID godoc
 // @Summary Delete a video by ID
 // @Description Delete a video by ID
@@ -177,19 +366,183 @@ func (vr *VideoRouter) UpdateVideoByID(w http.ResponseWriter, r *http.Request) {
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Video ID"
+// @Param If-Match header string false "ETag of the version being changed"
 // @Security ApiKeyAuth
+// @Security APIKeyHeader
 // @Success 200
 // @Failure 400 {object} ErrorMessage
 // @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires delete:videos, and to own the video or admin:content"
 // @Failure 404
+// @Failure 412 {object} ErrorMessage
 // @Failure 500 {object} ErrorMessage
 // @Router /videos [delete]
 func (vr *VideoRouter) DeleteVideoByID(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id, _ := primitive.ObjectIDFromHex(params["id"])
-	if err := vr.service.Delete(id); err != nil {
-		RespondWithError(w, http.StatusInternalServerError, err.Error())
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	if err := vr.service.Delete(r.Context(), id, version); err != nil {
+		RespondWithServiceError(w, err)
 		return
 	}
 	RespondWithJson(w, http.StatusNoContent, nil)
 }
+
+// GetDeletedVideos godoc
+// @Summary Get all deleted videos
+// @Description Get the videos in the trash, most recently deleted first
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number"
+// @Param pageSize query int false "Page size"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.Video
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/videos [get]
+func (vr *VideoRouter) GetDeletedVideos(w http.ResponseWriter, r *http.Request) {
+	_, page, pageSize, _ := GetQueryParams(r.URL.Query())
+	videos, err := vr.service.GetDeleted(page, pageSize)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if videos == nil {
+		RespondWithJson(w, http.StatusNotFound, []models.Video{})
+		return
+	}
+	RespondWithJson(w, http.StatusOK, videos)
+}
+
+// RestoreVideoByID godoc
+// @Summary Restore a deleted video by ID
+// @Description Restore a deleted video by ID
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Video ID"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Video
+// @Header 200 {string} ETag "Version of the video"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:videos, and to own the video or admin:content"
+// @Failure 404
+// @Failure 422 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/videos/{id}/restore [post]
+func (vr *VideoRouter) RestoreVideoByID(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	video, err := vr.service.Restore(r.Context(), id)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, video.Version)
+	RespondWithJson(w, http.StatusOK, video)
+}
+
+// PurgeDeletedVideos godoc
+// @Summary Purge deleted videos
+// @Description Permanently remove the videos deleted before the trash retention period (TRASH_RETENTION_DAYS). Only admin:content purges the videos of every user, others purge their own.
+// @Tags trash
+// @Accept  json
+// @Produce  json
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} PurgeResult
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires delete:videos"
+// @Failure 500 {object} ErrorMessage
+// @Router /trash/videos [delete]
+func (vr *VideoRouter) PurgeDeletedVideos(w http.ResponseWriter, r *http.Request) {
+	purged, err := vr.service.Purge(r.Context(), time.Now().Add(-GetTrashRetention()))
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	RespondWithJson(w, http.StatusOK, PurgeResult{purged})
+}
+
+// GetVideoRevisions godoc
+// @Summary Get the revisions of a video
+// @Description Get the versions of a video replaced by its updates, newest first. Each revision is the version it was.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Video ID"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {array} models.VideoRevision
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires read:videos"
+// @Failure 404
+// @Failure 500 {object} ErrorMessage
+// @Router /videos/{id}/revisions [get]
+func (vr *VideoRouter) GetVideoRevisions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	revisions, err := vr.service.GetRevisions(id)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(revisions) == 0 {
+		RespondWithJson(w, http.StatusNotFound, []models.VideoRevision{})
+		return
+	}
+	RespondWithJson(w, http.StatusOK, revisions)
+}
+
+// RestoreVideoRevision godoc
+// @Summary Restore a video to one of its revisions
+// @Description Update a video back to the values it had at a revision, with the same validation as any other update. The restore keeps the replaced version as a new revision.
+// @Tags videos
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Video ID"
+// @Param rev path int true "Revision"
+// @Param If-Match header string false "ETag of the version being changed"
+// @Security ApiKeyAuth
+// @Security APIKeyHeader
+// @Success 200 {object} models.Video
+// @Header 200 {string} ETag "Version of the video"
+// @Failure 400 {object} ErrorMessage
+// @Failure 401 {string} string
+// @Failure 403 {object} ErrorMessage "Requires write:videos, and to own the video or admin:content"
+// @Failure 404
+// @Failure 409 {object} ErrorMessage
+// @Failure 412 {object} ErrorMessage
+// @Failure 422 {object} ErrorMessage
+// @Failure 500 {object} ErrorMessage
+// @Router /videos/{id}/revisions/{rev}/restore [post]
+func (vr *VideoRouter) RestoreVideoRevision(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	id, _ := primitive.ObjectIDFromHex(params["id"])
+	revision, err := strconv.ParseInt(params["rev"], 10, 64)
+	if err != nil {
+		RespondWithJson(w, http.StatusNotFound, nil)
+		return
+	}
+	version, ok := GetIfMatchVersion(w, r)
+	if !ok {
+		return
+	}
+	restored, err := vr.service.RestoreRevision(r.Context(), id, revision, version)
+	if err != nil {
+		RespondWithServiceError(w, err)
+		return
+	}
+	SetETag(w, restored.Version)
+	RespondWithJson(w, http.StatusOK, restored)
+}
@@ -2,20 +2,31 @@ package resources
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
+	"time"
 
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// withPermissions makes r carry a validated token granting the permissions.
+func withPermissions(r *http.Request, permissions ...interface{}) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), "user", &jwt.Token{Claims: jwt.MapClaims{"permissions": permissions}}))
+}
+
 func TestGetAllFreeVideos(t *testing.T) {
 	t.Run("Should return free videos array and ok (200) status response when theres items to show", func(t *testing.T) {
 		var router = VideoRouter{}
@@ -74,14 +85,18 @@ func TestGetAllFreeVideos(t *testing.T) {
 }
 
 func TestGetAllVideos(t *testing.T) {
+	mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+		return &dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}, nil
+	}
+
 	t.Run("Should return videos array and ok (200) status response when theres items to show", func(t *testing.T) {
 		var router = VideoRouter{}
 		videoArray := []models.Video{*mocked_data.GetValidVideo()}
-		videoArrayJson, _ := json.Marshal(videoArray)
+		pageJson, _ := json.Marshal(Page{videoArray, 1, 5, 1, 1, dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}})
 		router.service = &mocked_services.VideoServiceMock{}
 
-		mocked_services.VideoServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Video, error) {
-			return videoArray, nil
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return videoArray, 1, nil
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/videos?page=1&pageSize=5", nil)
@@ -90,15 +105,15 @@ func TestGetAllVideos(t *testing.T) {
 		router.GetAllVideos(w, r)
 
 		assert.Equal(t, http.StatusOK, w.Code)
-		assert.Equal(t, videoArrayJson, w.Body.Bytes())
+		assert.Equal(t, pageJson, w.Body.Bytes())
 	})
 
 	t.Run("Should return empty videos array and not found (404) status response when theres no items to show", func(t *testing.T) {
 		var router = VideoRouter{}
 		router.service = &mocked_services.VideoServiceMock{}
 
-		mocked_services.VideoServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Video, error) {
-			return nil, nil
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return nil, 0, nil
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
@@ -107,15 +122,15 @@ func TestGetAllVideos(t *testing.T) {
 		router.GetAllVideos(w, r)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
-		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+		assert.Equal(t, []byte(`{"items":[],"page":1,"pageSize":5,"total":0,"totalPages":0,"facets":{"categorias":[],"hosts":[]}}`), w.Body.Bytes())
 	})
 
 	t.Run("Should return error and internal server error (500) status response when theres an error", func(t *testing.T) {
 		var router = VideoRouter{}
 		router.service = &mocked_services.VideoServiceMock{}
 
-		mocked_services.VideoServiceMockGetAll = func(filter string, page int64, pageSize int64) ([]models.Video, error) {
-			return nil, errors.New("Error test")
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return nil, 0, errors.New("Error test")
 		}
 
 		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
@@ -126,6 +141,303 @@ func TestGetAllVideos(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		assert.Equal(t, []byte("{\"error\":\"Error test\"}"), w.Body.Bytes())
 	})
+
+	t.Run("Should return the page metadata and Link headers to the neighbour pages", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		videoArray := []models.Video{*mocked_data.GetValidVideo(), *mocked_data.GetValidVideo()}
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return videoArray, 7, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?search=go&page=2&pageSize=2", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		var page Page
+		_ = json.Unmarshal(w.Body.Bytes(), &page)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(2), page.Page)
+		assert.Equal(t, int64(2), page.PageSize)
+		assert.Equal(t, int64(7), page.Total)
+		assert.Equal(t, int64(4), page.TotalPages)
+		assert.Equal(t, `</api/v1/videos?page=1&pageSize=2&search=go>; rel="first", `+
+			`</api/v1/videos?page=1&pageSize=2&search=go>; rel="prev", `+
+			`</api/v1/videos?page=3&pageSize=2&search=go>; rel="next", `+
+			`</api/v1/videos?page=4&pageSize=2&search=go>; rel="last"`, w.Header().Get("Link"))
+	})
+
+	t.Run("Should return the bare videos array When the client accepts it", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		videoArray := []models.Video{*mocked_data.GetValidVideo()}
+		videoArrayJson, _ := json.Marshal(videoArray)
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return videoArray, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		r.Header.Set("Accept", ArrayMediaType)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, videoArrayJson, w.Body.Bytes())
+	})
+}
+
+func TestGetAllVideosByCursor(t *testing.T) {
+	mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+		return &dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}, nil
+	}
+
+	t.Run("Should pass the decoded cursor and return the next one in the body and Link header", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		after := dto.Cursor{ID: primitive.NewObjectID()}
+		next := dto.Cursor{ID: primitive.NewObjectID()}
+		var received *dto.Cursor
+		var receivedLimit int64
+
+		mocked_services.VideoServiceMockGetAllAfter = func(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+			received, receivedLimit = after, limit
+			return []models.Video{*mocked_data.GetValidVideo()}, &next, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?after="+after.Encode()+"&limit=1", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		var page CursorPage
+		_ = json.Unmarshal(w.Body.Bytes(), &page)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, &after, received)
+		assert.Equal(t, int64(1), receivedLimit)
+		assert.Equal(t, next.Encode(), page.NextCursor)
+		assert.Equal(t, `</api/v1/videos?after=`+next.Encode()+`&limit=1>; rel="next"`, w.Header().Get("Link"))
+	})
+
+	t.Run("Should return bad request (400) status response When the cursor is invalid", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?after=invalid", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Invalid cursor.\"}"), w.Body.Bytes())
+	})
+}
+
+func TestGetAllVideosSorted(t *testing.T) {
+	mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+		return &dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}, nil
+	}
+
+	t.Run("Should pass the parsed sort to the service", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		var received dto.Sort
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			received = sort
+			return []models.Video{*mocked_data.GetValidVideo()}, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?sort=-created", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dto.Sort{Descending: true}, received)
+	})
+
+	t.Run("Should return bad request (400) status response When the field is not sortable", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?sort=url", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Cannot sort by url. Sortable fields: titulo, created, createdAt, updatedAt, createdBy, updatedBy.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return bad request (400) status response When the cursor was issued for another sort", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		after := dto.Cursor{ID: primitive.NewObjectID(), Sort: "titulo", Value: "Go"}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?sort=-titulo&after="+after.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"The cursor was issued for another sort.\"}"), w.Body.Bytes())
+	})
+}
+
+func TestGetAllVideosFiltered(t *testing.T) {
+	t.Run("Should pass the parsed filter to the listing and the facets and return the facet counts", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		first, second := primitive.NewObjectID(), primitive.NewObjectID()
+		facets := dto.VideoFacets{
+			Categories: []dto.FacetCount{{Value: first.Hex(), Count: 2}},
+			Hosts:      []dto.FacetCount{{Value: "youtube.com", Count: 2}},
+		}
+		var listed, faceted dto.VideoFilter
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			listed = filter
+			return []models.Video{*mocked_data.GetValidVideo()}, 1, nil
+		}
+		mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+			faceted = filter
+			return &facets, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?search=go&categoriaID="+first.Hex()+","+second.Hex()+
+			"&host=WWW.YouTube.com&host=vimeo.com&active=false&createdFrom=2021-08-01&createdTo=2021-08-14T12:30:00Z&status=Draft,scheduled", nil)
+		r = withPermissions(r, EditorPermission)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		var page struct {
+			Facets dto.VideoFacets `json:"facets"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &page)
+		inactive := false
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, dto.VideoFilter{
+			Search:        "go",
+			CategoryIDs:   []primitive.ObjectID{first, second},
+			Active:        &inactive,
+			CreatedFrom:   time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+			CreatedBefore: time.Date(2021, 8, 14, 12, 30, 1, 0, time.UTC),
+			Hosts:         []string{"youtube.com", "vimeo.com"},
+			Statuses:      []string{models.DraftStatus, models.ScheduledStatus},
+		}, listed)
+		assert.Equal(t, listed, faceted)
+		assert.Equal(t, facets, page.Facets)
+	})
+
+	t.Run("Should end the creation range after the whole day When createdTo is a date", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		var listed dto.VideoFilter
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			listed = filter
+			return nil, 0, nil
+		}
+		mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+			return &dto.VideoFacets{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?createdTo=2021-08-14", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, time.Date(2021, 8, 15, 0, 0, 0, 0, time.UTC), listed.CreatedBefore)
+	})
+
+	t.Run("Should only list the published videos When the token does not grant write:videos", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		var listed, faceted dto.VideoFilter
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			listed = filter
+			return nil, 0, nil
+		}
+		mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+			faceted = filter
+			return &dto.VideoFacets{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos?status=draft", nil)
+		r = withPermissions(r, "read:videos")
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, []string{models.PublishedStatus}, listed.Statuses)
+		assert.Equal(t, []string{models.PublishedStatus}, faceted.Statuses)
+	})
+
+	t.Run("Should not count the facets When the client accepts the bare array", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		counted := false
+
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			return []models.Video{*mocked_data.GetValidVideo()}, 1, nil
+		}
+		mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+			counted = true
+			return &dto.VideoFacets{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		r.Header.Set("Accept", ArrayMediaType)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, counted)
+	})
+
+	t.Run("Should return bad request (400) status response When a filter parameter is invalid", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		cases := map[string]string{
+			"categoriaID=invalid":  "Invalid categoriaID invalid.",
+			"active=maybe":         "active must be true or false.",
+			"createdFrom=14/08/21": "createdFrom must be a date (2006-01-02) or an RFC 3339 time.",
+			"status=live":          "status must be draft, scheduled, published or unpublished.",
+		}
+
+		for query, message := range cases {
+			r, _ := http.NewRequest("GET", "/api/v1/videos?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.GetAllVideos(w, r)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, []byte(`{"error":"`+message+`"}`), w.Body.Bytes())
+		}
+	})
+
+	t.Run("Should return internal server error (500) status response When counting the facets fails", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+			return nil, errors.New("aggregation failed")
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.GetAllVideos(w, r)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
 }
 
 func TestGetVideoByID(t *testing.T) {
@@ -167,6 +479,25 @@ func TestGetVideoByID(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Equal(t, videoJson, w.Body.Bytes())
 	})
+
+	t.Run("Should only find a video not published yet When the token grants write:videos", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		video := mocked_data.GetValidVideo()
+		video.Status = models.DraftStatus
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return video, nil
+		}
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+video.ID.Hex(), nil)
+
+		w := httptest.NewRecorder()
+		router.GetVideoByID(w, withPermissions(r, ReadVideosPermission))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w = httptest.NewRecorder()
+		router.GetVideoByID(w, withPermissions(r, ReadVideosPermission, WriteVideosPermission))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
 }
 
 func TestCreateVideo(t *testing.T) {
@@ -271,7 +602,7 @@ func TestUpdateVideo(t *testing.T) {
 		r, _ := http.NewRequest("PUT", "/api/v1/videos/1", bytes.NewReader(videoDtoJson))
 		w := httptest.NewRecorder()
 
-		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo) (*models.Video, error) {
+		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo, version int64) (*models.Video, error) {
 			return nil, errors.New("There's an error")
 		}
 
@@ -289,7 +620,7 @@ func TestUpdateVideo(t *testing.T) {
 		videoDtoJson, _ := json.Marshal(videoDto)
 		videoModelJson, _ := json.Marshal(videoModel)
 
-		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo) (*models.Video, error) {
+		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo, version int64) (*models.Video, error) {
 			return videoModel, nil
 		}
 
@@ -303,6 +634,44 @@ func TestUpdateVideo(t *testing.T) {
 	})
 }
 
+func TestUpdateVideoByIDWithServiceErrors(t *testing.T) {
+	t.Run("Should return unprocessable entity (422) status response when the category dont exists", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		categoryID := primitive.NewObjectID()
+		videoDtoJson, _ := json.Marshal(mocked_data.GetValidInsertVideoDto())
+
+		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo, version int64) (*models.Video, error) {
+			return nil, interfaces.CategoryNotFoundError{ID: categoryID}
+		}
+
+		r, _ := http.NewRequest("PUT", "/api/v1/videos/"+primitive.NewObjectID().Hex(), bytes.NewReader(videoDtoJson))
+		w := httptest.NewRecorder()
+
+		router.UpdateVideoByID(w, r)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Category with id "+categoryID.Hex()+" dont exists.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return not found (404) status response when the video dont exists", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		videoDtoJson, _ := json.Marshal(mocked_data.GetValidInsertVideoDto())
+
+		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo, version int64) (*models.Video, error) {
+			return nil, interfaces.ErrNotFound
+		}
+
+		r, _ := http.NewRequest("PUT", "/api/v1/videos/"+primitive.NewObjectID().Hex(), bytes.NewReader(videoDtoJson))
+		w := httptest.NewRecorder()
+
+		router.UpdateVideoByID(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
 func TestDeleteVideo(t *testing.T) {
 	t.Run("Should return an error and internal server error (500) status response when theres a problem on videoservice", func(t *testing.T) {
 		var router = VideoRouter{}
@@ -311,7 +680,7 @@ func TestDeleteVideo(t *testing.T) {
 		r, _ := http.NewRequest("DELETE", "/api/v1/videos/"+primitive.NewObjectID().Hex(), nil)
 		w := httptest.NewRecorder()
 
-		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID) error {
+		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID, version int64) error {
 			return errors.New("There's an error")
 		}
 
@@ -328,7 +697,7 @@ func TestDeleteVideo(t *testing.T) {
 		r, _ := http.NewRequest("DELETE", "/api/v1/videos/"+primitive.NewObjectID().Hex(), nil)
 		w := httptest.NewRecorder()
 
-		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID) error {
+		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID, version int64) error {
 			return nil
 		}
 
@@ -337,4 +706,475 @@ func TestDeleteVideo(t *testing.T) {
 		assert.Equal(t, http.StatusNoContent, w.Code)
 		assert.Nil(t, w.Body.Bytes())
 	})
+
+	t.Run("Should return forbidden (403) status response When the token neither owns the video nor is an admin", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("DELETE", "/api/v1/videos/"+primitive.NewObjectID().Hex(), nil)
+		w := httptest.NewRecorder()
+
+		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			return interfaces.ErrNotOwner
+		}
+
+		router.DeleteVideoByID(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, []byte(`{"error":"only the owner of the resource or an admin can change it"}`), w.Body.Bytes())
+	})
+}
+
+func TestGetOwnedVideos(t *testing.T) {
+	mocked_services.VideoServiceMockGetFacets = func(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+		return &dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}, nil
+	}
+
+	t.Run("GetMyVideos Should list the videos of the token subject whatever their status", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		var listed dto.VideoFilter
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			listed = filter
+			return []models.Video{*mocked_data.GetValidVideo()}, 1, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/me/videos", nil)
+		r = r.WithContext(mocked_data.GetContextWithSubject("auth0|123"))
+		w := httptest.NewRecorder()
+
+		router.GetMyVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "auth0|123", listed.OwnerID)
+		assert.Empty(t, listed.Statuses)
+	})
+
+	t.Run("GetUserVideos Should list the published videos of the user When the token is no editor", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		var listed dto.VideoFilter
+		mocked_services.VideoServiceMockGetAll = func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+			listed = filter
+			return nil, 0, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/users/auth0|456/videos", nil)
+		r = mux.SetURLVars(withPermissions(r, ReadUsersPermission), map[string]string{"id": "auth0|456"})
+		w := httptest.NewRecorder()
+
+		router.GetUserVideos(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "auth0|456", listed.OwnerID)
+		assert.Equal(t, []string{models.PublishedStatus}, listed.Statuses)
+	})
+}
+
+func TestGetDeletedVideos(t *testing.T) {
+	t.Run("Should return deleted videos array and ok (200) status response when theres items in the trash", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		array := []models.Video{*mocked_data.GetValidVideo()}
+		arrayJson, _ := json.Marshal(array)
+
+		mocked_services.VideoServiceMockGetDeleted = func(page int64, pageSize int64) ([]models.Video, error) {
+			return array, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/trash/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.GetDeletedVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, arrayJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return empty array and not found (404) status response when the trash is empty", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockGetDeleted = func(page int64, pageSize int64) ([]models.Video, error) {
+			return nil, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/trash/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.GetDeletedVideos(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+	})
+}
+
+func TestRestoreVideo(t *testing.T) {
+	t.Run("Should return the restored video and ok (200) status response when the item is in the trash", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		model := mocked_data.GetValidVideo()
+		modelJson, _ := json.Marshal(model)
+
+		mocked_services.VideoServiceMockRestore = func(id primitive.ObjectID) (*models.Video, error) {
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/videos/"+model.ID.Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, modelJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return not found (404) status response when the item is not in the trash", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockRestore = func(id primitive.ObjectID) (*models.Video, error) {
+			return nil, interfaces.ErrNotFound
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/videos/"+primitive.NewObjectID().Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoByID(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Should return forbidden (403) status response when the video belongs to another user", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockRestore = func(id primitive.ObjectID) (*models.Video, error) {
+			return nil, interfaces.ErrNotOwner
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/videos/"+primitive.NewObjectID().Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoByID(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Should return unprocessable entity (422) status response when the category of the video is gone", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		categoryID := primitive.NewObjectID()
+
+		mocked_services.VideoServiceMockRestore = func(id primitive.ObjectID) (*models.Video, error) {
+			return nil, interfaces.CategoryNotFoundError{ID: categoryID}
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/trash/videos/"+primitive.NewObjectID().Hex()+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoByID(w, r)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, []byte(`{"error":"`+interfaces.CategoryNotFoundError{ID: categoryID}.Error()+`"}`), w.Body.Bytes())
+	})
+}
+
+func TestVideoRevisions(t *testing.T) {
+	t.Run("Should return the revisions and ok (200) status response when the video has revisions", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		model := mocked_data.GetValidVideo()
+		revisions := []models.VideoRevision{{Revision: model.Version, Video: *model}}
+		revisionsJson, _ := json.Marshal(revisions)
+		var received primitive.ObjectID
+
+		mocked_services.VideoServiceMockGetRevisions = func(id primitive.ObjectID) ([]models.VideoRevision, error) {
+			received = id
+			return revisions, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+model.ID.Hex()+"/revisions", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": model.ID.Hex()})
+		w := httptest.NewRecorder()
+
+		router.GetVideoRevisions(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, model.ID, received)
+		assert.Equal(t, revisionsJson, w.Body.Bytes())
+	})
+
+	t.Run("Should return empty array and not found (404) status response when the video has no revisions", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockGetRevisions = func(id primitive.ObjectID) ([]models.VideoRevision, error) {
+			return []models.VideoRevision{}, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+primitive.NewObjectID().Hex()+"/revisions", nil)
+		w := httptest.NewRecorder()
+
+		router.GetVideoRevisions(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, []byte("[]"), w.Body.Bytes())
+	})
+}
+
+func TestRestoreVideoRevision(t *testing.T) {
+	t.Run("Should return the restored video and ok (200) status response when the revision exists", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		model := mocked_data.GetValidVideo()
+		var receivedRevision, receivedVersion int64
+
+		mocked_services.VideoServiceMockRestoreRevision = func(id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+			receivedRevision, receivedVersion = revision, version
+			model.Version = version + 1
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/videos/"+model.ID.Hex()+"/revisions/2/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": model.ID.Hex(), "rev": "2"})
+		r.Header.Set("If-Match", `"3"`)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoRevision(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(2), receivedRevision)
+		assert.Equal(t, int64(3), receivedVersion)
+		assert.Equal(t, `"4"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Should return not found (404) status response when the revision is not a number", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("POST", "/api/v1/videos/"+primitive.NewObjectID().Hex()+"/revisions/foo/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"id": primitive.NewObjectID().Hex(), "rev": "foo"})
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoRevision(w, r)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Should return precondition failed (412) status response when the version is stale", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockRestoreRevision = func(id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+			return nil, interfaces.ErrVersionMismatch
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/videos/"+primitive.NewObjectID().Hex()+"/revisions/1/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"rev": "1"})
+		r.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoRevision(w, r)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("Should return unprocessable entity (422) status response when the category of the revision is deleted", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		categoryID := primitive.NewObjectID()
+
+		mocked_services.VideoServiceMockRestoreRevision = func(id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+			return nil, interfaces.CategoryNotFoundError{ID: categoryID}
+		}
+
+		r, _ := http.NewRequest("POST", "/api/v1/videos/"+primitive.NewObjectID().Hex()+"/revisions/1/restore", nil)
+		r = mux.SetURLVars(r, map[string]string{"rev": "1"})
+		w := httptest.NewRecorder()
+
+		router.RestoreVideoRevision(w, r)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestPurgeDeletedVideos(t *testing.T) {
+	t.Run("Should purge items older than TRASH_RETENTION_DAYS and return how many were removed", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		os.Setenv("TRASH_RETENTION_DAYS", "7")
+		defer os.Unsetenv("TRASH_RETENTION_DAYS")
+		var cutoff time.Time
+
+		mocked_services.VideoServiceMockPurge = func(ctx context.Context, deletedBefore time.Time) (int64, error) {
+			cutoff = deletedBefore
+			return 3, nil
+		}
+
+		r, _ := http.NewRequest("DELETE", "/api/v1/trash/videos", nil)
+		w := httptest.NewRecorder()
+
+		router.PurgeDeletedVideos(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []byte("{\"purged\":3}"), w.Body.Bytes())
+		assert.WithinDuration(t, time.Now().Add(-7*24*time.Hour), cutoff, time.Minute)
+	})
+}
+
+func TestPatchVideoByID(t *testing.T) {
+	t.Run("Should return the patched video and ok (200) status response when the patch is valid", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		model := mocked_data.GetValidVideo()
+		modelJson, _ := json.Marshal(model)
+		var received dto.PatchVideo
+
+		mocked_services.VideoServiceMockPatch = func(id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+			received = patch
+			return model, nil
+		}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/videos/"+model.ID.Hex(), bytes.NewReader([]byte(`{"titulo":"Patched title"}`)))
+		r.Header.Set("Content-Type", MergePatchContentType)
+		w := httptest.NewRecorder()
+
+		router.PatchVideoByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, modelJson, w.Body.Bytes())
+		assert.Equal(t, "Patched title", *received.Titulo)
+		assert.Nil(t, received.Url)
+	})
+
+	t.Run("Should return bad request (400) status response when removing a required field", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/videos/"+primitive.NewObjectID().Hex(), bytes.NewReader([]byte(`{"titulo":null}`)))
+		r.Header.Set("Content-Type", MergePatchContentType)
+		w := httptest.NewRecorder()
+
+		router.PatchVideoByID(w, r)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, []byte("{\"error\":\"Titulo cannot be removed.\"}"), w.Body.Bytes())
+	})
+
+	t.Run("Should return unsupported media type (415) status response when the body is not a merge patch", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/videos/"+primitive.NewObjectID().Hex(), bytes.NewReader([]byte(`{"titulo":"Patched title"}`)))
+		r.Header.Set("Content-Type", "application/xml")
+		w := httptest.NewRecorder()
+
+		router.PatchVideoByID(w, r)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+	})
+}
+
+func TestVideoConditionalRequests(t *testing.T) {
+	t.Run("Should return the version as ETag when getting a video", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		video := mocked_data.GetValidVideo()
+		video.Version = 3
+
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return video, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+video.ID.Hex(), nil)
+		w := httptest.NewRecorder()
+
+		router.GetVideoByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Should return not modified (304) status response when If-None-Match holds the current version", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		video := mocked_data.GetValidVideo()
+		video.Version = 3
+
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return video, nil
+		}
+
+		r, _ := http.NewRequest("GET", "/api/v1/videos/"+video.ID.Hex(), nil)
+		r.Header.Set("If-None-Match", `"2", W/"3"`)
+		w := httptest.NewRecorder()
+
+		router.GetVideoByID(w, r)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.Bytes())
+	})
+
+	t.Run("Should pass the If-Match version to the service and return the new ETag", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		video := mocked_data.GetValidVideo()
+		videoDtoJson, _ := json.Marshal(mocked_data.GetValidInsertVideoDto())
+		var received int64
+
+		mocked_services.VideoServiceMockUpdate = func(id primitive.ObjectID, dto dto.InsertVideo, version int64) (*models.Video, error) {
+			received = version
+			video.Version = version + 1
+			return video, nil
+		}
+
+		r, _ := http.NewRequest("PUT", "/api/v1/videos/"+video.ID.Hex(), bytes.NewReader(videoDtoJson))
+		r.Header.Set("If-Match", `"4"`)
+		w := httptest.NewRecorder()
+
+		router.UpdateVideoByID(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int64(4), received)
+		assert.Equal(t, `"5"`, w.Header().Get("ETag"))
+	})
+
+	t.Run("Should return precondition failed (412) status response when the version is stale", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+
+		mocked_services.VideoServiceMockPatch = func(id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+			return nil, interfaces.ErrVersionMismatch
+		}
+
+		r, _ := http.NewRequest("PATCH", "/api/v1/videos/"+primitive.NewObjectID().Hex(), bytes.NewReader([]byte(`{"titulo":"Patched title"}`)))
+		r.Header.Set("Content-Type", MergePatchContentType)
+		r.Header.Set("If-Match", `"1"`)
+		w := httptest.NewRecorder()
+
+		router.PatchVideoByID(w, r)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	})
+
+	t.Run("Should return precondition failed (412) status response when If-Match is not a version", func(t *testing.T) {
+		var router = VideoRouter{}
+		router.service = &mocked_services.VideoServiceMock{}
+		called := false
+
+		mocked_services.VideoServiceMockDelete = func(id primitive.ObjectID, version int64) error {
+			called = true
+			return nil
+		}
+
+		r, _ := http.NewRequest("DELETE", "/api/v1/videos/"+primitive.NewObjectID().Hex(), nil)
+		r.Header.Set("If-Match", "not-a-version")
+		w := httptest.NewRecorder()
+
+		router.DeleteVideoByID(w, r)
+
+		assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+		assert.False(t, called)
+	})
 }
@@ -1,7 +1,6 @@
 package rest
 
 import (
-	"github.com/auth0/go-jwt-middleware"
 	_ "github.com/cristovaoolegario/aluraflix-api/docs"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest/resources"
@@ -11,30 +10,187 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func ProvideRouter(videoRouter resources.VideoRouter, categoryRouter resources.CategoryRouter) mux.Router {
+func ProvideRouter(videoRouter resources.VideoRouter, categoryRouter resources.CategoryRouter, searchRouter resources.SearchRouter,
+	auditRouter resources.AuditRouter, apiKeyRouter resources.APIKeyRouter, userRouter resources.UserRouter,
+	playlistRouter resources.PlaylistRouter, devRouter resources.DevRouter, devIssuer *jwt.DevIssuer) mux.Router {
 	r := mux.Router{}
-	addVideosResources(videoRouter, &r, jwt.JwtMiddleware)
-	addCategoriesResources(categoryRouter, &r, jwt.JwtMiddleware)
+	authenticate := userRouter.Identify(apiKeyRouter.Authenticate(&jwt.Middleware{DevIssuer: devIssuer}))
+	authenticateOptional := userRouter.Identify(apiKeyRouter.Authenticate(&jwt.Middleware{CredentialsOptional: true, DevIssuer: devIssuer}))
+	addVideosResources(videoRouter, &r, authenticate)
+	addCategoriesResources(categoryRouter, &r, authenticate)
+	addSearchResources(searchRouter, &r, authenticate, authenticateOptional)
+	addAuditResources(auditRouter, &r, authenticate)
+	addAuthResources(apiKeyRouter, &r, authenticate)
+	addUserResources(userRouter, videoRouter, &r, authenticate)
+	addPlaylistResources(playlistRouter, &r, authenticate)
+	addDevResources(devRouter, &r)
 	addSwaggerDocumentation(&r)
 	return r
 }
 
-func addVideosResources(videoRouter resources.VideoRouter, r *mux.Router, middleware *jwtmiddleware.JWTMiddleware) {
+// route binds a handler to a method and path, behind the permissions the token
+// of the request must grant.
+type route struct {
+	method      string
+	path        string
+	handler     http.HandlerFunc
+	permissions []string
+}
+
+// handle registers the routes behind the authentication and the
+// authorization of their permissions.
+func handle(r *mux.Router, authenticate resources.Authenticator, routes []route) {
+	for _, route := range routes {
+		r.Handle(route.path, authenticate(resources.Authorize(route.handler, route.permissions...))).Methods(route.method)
+	}
+}
+
+// handleAnonymous registers the routes that also serve requests without
+// credentials, checking the permissions of the authenticated ones only.
+func handleAnonymous(r *mux.Router, authenticate resources.Authenticator, routes []route) {
+	for _, route := range routes {
+		r.Handle(route.path, authenticate(resources.AuthorizeAuthenticated(route.handler, route.permissions...))).Methods(route.method)
+	}
+}
+
+func addVideosResources(videoRouter resources.VideoRouter, r *mux.Router, authenticate resources.Authenticator) {
 	r.HandleFunc("/api/v1/videos/free", videoRouter.GetAllFreeVideos).Methods("GET")
-	r.Handle("/api/v1/videos", middleware.Handler(http.HandlerFunc(videoRouter.GetAllVideos))).Methods("GET")
-	r.Handle("/api/v1/videos/{id}", middleware.Handler(http.HandlerFunc(videoRouter.GetVideoByID))).Methods("GET")
-	r.Handle("/api/v1/videos", middleware.Handler(http.HandlerFunc(videoRouter.CreateVideo))).Methods("POST")
-	r.Handle("/api/v1/videos/{id}", middleware.Handler(http.HandlerFunc(videoRouter.UpdateVideoByID))).Methods("PUT")
-	r.Handle("/api/v1/videos/{id}", middleware.Handler(http.HandlerFunc(videoRouter.DeleteVideoByID))).Methods("DELETE")
-}
-
-func addCategoriesResources(categoryRouter resources.CategoryRouter, r *mux.Router, middleware *jwtmiddleware.JWTMiddleware) {
-	r.Handle("/api/v1/categories", middleware.Handler(http.HandlerFunc(categoryRouter.GetAllCategories))).Methods("GET")
-	r.Handle("/api/v1/categories/{id}", middleware.Handler(http.HandlerFunc(categoryRouter.GetCategoryByID))).Methods("GET")
-	r.Handle("/api/v1/categories/{id}/videos", middleware.Handler(http.HandlerFunc(categoryRouter.GetAllVideosByCategoryID))).Methods("GET")
-	r.Handle("/api/v1/categories", middleware.Handler(http.HandlerFunc(categoryRouter.CreateCategory))).Methods("POST")
-	r.Handle("/api/v1/categories/{id}", middleware.Handler(http.HandlerFunc(categoryRouter.UpdateCategoryByID))).Methods("PUT")
-	r.Handle("/api/v1/categories/{id}", middleware.Handler(http.HandlerFunc(categoryRouter.DeleteCategoryByID))).Methods("DELETE")
+	handle(r, authenticate, videoRoutes(videoRouter))
+}
+
+func videoRoutes(videoRouter resources.VideoRouter) []route {
+	read := []string{resources.ReadVideosPermission}
+	write := []string{resources.WriteVideosPermission}
+	remove := []string{resources.DeleteVideosPermission}
+	return []route{
+		{"GET", "/api/v1/videos", videoRouter.GetAllVideos, read},
+		{"GET", "/api/v1/videos/{id}", videoRouter.GetVideoByID, read},
+		{"POST", "/api/v1/videos", videoRouter.CreateVideo, write},
+		{"PUT", "/api/v1/videos/{id}", videoRouter.UpdateVideoByID, write},
+		{"PATCH", "/api/v1/videos/{id}", videoRouter.PatchVideoByID, write},
+		{"DELETE", "/api/v1/videos/{id}", videoRouter.DeleteVideoByID, remove},
+		{"GET", "/api/v1/trash/videos", videoRouter.GetDeletedVideos, read},
+		{"DELETE", "/api/v1/trash/videos", videoRouter.PurgeDeletedVideos, remove},
+		{"POST", "/api/v1/trash/videos/{id}/restore", videoRouter.RestoreVideoByID, write},
+		{"GET", "/api/v1/videos/{id}/revisions", videoRouter.GetVideoRevisions, read},
+		{"POST", "/api/v1/videos/{id}/revisions/{rev}/restore", videoRouter.RestoreVideoRevision, write},
+	}
+}
+
+func addCategoriesResources(categoryRouter resources.CategoryRouter, r *mux.Router, authenticate resources.Authenticator) {
+	handle(r, authenticate, categoryRoutes(categoryRouter))
+}
+
+func categoryRoutes(categoryRouter resources.CategoryRouter) []route {
+	read := []string{resources.ReadCategoriesPermission}
+	write := []string{resources.WriteCategoriesPermission}
+	remove := []string{resources.DeleteCategoriesPermission}
+	return []route{
+		{"GET", "/api/v1/categories", categoryRouter.GetAllCategories, read},
+		{"GET", "/api/v1/categories/{id}", categoryRouter.GetCategoryByID, read},
+		{"GET", "/api/v1/categories/{id}/videos", categoryRouter.GetAllVideosByCategoryID, []string{resources.ReadVideosPermission}},
+		{"POST", "/api/v1/categories", categoryRouter.CreateCategory, write},
+		{"PUT", "/api/v1/categories/{id}", categoryRouter.UpdateCategoryByID, write},
+		{"PATCH", "/api/v1/categories/{id}", categoryRouter.PatchCategoryByID, write},
+		{"DELETE", "/api/v1/categories/{id}", categoryRouter.DeleteCategoryByID, remove},
+		{"GET", "/api/v1/trash/categories", categoryRouter.GetDeletedCategories, read},
+		{"DELETE", "/api/v1/trash/categories", categoryRouter.PurgeDeletedCategories, remove},
+		{"POST", "/api/v1/trash/categories/{id}/restore", categoryRouter.RestoreCategoryByID, write},
+		{"GET", "/api/v1/categories/{id}/revisions", categoryRouter.GetCategoryRevisions, read},
+		{"POST", "/api/v1/categories/{id}/revisions/{rev}/restore", categoryRouter.RestoreCategoryRevision, write},
+	}
+}
+
+func addSearchResources(searchRouter resources.SearchRouter, r *mux.Router, authenticate resources.Authenticator, authenticateOptional resources.Authenticator) {
+	handle(r, authenticate, searchRoutes(searchRouter))
+	handleAnonymous(r, authenticateOptional, suggestRoutes(searchRouter))
+}
+
+func searchRoutes(searchRouter resources.SearchRouter) []route {
+	return []route{
+		{"GET", "/api/v1/search", searchRouter.Search, []string{resources.ReadVideosPermission, resources.ReadCategoriesPermission}},
+	}
+}
+
+// suggestRoutes serve anonymous requests the FREE videos, and authenticated
+// ones every title the permissions let them read.
+func suggestRoutes(searchRouter resources.SearchRouter) []route {
+	return []route{
+		{"GET", "/api/v1/suggest", searchRouter.Suggest, []string{resources.ReadVideosPermission, resources.ReadCategoriesPermission}},
+	}
+}
+
+func addAuditResources(auditRouter resources.AuditRouter, r *mux.Router, authenticate resources.Authenticator) {
+	handle(r, authenticate, auditRoutes(auditRouter))
+}
+
+func auditRoutes(auditRouter resources.AuditRouter) []route {
+	return []route{
+		{"GET", "/api/v1/videos/{id}/history", auditRouter.GetVideoHistory, []string{resources.ReadVideosPermission}},
+		{"GET", "/api/v1/categories/{id}/history", auditRouter.GetCategoryHistory, []string{resources.ReadCategoriesPermission}},
+		{"GET", "/api/v1/audit", auditRouter.GetAuditLog, []string{resources.ReadAuditPermission}},
+	}
+}
+
+func addAuthResources(apiKeyRouter resources.APIKeyRouter, r *mux.Router, authenticate resources.Authenticator) {
+	handle(r, authenticate, authRoutes(apiKeyRouter))
+}
+
+func authRoutes(apiKeyRouter resources.APIKeyRouter) []route {
+	manage := []string{resources.ManageAPIKeysPermission}
+	return []route{
+		{"GET", "/api/v1/auth/jwks/stats", resources.GetKeyCacheStats, []string{resources.ReadStatsPermission}},
+		{"GET", "/api/v1/api-keys", apiKeyRouter.GetAllAPIKeys, manage},
+		{"POST", "/api/v1/api-keys", apiKeyRouter.CreateAPIKey, manage},
+		{"DELETE", "/api/v1/api-keys/{id}", apiKeyRouter.RevokeAPIKey, manage},
+	}
+}
+
+func addUserResources(userRouter resources.UserRouter, videoRouter resources.VideoRouter, r *mux.Router, authenticate resources.Authenticator) {
+	handle(r, authenticate, userRoutes(userRouter, videoRouter))
+}
+
+// userRoutes serves the user of the token to any token, and every user to
+// the tokens granting read:users.
+func userRoutes(userRouter resources.UserRouter, videoRouter resources.VideoRouter) []route {
+	read := []string{resources.ReadUsersPermission}
+	return []route{
+		{"GET", "/api/v1/me", userRouter.GetMe, nil},
+		{"GET", "/api/v1/me/videos", videoRouter.GetMyVideos, nil},
+		{"GET", "/api/v1/users", userRouter.GetAllUsers, read},
+		{"GET", "/api/v1/users/{id}", userRouter.GetUserByID, read},
+		{"GET", "/api/v1/users/{id}/videos", videoRouter.GetUserVideos, read},
+	}
+}
+
+func addPlaylistResources(playlistRouter resources.PlaylistRouter, r *mux.Router, authenticate resources.Authenticator) {
+	handle(r, authenticate, playlistRoutes(playlistRouter))
+}
+
+func playlistRoutes(playlistRouter resources.PlaylistRouter) []route {
+	read := []string{resources.ReadPlaylistsPermission}
+	write := []string{resources.WritePlaylistsPermission}
+	remove := []string{resources.DeletePlaylistsPermission}
+	return []route{
+		{"GET", "/api/v1/playlists", playlistRouter.GetAllPlaylists, read},
+		{"GET", "/api/v1/playlists/{id}", playlistRouter.GetPlaylistByID, read},
+		{"POST", "/api/v1/playlists", playlistRouter.CreatePlaylist, write},
+		{"PUT", "/api/v1/playlists/{id}", playlistRouter.UpdatePlaylistByID, write},
+		{"DELETE", "/api/v1/playlists/{id}", playlistRouter.DeletePlaylistByID, remove},
+		{"POST", "/api/v1/playlists/{id}/items", playlistRouter.AddPlaylistItem, write},
+		{"PATCH", "/api/v1/playlists/{id}/items/{videoID}", playlistRouter.MovePlaylistItem, write},
+		{"DELETE", "/api/v1/playlists/{id}/items/{videoID}", playlistRouter.RemovePlaylistItem, write},
+	}
+}
+
+// addDevResources serves the development issuer, when there is one. Its
+// routes take no token, since they are how a token is obtained.
+func addDevResources(devRouter resources.DevRouter, r *mux.Router) {
+	if !devRouter.Enabled() {
+		return
+	}
+	r.HandleFunc("/.well-known/jwks.json", devRouter.GetJWKS).Methods("GET")
+	r.HandleFunc("/api/v1/dev/token", devRouter.CreateDevToken).Methods("POST")
 }
 
 func addSwaggerDocumentation(router *mux.Router) {
@@ -0,0 +1,178 @@
+package rest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/apikey"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/jwt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/rest/resources"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRoutes(t *testing.T) {
+	t.Run("Should require a permission on every route behind the JWT middleware but those of the token user", func(t *testing.T) {
+		routes := append(videoRoutes(resources.VideoRouter{}), categoryRoutes(resources.CategoryRouter{})...)
+		routes = append(routes, auditRoutes(resources.AuditRouter{})...)
+		routes = append(routes, authRoutes(resources.APIKeyRouter{})...)
+		routes = append(routes, playlistRoutes(resources.PlaylistRouter{})...)
+		routes = append(routes, searchRoutes(resources.SearchRouter{})...)
+		routes = append(routes, suggestRoutes(resources.SearchRouter{})...)
+		for _, route := range userRoutes(resources.UserRouter{}, resources.VideoRouter{}) {
+			if !strings.HasPrefix(route.path, "/api/v1/me") {
+				routes = append(routes, route)
+			}
+		}
+
+		for _, route := range routes {
+			assert.NotEmpty(t, route.permissions, route.method+" "+route.path)
+		}
+	})
+
+	t.Run("Should return unauthorized (401) before checking permissions When the request carries no token", func(t *testing.T) {
+		router := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, resources.APIKeyRouter{}, resources.UserRouter{}, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/61186c6fb3b4be6cd6fa8f4f", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should authorize a request authenticated with an API key by the scopes of the key", func(t *testing.T) {
+		key := mocked_data.GetValidAPIKey()
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return &key, nil
+		}
+		mocked_services.APIKeyServiceMockTouch = func(id primitive.ObjectID, usedAt time.Time) error {
+			return nil
+		}
+		apiKeyRouter := resources.ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		router := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, apiKeyRouter, resources.UserRouter{}, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/61186c6fb3b4be6cd6fa8f4f", nil)
+		r.Header.Set(apikey.Header, mocked_data.ValidAPIKeySecret)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, `{"error":"The token lacks the permissions this action requires: delete:categories."}`, w.Body.String())
+	})
+
+	t.Run("Should serve the user of the request to any principal but every user only with read:users", func(t *testing.T) {
+		key := mocked_data.GetValidAPIKey()
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return &key, nil
+		}
+		mocked_services.APIKeyServiceMockTouch = func(id primitive.ObjectID, usedAt time.Time) error {
+			return nil
+		}
+		mocked_services.UserServiceMockGetByID = func(id string) (*models.User, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		apiKeyRouter := resources.ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		userRouter := resources.ProvideUserRouter(&mocked_services.UserServiceMock{})
+		router := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, apiKeyRouter, userRouter, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+
+		for path, code := range map[string]int{"/api/v1/me": http.StatusNotFound, "/api/v1/users": http.StatusForbidden} {
+			r, _ := http.NewRequest("GET", path, nil)
+			r.Header.Set(apikey.Header, mocked_data.ValidAPIKeySecret)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, code, w.Code, path)
+		}
+	})
+
+	t.Run("Should suggest from every category When the request is authenticated with an API key", func(t *testing.T) {
+		key := mocked_data.GetValidAPIKey()
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return &key, nil
+		}
+		mocked_services.APIKeyServiceMockTouch = func(id primitive.ObjectID, usedAt time.Time) error {
+			return nil
+		}
+		var receivedFreeOnly bool
+		mocked_services.SearchServiceMockSuggest = func(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+			receivedFreeOnly = freeOnly
+			return nil, nil
+		}
+		apiKeyRouter := resources.ProvideAPIKeyRouter(&mocked_services.APIKeyServiceMock{})
+		searchRouter := resources.ProvideSearchRouter(&mocked_services.SearchServiceMock{})
+		router := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, searchRouter, resources.AuditRouter{}, apiKeyRouter, resources.UserRouter{}, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+
+		r, _ := http.NewRequest("GET", "/api/v1/suggest?prefix=go", nil)
+		r.Header.Set(apikey.Header, mocked_data.ValidAPIKeySecret)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.False(t, receivedFreeOnly)
+
+		r.Header.Set(apikey.Header, "invalid")
+		mocked_services.APIKeyServiceMockGetByHash = func(hash string) (*models.APIKey, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should only trust the tokens of the development issuer given to the router", func(t *testing.T) {
+		os.Setenv("AUD", "https://unit-test-audience/")
+		defer os.Unsetenv("AUD")
+		key, _ := rsa.GenerateKey(rand.Reader, 1024)
+		issuer, _ := jwt.NewDevIssuer("http://localhost:3000/", "https://unit-test-audience/", key)
+		signed, _ := issuer.Mint("dev|alice", nil, time.Hour)
+		mocked_services.UserServiceMockSave = func(user models.User) (*models.User, error) {
+			return &user, nil
+		}
+		userRouter := resources.ProvideUserRouter(&mocked_services.UserServiceMock{})
+		disabled := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, resources.APIKeyRouter{}, userRouter, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+		enabled := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, resources.APIKeyRouter{}, userRouter, resources.PlaylistRouter{}, resources.ProvideDevRouter(issuer), issuer)
+		r, _ := http.NewRequest("DELETE", "/api/v1/categories/61186c6fb3b4be6cd6fa8f4f", nil)
+		r.Header.Set("Authorization", "Bearer "+signed)
+
+		w := httptest.NewRecorder()
+		disabled.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = httptest.NewRecorder()
+		enabled.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("Should only serve the development issuer when there is one", func(t *testing.T) {
+		key, _ := rsa.GenerateKey(rand.Reader, 1024)
+		issuer, _ := jwt.NewDevIssuer("http://localhost:3000/", "", key)
+		disabled := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, resources.APIKeyRouter{}, resources.UserRouter{}, resources.PlaylistRouter{}, resources.DevRouter{}, nil)
+		enabled := ProvideRouter(resources.VideoRouter{}, resources.CategoryRouter{}, resources.SearchRouter{}, resources.AuditRouter{}, resources.APIKeyRouter{}, resources.UserRouter{}, resources.PlaylistRouter{}, resources.ProvideDevRouter(issuer), issuer)
+
+		for _, path := range []string{"/.well-known/jwks.json", "/api/v1/dev/token"} {
+			method := "GET"
+			if path == "/api/v1/dev/token" {
+				method = "POST"
+			}
+			r, _ := http.NewRequest(method, path, nil)
+			w := httptest.NewRecorder()
+			disabled.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusNotFound, w.Code, path)
+
+			w = httptest.NewRecorder()
+			enabled.ServeHTTP(w, r)
+			assert.Equal(t, http.StatusOK, w.Code, path)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IAPIKeyService stores the API keys, which are looked up by the hash of their
+// secret.
+type IAPIKeyService interface {
+	GetAll() ([]models.APIKey, error)
+	GetByHash(hash string) (*models.APIKey, error)
+	Create(ctx context.Context, key models.APIKey) (*models.APIKey, error)
+	Revoke(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error)
+	Touch(id primitive.ObjectID, usedAt time.Time) error
+}
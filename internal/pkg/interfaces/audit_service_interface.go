@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// IAuditService reads the audit log the video and category services append
+// to on every write. GetAll lists the newest entries first and also returns
+// how many entries match the filter across all pages.
+type IAuditService interface {
+	GetAll(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error)
+}
@@ -1,17 +1,34 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ICategoryService stores the categories, stamping each write with the subject
+// of ctx.
 type ICategoryService interface {
-	GetAll(filter string, page int64, pageSize int64) ([]models.Category, error)
+	GetAll(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error)
+	GetAllAfter(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error)
 	GetById(id primitive.ObjectID) (*models.Category, error)
-	Create(insertCategory dto.InsertCategory) (*models.Category, error)
-	Update(id primitive.ObjectID, newData dto.InsertCategory) (*models.Category, error)
-	Delete(id primitive.ObjectID) error
-	GetVideosByCategoryId(id primitive.ObjectID) ([]models.Video, error)
+	Create(ctx context.Context, insertCategory dto.InsertCategory) (*models.Category, error)
+	// Update, Patch and Delete return ErrVersionMismatch unless version is zero
+	// or the stored one.
+	Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertCategory, version int64) (*models.Category, error)
+	Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error)
+	Delete(ctx context.Context, id primitive.ObjectID, version int64) error
+	GetVideosByCategoryId(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error)
+	GetVideosByCategoryIdAfter(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error)
 	GetFreeCategory() *models.Category
+	GetDeleted(page int64, pageSize int64) ([]models.Category, error)
+	Restore(ctx context.Context, id primitive.ObjectID) (*models.Category, error)
+	// Purge removes the categories deleted before deletedBefore from the trash
+	// ctx may purge.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetRevisions(id primitive.ObjectID) ([]models.CategoryRevision, error)
+	RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Category, error)
 }
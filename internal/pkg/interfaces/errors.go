@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Errors shared by every storage backend, so the routers can map them to a status code.
+var (
+	ErrNotFound           = errors.New("the resource does not exist")
+	ErrCategoryHasVideos  = errors.New("category still has videos")
+	ErrProtectedCategory  = errors.New("the FREE category cannot be deleted or renamed")
+	ErrCategoryNotFound   = errors.New("category not found")
+	ErrVersionMismatch    = errors.New("the resource was changed by someone else")
+	ErrNotOwner           = errors.New("only the owner of the resource or an admin can change it")
+	ErrVideoNotFound      = errors.New("the video does not exist or was deleted")
+	ErrVideoInPlaylist    = errors.New("the video is already in the playlist")
+	ErrVideoNotInPlaylist = errors.New("the video is not in the playlist")
+)
+
+// CategoryNotFoundError reports which category a video points at does not
+// exist, and matches ErrCategoryNotFound with errors.Is.
+type CategoryNotFoundError struct {
+	ID primitive.ObjectID
+}
+
+func (e CategoryNotFoundError) Error() string {
+	return "Category with id " + e.ID.Hex() + " dont exists."
+}
+
+func (e CategoryNotFoundError) Is(target error) bool {
+	return target == ErrCategoryNotFound
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IPlaylistService stores the playlists, which only their owner or an admin
+// may change.
+type IPlaylistService interface {
+	GetAll(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error)
+	GetByID(id primitive.ObjectID) (*models.Playlist, error)
+	Create(ctx context.Context, insertPlaylist dto.InsertPlaylist) (*models.Playlist, error)
+	// The writes return ErrVersionMismatch unless version is zero or the stored
+	// one.
+	Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error)
+	Delete(ctx context.Context, id primitive.ObjectID, version int64) error
+	AddVideo(ctx context.Context, id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error)
+	RemoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error)
+	MoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error)
+}
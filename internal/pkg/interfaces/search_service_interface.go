@@ -0,0 +1,13 @@
+package interfaces
+
+import "github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+
+// ISearchService searches the videos and categories by their titles and
+// descriptions.
+type ISearchService interface {
+	// Search returns at most limit items of each type, most relevant first.
+	Search(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error)
+	// Suggest returns at most limit titles starting with prefix, in
+	// alphabetical order.
+	Suggest(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// IUserService stores the users the API has seen a token of, by subject.
+type IUserService interface {
+	GetAll(page int64, pageSize int64) ([]models.User, int64, error)
+	GetByID(id string) (*models.User, error)
+	// Save creates the user or refreshes its profile, keeping when it was
+	// created.
+	Save(user models.User) (*models.User, error)
+}
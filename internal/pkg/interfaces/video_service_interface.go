@@ -1,16 +1,34 @@
 package interfaces
 
 import (
+	"context"
+	"time"
+
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// IVideoService stores the videos, stamping each write with the subject of ctx.
 type IVideoService interface {
 	GetAllFreeVideos() ([]models.Video, error)
-	GetAll(filter string, page int64, pageSize int64) ([]models.Video, error)
+	GetAll(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error)
+	GetAllAfter(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error)
+	GetFacets(filter dto.VideoFilter) (*dto.VideoFacets, error)
 	GetByID(id primitive.ObjectID) (*models.Video, error)
-	Create(video dto.InsertVideo) (*models.Video, error)
-	Update(id primitive.ObjectID, newData dto.InsertVideo) (*models.Video, error)
-	Delete(id primitive.ObjectID) error
+	Create(ctx context.Context, video dto.InsertVideo) (*models.Video, error)
+	// Update, Patch and Delete return ErrVersionMismatch unless version is zero
+	// or the stored one.
+	Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error)
+	Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error)
+	Delete(ctx context.Context, id primitive.ObjectID, version int64) error
+	GetDeleted(page int64, pageSize int64) ([]models.Video, error)
+	Restore(ctx context.Context, id primitive.ObjectID) (*models.Video, error)
+	// Purge removes the videos deleted before deletedBefore from the trash ctx
+	// may purge.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	GetRevisions(id primitive.ObjectID) ([]models.VideoRevision, error)
+	RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Video, error)
+	// PublishDue publishes the scheduled videos due by now and returns them.
+	PublishDue(ctx context.Context, now time.Time) ([]models.Video, error)
 }
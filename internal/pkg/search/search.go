@@ -0,0 +1,267 @@
+// Package search ranks and highlights full-text matches. The Mongo backend
+// ranks with its text index and only borrows the highlighting, while the
+// other backends score every item in process with the same field weights.
+package search
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+// Weights of a match in each field, shared with the Mongo text indexes.
+const (
+	TituloWeight    = 3
+	DescricaoWeight = 1
+)
+
+// Language is the default language of the Mongo text indexes.
+const Language = "portuguese"
+
+const snippetWidth = 160
+
+// stopWords are left out of queries, as the Mongo text index does.
+var stopWords = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "e": true, "de": true, "da": true, "do": true, "das": true,
+	"dos": true, "em": true, "no": true, "na": true, "nos": true, "nas": true, "um": true, "uma": true,
+	"para": true, "com": true, "por": true, "que": true, "the": true, "of": true, "and": true, "to": true, "in": true,
+}
+
+// Terms returns the distinct folded words of a query, without stop words.
+func Terms(query string) []string {
+	var terms []string
+	seen := map[string]bool{}
+	for _, word := range tokenize(query) {
+		if !stopWords[word] && !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// tokenize splits text into words folded by dto.NormalizeSearch.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(dto.NormalizeSearch(text), isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// field is a piece of text weighted by how much a match in it counts.
+type field struct {
+	name   string
+	text   string
+	weight float64
+}
+
+// score sums, for each field, its weight times the share of its words that
+// are query terms, so a term in a short title outranks one lost in a long
+// description. Zero means that no term matched.
+func score(terms []string, fields []field) float64 {
+	wanted := map[string]bool{}
+	for _, term := range terms {
+		wanted[term] = true
+	}
+	var total float64
+	for _, f := range fields {
+		words := tokenize(f.text)
+		matches := 0
+		for _, word := range words {
+			if wanted[word] {
+				matches++
+			}
+		}
+		if matches > 0 {
+			total += f.weight * float64(matches) / float64(len(words))
+		}
+	}
+	return total
+}
+
+// highlights returns the snippet of each field where a term matched.
+func highlights(terms []string, fields []field) map[string]string {
+	snippets := map[string]string{}
+	for _, f := range fields {
+		if snippet := Snippet(f.text, terms, snippetWidth); snippet != "" {
+			snippets[f.name] = snippet
+		}
+	}
+	return snippets
+}
+
+func videoFields(video models.Video) []field {
+	return []field{{"titulo", video.Titulo, TituloWeight}, {"descricao", video.Descricao, DescricaoWeight}}
+}
+
+func categoryFields(category models.Category) []field {
+	return []field{{"titulo", category.Titulo, TituloWeight}}
+}
+
+// MatchVideo scores a video against the terms, reporting false when none matched.
+func MatchVideo(video models.Video, terms []string) (dto.VideoHit, bool) {
+	fields := videoFields(video)
+	s := score(terms, fields)
+	if s == 0 {
+		return dto.VideoHit{}, false
+	}
+	return dto.VideoHit{Video: video, Score: s, Highlights: highlights(terms, fields)}, true
+}
+
+// MatchCategory is the MatchVideo counterpart for categories.
+func MatchCategory(category models.Category, terms []string) (dto.CategoryHit, bool) {
+	fields := categoryFields(category)
+	s := score(terms, fields)
+	if s == 0 {
+		return dto.CategoryHit{}, false
+	}
+	return dto.CategoryHit{Category: category, Score: s, Highlights: highlights(terms, fields)}, true
+}
+
+// VideoHighlights highlights a video ranked elsewhere, such as by a text index.
+func VideoHighlights(video models.Video, terms []string) map[string]string {
+	return highlights(terms, videoFields(video))
+}
+
+// CategoryHighlights is the VideoHighlights counterpart for categories.
+func CategoryHighlights(category models.Category, terms []string) map[string]string {
+	return highlights(terms, categoryFields(category))
+}
+
+// RankVideos orders hits from the most relevant, oldest first among equals,
+// keeping at most limit of them.
+func RankVideos(hits []dto.VideoHit, limit int64) []dto.VideoHit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Video.ID.Hex() < hits[j].Video.ID.Hex()
+	})
+	if int64(len(hits)) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// RankCategories is the RankVideos counterpart for categories.
+func RankCategories(hits []dto.CategoryHit, limit int64) []dto.CategoryHit {
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Category.ID.Hex() < hits[j].Category.ID.Hex()
+	})
+	if int64(len(hits)) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// Snippet returns the part of text around its first matched term, at most
+// about width characters long, with the matched words wrapped in <mark>.
+// Everything else is HTML escaped. It returns "" when no term matches.
+func Snippet(text string, terms []string, width int) string {
+	wanted := map[string]bool{}
+	for _, term := range terms {
+		wanted[term] = true
+	}
+	runes := []rune(text)
+	words := wordSpans(runes)
+	first := -1
+	for i, word := range words {
+		if wanted[dto.NormalizeSearch(string(runes[word.start:word.end]))] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start, end := 0, len(runes)
+	if len(runes) > width {
+		start = words[first].start - width/3
+		if start < 0 {
+			start = 0
+		}
+		end = start + width
+		if end > len(runes) {
+			end, start = len(runes), len(runes)-width
+		}
+		// Never cut a word in half.
+		for start > 0 && !isSeparator(runes[start-1]) {
+			start++
+		}
+		for end < len(runes) && !isSeparator(runes[end]) && end > words[first].end {
+			end--
+		}
+	}
+
+	var builder strings.Builder
+	if start > 0 {
+		builder.WriteString("…")
+	}
+	position := start
+	for _, word := range words {
+		if word.start < start || word.end > end {
+			continue
+		}
+		original := string(runes[word.start:word.end])
+		if !wanted[dto.NormalizeSearch(original)] {
+			continue
+		}
+		builder.WriteString(html.EscapeString(string(runes[position:word.start])))
+		builder.WriteString("<mark>" + html.EscapeString(original) + "</mark>")
+		position = word.end
+	}
+	builder.WriteString(html.EscapeString(string(runes[position:end])))
+	if end < len(runes) {
+		builder.WriteString("…")
+	}
+	return builder.String()
+}
+
+type span struct{ start, end int }
+
+func wordSpans(runes []rune) []span {
+	var spans []span
+	start := -1
+	for i, r := range runes {
+		if isSeparator(r) {
+			if start >= 0 {
+				spans = append(spans, span{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(runes)})
+	}
+	return spans
+}
+
+// SortSuggestions orders suggestions alphabetically by their folded title,
+// keeping at most limit of them.
+func SortSuggestions(suggestions []dto.Suggestion, limit int64) []dto.Suggestion {
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := dto.NormalizeSearch(suggestions[i].Titulo), dto.NormalizeSearch(suggestions[j].Titulo)
+		if a != b {
+			return a < b
+		}
+		if suggestions[i].Type != suggestions[j].Type {
+			return suggestions[i].Type > suggestions[j].Type
+		}
+		return suggestions[i].ID.Hex() < suggestions[j].ID.Hex()
+	})
+	if int64(len(suggestions)) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
@@ -0,0 +1,89 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTerms(t *testing.T) {
+	t.Run("Should fold the words of the query and drop stop words and repetitions", func(t *testing.T) {
+		assert.Equal(t, []string{"logica", "programacao"}, Terms("Lógica de PROGRAMAÇÃO, lógica!"))
+	})
+
+	t.Run("Should return no terms When the query has no words", func(t *testing.T) {
+		assert.Nil(t, Terms(" !? de "))
+	})
+}
+
+func TestMatchVideo(t *testing.T) {
+	t.Run("Should not match When no term is in the video", func(t *testing.T) {
+		_, ok := MatchVideo(models.Video{Titulo: "Go", Descricao: "Concurrency"}, []string{"python"})
+
+		assert.False(t, ok)
+	})
+
+	t.Run("Should rank a match in the title above one in the description", func(t *testing.T) {
+		inTitle, _ := MatchVideo(models.Video{Titulo: "Go concurrency", Descricao: "Channels and goroutines"}, []string{"concurrency"})
+		inDescription, _ := MatchVideo(models.Video{Titulo: "Go channels", Descricao: "About concurrency"}, []string{"concurrency"})
+
+		assert.Greater(t, inTitle.Score, inDescription.Score)
+	})
+
+	t.Run("Should highlight only the fields that matched", func(t *testing.T) {
+		hit, ok := MatchVideo(models.Video{Titulo: "Introdução à Programação", Descricao: "Primeiros passos"}, []string{"programacao"})
+
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"titulo": "Introdução à <mark>Programação</mark>"}, hit.Highlights)
+	})
+}
+
+func TestRankVideos(t *testing.T) {
+	t.Run("Should order by score and keep at most limit hits", func(t *testing.T) {
+		low := dto.VideoHit{Video: models.Video{ID: primitive.NewObjectID()}, Score: 0.5}
+		high := dto.VideoHit{Video: models.Video{ID: primitive.NewObjectID()}, Score: 2}
+		middle := dto.VideoHit{Video: models.Video{ID: primitive.NewObjectID()}, Score: 1}
+
+		assert.Equal(t, []dto.VideoHit{high, middle}, RankVideos([]dto.VideoHit{low, high, middle}, 2))
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	t.Run("Should return no snippet When no term matches", func(t *testing.T) {
+		assert.Equal(t, "", Snippet("Go concurrency", []string{"python"}, 160))
+	})
+
+	t.Run("Should escape html around the marked words", func(t *testing.T) {
+		assert.Equal(t, "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; &amp; more", Snippet("<b>Go</b> & more", []string{"go"}, 160))
+	})
+
+	t.Run("Should cut long text around the first match without splitting words", func(t *testing.T) {
+		text := strings.Repeat("lorem ipsum ", 30) + "Programação concorrente " + strings.Repeat("dolor sit ", 30)
+
+		snippet := Snippet(text, []string{"concorrente"}, 60)
+
+		assert.True(t, strings.HasPrefix(snippet, "…"))
+		assert.True(t, strings.HasSuffix(snippet, "…"))
+		assert.Contains(t, snippet, "Programação <mark>concorrente</mark>")
+		for _, word := range strings.Fields(strings.Trim(snippet, "…")) {
+			assert.Contains(t, []string{"lorem", "ipsum", "Programação", "<mark>concorrente</mark>", "dolor", "sit"}, word)
+		}
+	})
+}
+
+func TestSortSuggestions(t *testing.T) {
+	t.Run("Should order by folded title, videos first among equals, keeping at most limit", func(t *testing.T) {
+		category := dto.Suggestion{ID: primitive.NewObjectID(), Titulo: "Go", Type: dto.CategorySuggestion}
+		video := dto.Suggestion{ID: primitive.NewObjectID(), Titulo: "go", Type: dto.VideoSuggestion}
+		accented := dto.Suggestion{ID: primitive.NewObjectID(), Titulo: "Árvores", Type: dto.VideoSuggestion}
+		last := dto.Suggestion{ID: primitive.NewObjectID(), Titulo: "Zig", Type: dto.VideoSuggestion}
+
+		sorted := SortSuggestions([]dto.Suggestion{last, category, video, accented}, 3)
+
+		assert.Equal(t, []dto.Suggestion{accented, video, category}, sorted)
+	})
+}
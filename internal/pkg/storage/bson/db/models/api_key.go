@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeySubjectPrefix starts the subject a request authenticated with an API
+// key acts as, followed by the id of the key.
+const APIKeySubjectPrefix = "apikey|"
+
+// APIKey lets a service client call the API without a token, with the scopes
+// it was granted until it expires or is revoked. Only the hash of the key is
+// stored: the key itself is shown once, when it is created, and its prefix
+// tells the keys apart afterwards.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
+	Name       string             `bson:"name" json:"name" example:"Nightly export"`
+	Prefix     string             `bson:"prefix" json:"prefix" example:"afk_Xq3v"`
+	Hash       string             `bson:"hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes" example:"read:videos,read:categories"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expiresAt" example:"2021-11-12T04:46:49Z"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"lastUsedAt,omitempty" example:"2021-08-14T04:46:49Z"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revokedAt,omitempty" example:"2021-08-14T04:46:49Z"`
+	RevokedBy  string             `bson:"revoked_by,omitempty" json:"revokedBy,omitempty" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+	CreatedAt  time.Time          `bson:"created_at" json:"createdAt" example:"2021-08-14T04:46:49Z"`
+	CreatedBy  string             `bson:"created_by" json:"createdBy" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+}
+
+// Subject is who a request authenticated with the key acts as.
+func (key APIKey) Subject() string {
+	return APIKeySubjectPrefix + key.ID.Hex()
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Audit records when a document was created and last written, and by whom.
+// The authors are the subject of the token the request was made with, empty
+// for the documents written before it was recorded.
+type Audit struct {
+	CreatedAt time.Time `bson:"created_at" json:"createdAt" example:"2021-08-14T04:46:49Z"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updatedAt" example:"2021-08-14T04:46:49Z"`
+	CreatedBy string    `bson:"created_by" json:"createdBy" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+	UpdatedBy string    `bson:"updated_by" json:"updatedBy" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+}
+
+// NewAudit stamps a document created by author at the given time.
+func NewAudit(author string, at time.Time) Audit {
+	return Audit{CreatedAt: at, UpdatedAt: at, CreatedBy: author, UpdatedBy: author}
+}
+
+// Touch stamps a write made by author at the given time.
+func (audit *Audit) Touch(author string, at time.Time) {
+	audit.UpdatedAt = at
+	audit.UpdatedBy = author
+}
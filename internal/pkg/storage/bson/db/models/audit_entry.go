@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Resources whose writes are recorded in the audit log.
+const (
+	VideoResource    = "videos"
+	CategoryResource = "categories"
+)
+
+// Operations recorded in the audit log. Update covers both replacing and
+// patching a document, and publish the scheduler publishing a video.
+const (
+	CreateOperation  = "create"
+	UpdateOperation  = "update"
+	DeleteOperation  = "delete"
+	RestoreOperation = "restore"
+	PublishOperation = "publish"
+)
+
+// AuditEntry records one write to a video or category: who made it, when, and
+// the value each field it changed had before and after it.
+type AuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
+	Resource   string             `bson:"resource" json:"resource" example:"videos"`
+	ResourceID primitive.ObjectID `bson:"resource_id" json:"resourceID" example:"000000000000000000000000"`
+	Operation  string             `bson:"operation" json:"operation" example:"update"`
+	Actor      string             `bson:"actor" json:"actor" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+	Timestamp  time.Time          `bson:"timestamp" json:"timestamp" example:"2021-08-14T04:46:49Z"`
+	Changes    []FieldChange      `bson:"changes" json:"changes"`
+}
+
+// FieldChange is the value of a field before and after a write, null when the
+// field was absent. Fields are named as in the JSON of the resource.
+type FieldChange struct {
+	Field  string      `bson:"field" json:"field" example:"titulo"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
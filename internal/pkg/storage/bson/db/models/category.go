@@ -1,22 +1,32 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Category represents a model of categories
+// Category represents a model of categories. TituloSearch holds the title folded by
+// dto.NormalizeSearch, which the search parameter is matched against.
 type Category struct {
-	ID     primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
-	Titulo string             `bson:"titulo" json:"titulo" example:"Example category"`
-	Cor    string             `bson:"cor" json:"cor" example:"Red"`
-	Active bool               `bson:"active" json:"active" example:"true"`
+	ID           primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
+	Titulo       string             `bson:"titulo" json:"titulo" example:"Example category"`
+	Cor          string             `bson:"cor" json:"cor" example:"Red"`
+	Active       bool               `bson:"active" json:"active" example:"true"`
+	DeletedAt    *time.Time         `bson:"deleted_at,omitempty" json:"deletedAt,omitempty" example:"2021-08-14T04:46:49Z"`
+	Version      int64              `bson:"version" json:"version" example:"1"`
+	TituloSearch string             `bson:"titulo_search" json:"-"`
+	Audit        `bson:",inline"`
+	Ownership    `bson:",inline"`
 }
 
 func GetFreeCategory() *Category {
 	return &Category{
-		ID:     primitive.ObjectID{},
-		Titulo: "FREE",
-		Cor:    "FREE",
-		Active: true,
+		ID:           primitive.ObjectID{},
+		Titulo:       "FREE",
+		TituloSearch: "free",
+		Cor:          "FREE",
+		Active:       true,
+		Version:      1,
 	}
 }
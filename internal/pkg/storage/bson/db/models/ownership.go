@@ -0,0 +1,19 @@
+package models
+
+// Ownership records the user a document belongs to, by the subject of the
+// token it was created with. Only its owner and admins may change it, and
+// documents created before it was recorded belong to their author, or to
+// nobody when they have none.
+type Ownership struct {
+	OwnerID string `bson:"owner_id" json:"ownerId" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+}
+
+// Owned is implemented by the documents carrying an Ownership.
+type Owned interface {
+	Owner() string
+}
+
+// Owner returns the subject of the user the document belongs to.
+func (ownership Ownership) Owner() string {
+	return ownership.OwnerID
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Visibilities of a playlist. A private playlist is only seen by its owner
+// and admins.
+const (
+	PublicVisibility  = "public"
+	PrivateVisibility = "private"
+)
+
+// Playlist represents a model of playlists, an ordered list of videos of its
+// owner. The position of an item is its place in Items, starting from 1.
+// Deleting a video removes it from every playlist, and restoring the video
+// does not add it back.
+type Playlist struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
+	Titulo     string             `bson:"titulo" json:"titulo" example:"Example playlist"`
+	Descricao  string             `bson:"descricao" json:"descricao" example:"Example description"`
+	Visibility string             `bson:"visibility" json:"visibility" example:"private"`
+	Items      []PlaylistItem     `bson:"items" json:"items"`
+	Version    int64              `bson:"version" json:"version" example:"1"`
+	Audit      `bson:",inline"`
+	Ownership  `bson:",inline"`
+}
+
+// PlaylistItem is a video added to a playlist.
+type PlaylistItem struct {
+	VideoID primitive.ObjectID `bson:"video_id" json:"videoID" example:"000000000000000000000000"`
+	AddedAt time.Time          `bson:"added_at" json:"addedAt" example:"2021-08-14T04:46:49Z"`
+}
+
+// IsPublic reports whether everyone may see the playlist.
+func (playlist Playlist) IsPublic() bool {
+	return playlist.Visibility == PublicVisibility
+}
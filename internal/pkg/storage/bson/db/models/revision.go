@@ -0,0 +1,15 @@
+package models
+
+// VideoRevision is a video as it was at one of its versions, kept when an
+// update replaced that version. Revision is the version it was.
+type VideoRevision struct {
+	Revision int64 `bson:"revision" json:"revision" example:"1"`
+	Video    Video `bson:"document" json:"video"`
+}
+
+// CategoryRevision is a category as it was at one of its versions, kept when
+// an update replaced that version. Revision is the version it was.
+type CategoryRevision struct {
+	Revision int64    `bson:"revision" json:"revision" example:"1"`
+	Category Category `bson:"document" json:"category"`
+}
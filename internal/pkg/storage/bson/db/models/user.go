@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// User is someone the API has seen a token of, identified by the subject of
+// their tokens. The profile fields hold the standard claims of their latest
+// token, empty when the issuer does not include them.
+type User struct {
+	ID         string    `bson:"_id" json:"id" example:"auth0|611743c4a8e4b1006a7e5e8c"`
+	Name       string    `bson:"name" json:"name" example:"Alice Example"`
+	Nickname   string    `bson:"nickname" json:"nickname" example:"alice"`
+	Email      string    `bson:"email" json:"email" example:"alice@example.com"`
+	Picture    string    `bson:"picture" json:"picture" example:"https://example.com/alice.png"`
+	CreatedAt  time.Time `bson:"created_at" json:"createdAt" example:"2021-08-14T04:46:49Z"`
+	LastSeenAt time.Time `bson:"last_seen_at" json:"lastSeenAt" example:"2021-08-14T04:46:49Z"`
+}
+
+// SameProfile reports whether both users hold the same profile fields.
+func (user User) SameProfile(other User) bool {
+	return user.Name == other.Name && user.Nickname == other.Nickname &&
+		user.Email == other.Email && user.Picture == other.Picture
+}
@@ -1,17 +1,40 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Video represents a model of videos
+// Publication statuses of a video. A scheduled video is published once its
+// PublishAt time comes.
+const (
+	DraftStatus       = "draft"
+	ScheduledStatus   = "scheduled"
+	PublishedStatus   = "published"
+	UnpublishedStatus = "unpublished"
+)
+
+// Video represents a model of videos. TituloSearch holds the title folded by
+// dto.NormalizeSearch, which the search parameter is matched against, and
+// UrlHost the host of Url folded by dto.URLHost, which the host filter and
+// facet use. Status is one of the publication statuses, and PublishAt the
+// time a scheduled video is, or was, published at.
 type Video struct {
-	ID         primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
-	CategoryID primitive.ObjectID `bson:"category_id" json:"categoriaID" example:"000000000000000000000000"`
-	Titulo     string             `bson:"titulo" json:"titulo" example:"Example video"`
-	Descricao  string             `bson:"descricao" json:"descricao" example:"Example description"`
-	Url        string             `bson:"url" json:"url" example:"https://www.example-url.com"`
-	Active     bool               `bson:"active" json:"active" example:"true"`
+	ID           primitive.ObjectID `bson:"_id" json:"id" example:"000000000000000000000000"`
+	CategoryID   primitive.ObjectID `bson:"category_id" json:"categoriaID" example:"000000000000000000000000"`
+	Titulo       string             `bson:"titulo" json:"titulo" example:"Example video"`
+	Descricao    string             `bson:"descricao" json:"descricao" example:"Example description"`
+	Url          string             `bson:"url" json:"url" example:"https://www.example-url.com"`
+	Active       bool               `bson:"active" json:"active" example:"true"`
+	DeletedAt    *time.Time         `bson:"deleted_at,omitempty" json:"deletedAt,omitempty" example:"2021-08-14T04:46:49Z"`
+	Version      int64              `bson:"version" json:"version" example:"1"`
+	TituloSearch string             `bson:"titulo_search" json:"-"`
+	UrlHost      string             `bson:"url_host" json:"-"`
+	Status       string             `bson:"status" json:"status" example:"published"`
+	PublishAt    *time.Time         `bson:"publish_at,omitempty" json:"publishAt,omitempty" example:"2021-08-14T04:46:49Z"`
+	Audit        `bson:",inline"`
+	Ownership    `bson:",inline"`
 }
 
 var _ interface{} = (*Video)(nil)
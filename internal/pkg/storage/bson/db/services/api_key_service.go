@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type APIKeyService struct {
+	apiKeyCollection *mongo.Collection
+}
+
+func ProvideAPIKeyService(database DatabaseService) APIKeyService {
+	return APIKeyService{database.Collection(APIKeyCollection)}
+}
+
+func (ks *APIKeyService) GetAll() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	cursor, err := ks.apiKeyCollection.Find(context.TODO(), bson.M{},
+		options.Find().SetSort(bson.D{primitive.E{Key: "_id", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	err = cursor.All(context.TODO(), &keys)
+	return keys, err
+}
+
+func (ks *APIKeyService) GetByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := ks.apiKeyCollection.FindOne(context.TODO(), bson.M{"hash": hash}).Decode(&key); err != nil {
+		return nil, notFound(err)
+	}
+	return &key, nil
+}
+
+func (ks *APIKeyService) Create(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	key.CreatedBy, key.CreatedAt = domain.Stamp(ctx)
+	if _, err := ks.apiKeyCollection.InsertOne(context.TODO(), &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (ks *APIKeyService) Revoke(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	author, revokedAt := domain.Stamp(ctx)
+	var key models.APIKey
+	err := ks.apiKeyCollection.FindOneAndUpdate(context.TODO(),
+		bson.M{"_id": id, "revoked_at": nil},
+		bson.M{"$set": bson.M{"revoked_at": revokedAt, "revoked_by": author}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		err = ks.apiKeyCollection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&key)
+	}
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &key, nil
+}
+
+func (ks *APIKeyService) Touch(id primitive.ObjectID, usedAt time.Time) error {
+	result, err := ks.apiKeyCollection.UpdateOne(context.TODO(), bson.M{"_id": id},
+		bson.M{"$set": bson.M{"last_used_at": usedAt.UTC().Truncate(time.Millisecond)}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return interfaces.ErrNotFound
+	}
+	return nil
+}
+
+// createAPIKeyIndexes creates the index API keys are looked up by, which also
+// keeps their hashes unique.
+func createAPIKeyIndexes(database DatabaseService) error {
+	_, err := database.Collection(APIKeyCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{primitive.E{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
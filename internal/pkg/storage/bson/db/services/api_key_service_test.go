@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAPIKeyService(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("GetAll method Should return the keys newest first", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		key := mocked_data.GetValidAPIKey()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromAPIKey(key)))
+
+		response, err := apiKeyService.GetAll()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, key.Scopes, response[0].Scopes)
+		assert.Equal(t, int32(-1), mt.GetStartedEvent().Command.Lookup("sort", "_id").Int32())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetByHash method Should return the key with the hash", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		key := mocked_data.GetValidAPIKey()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromAPIKey(key)))
+
+		response, err := apiKeyService.GetByHash(key.Hash)
+
+		assert.Nil(t, err)
+		assert.Equal(t, key.ID, response.ID)
+		assert.Equal(t, key.Hash, mt.GetStartedEvent().Command.Lookup("filter", "hash").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetByHash method Should return ErrNotFound When no key has the hash", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		response, err := apiKeyService.GetByHash("unknown")
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Create method Should insert the key stamped with its author", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		response, err := apiKeyService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidAPIKey())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|123", response.CreatedBy)
+		assert.False(t, response.CreatedAt.IsZero())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Revoke method Should stamp the revocation of a key not revoked yet", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		key := mocked_data.GetValidAPIKey()
+		mt.AddMockResponses(bson.D{
+			primitive.E{Key: "ok", Value: 1},
+			primitive.E{Key: "value", Value: append(mocked_data.GetBsonFromAPIKey(key),
+				primitive.E{Key: "revoked_at", Value: time.Now()}, primitive.E{Key: "revoked_by", Value: "auth0|123"})},
+		})
+
+		response, err := apiKeyService.Revoke(mocked_data.GetContextWithSubject("auth0|123"), key.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, response.RevokedAt)
+		assert.Equal(t, "auth0|123", response.RevokedBy)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, bson.TypeNull, command.Lookup("query", "revoked_at").Type)
+		assert.Equal(t, "auth0|123", command.Lookup("update", "$set", "revoked_by").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Revoke method Should return the key as it was When it was already revoked", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		key := mocked_data.GetValidAPIKey()
+		mt.AddMockResponses(
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, append(mocked_data.GetBsonFromAPIKey(key),
+				primitive.E{Key: "revoked_at", Value: time.Now()}, primitive.E{Key: "revoked_by", Value: "auth0|456"})))
+
+		response, err := apiKeyService.Revoke(context.TODO(), key.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|456", response.RevokedBy)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Revoke method Should return ErrNotFound When the key does not exist", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		mt.AddMockResponses(
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		response, err := apiKeyService.Revoke(context.TODO(), primitive.NewObjectID())
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Touch method Should set when the key was last used", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		usedAt := time.Date(2021, 8, 14, 4, 46, 49, 0, time.UTC)
+		mt.AddMockResponses(bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 1}, primitive.E{Key: "nModified", Value: 1}})
+
+		err := apiKeyService.Touch(primitive.NewObjectID(), usedAt)
+
+		assert.Nil(t, err)
+		assert.Equal(t, usedAt, mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("u", "$set", "last_used_at").Time().UTC())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Touch method Should return ErrNotFound When the key does not exist", func(mt *mtest.T) {
+		var apiKeyService = APIKeyService{}
+		apiKeyService.apiKeyCollection = mt.Coll
+		mt.AddMockResponses(bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 0}})
+
+		err := apiKeyService.Touch(primitive.NewObjectID(), time.Now())
+
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+}
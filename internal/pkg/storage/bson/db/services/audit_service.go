@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditService struct {
+	auditCollection *mongo.Collection
+}
+
+func ProvideAuditService(database DatabaseService) AuditService {
+	return AuditService{database.Collection(AuditCollection)}
+}
+
+func (as *AuditService) GetAll(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+	collectionFilter, findOptions := makeAuditFindOptions(filter, page, pageSize)
+	var entries []models.AuditEntry
+	cursor, err := as.auditCollection.Find(context.TODO(), collectionFilter, findOptions)
+
+	if err != nil {
+		return nil, 0, err
+	}
+	_ = cursor.All(context.TODO(), &entries)
+	total, err := as.auditCollection.CountDocuments(context.TODO(), collectionFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// makeAuditFindOptions matches the entries passing the filter, newest first.
+func makeAuditFindOptions(filter dto.AuditFilter, page int64, pageSize int64) (bson.M, *options.FindOptions) {
+	collectionFilter := bson.M{}
+	if filter.Resource != "" {
+		collectionFilter["resource"] = filter.Resource
+	}
+	if !filter.ResourceID.IsZero() {
+		collectionFilter["resource_id"] = filter.ResourceID
+	}
+	if filter.Actor != "" {
+		collectionFilter["actor"] = filter.Actor
+	}
+	if filter.Operation != "" {
+		collectionFilter["operation"] = filter.Operation
+	}
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.Before.IsZero() {
+		timestamp["$lt"] = filter.Before
+	}
+	if len(timestamp) > 0 {
+		collectionFilter["timestamp"] = timestamp
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "timestamp", Value: -1}, primitive.E{Key: "_id", Value: -1}})
+	if pageSize > 0 {
+		findOptions.SetSkip((page - 1) * pageSize)
+		findOptions.SetLimit(pageSize)
+	}
+	return collectionFilter, findOptions
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestAuditService(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("GetAll method Should return the entries newest first with the total", func(mt *mtest.T) {
+		var auditService = AuditService{}
+		auditService.auditCollection = mt.Coll
+		id := primitive.NewObjectID()
+		entry := bson.D{
+			primitive.E{Key: "_id", Value: primitive.NewObjectID()},
+			primitive.E{Key: "resource", Value: models.VideoResource},
+			primitive.E{Key: "resource_id", Value: id},
+			primitive.E{Key: "operation", Value: models.UpdateOperation},
+			primitive.E{Key: "actor", Value: "auth0|123"},
+			primitive.E{Key: "changes", Value: bson.A{bson.D{
+				primitive.E{Key: "field", Value: "titulo"},
+				primitive.E{Key: "before", Value: "old"},
+				primitive.E{Key: "after", Value: "new"},
+			}}},
+		}
+		count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 3}})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, entry), count)
+
+		response, total, err := auditService.GetAll(dto.AuditFilter{Resource: models.VideoResource, ResourceID: id}, 2, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, []models.FieldChange{{Field: "titulo", Before: "old", After: "new"}}, response[0].Changes)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, id, command.Lookup("filter", "resource_id").ObjectID())
+		assert.Equal(t, int32(-1), command.Lookup("sort", "timestamp").Int32())
+		assert.Equal(t, int64(1), command.Lookup("skip").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetAll method Should return error When the query fails", func(mt *mtest.T) {
+		var auditService = AuditService{}
+		auditService.auditCollection = mt.Coll
+		mt.AddMockResponses(bson.D{})
+
+		response, _, err := auditService.GetAll(dto.AuditFilter{}, 1, 5)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, response)
+		mt.ClearMockResponses()
+	})
+}
+
+func TestAuditService_makeAuditFindOptions(t *testing.T) {
+	t.Run("Should match every filter field and the time range", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		from := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+		before := from.AddDate(0, 1, 0)
+
+		filter, findOptions := makeAuditFindOptions(dto.AuditFilter{
+			Resource: models.CategoryResource, ResourceID: id, Actor: "auth0|123", Operation: models.DeleteOperation,
+			From: from, Before: before,
+		}, 1, 5)
+
+		assert.Equal(t, bson.M{
+			"resource":    models.CategoryResource,
+			"resource_id": id,
+			"actor":       "auth0|123",
+			"operation":   models.DeleteOperation,
+			"timestamp":   bson.M{"$gte": from, "$lt": before},
+		}, filter)
+		assert.Equal(t, int64(0), *findOptions.Skip)
+		assert.Equal(t, int64(5), *findOptions.Limit)
+	})
+
+	t.Run("Should match everything When the filter is empty", func(t *testing.T) {
+		filter, _ := makeAuditFindOptions(dto.AuditFilter{}, 1, 5)
+		assert.Equal(t, bson.M{}, filter)
+	})
+}
@@ -3,79 +3,244 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type CategoryService struct {
 	categoryCollection *mongo.Collection
 	videosCollection   *mongo.Collection
+	deletePolicy       domain.CategoryDeletePolicy
 }
 
 func ProvideCategoryService(database DatabaseService) CategoryService {
-	return CategoryService{database.Collection(CategoriesCollection), database.Collection(VideoCollection)}
+	return CategoryService{database.Collection(CategoriesCollection), database.Collection(VideoCollection),
+		domain.GetCategoryDeletePolicy()}
 }
 
-func (cs *CategoryService) GetAll(filter string, page int64, pageSize int64) ([]models.Category, error) {
-	collectionFilter, findOptions := makeFindOptions(filter, page, pageSize)
+func (cs *CategoryService) GetAll(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+	collectionFilter, findOptions := makeFindOptions(makeTitleFilter(filter), sort, page, pageSize)
 	var Categories []models.Category
 	cursor, err := cs.categoryCollection.Find(context.TODO(), collectionFilter, findOptions)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	_ = cursor.All(context.TODO(), &Categories)
-	return Categories, err
+	total, err := cs.categoryCollection.CountDocuments(context.TODO(), collectionFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return Categories, total, nil
+}
+
+func (cs *CategoryService) GetAllAfter(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error) {
+	collectionFilter, findOptions := makeCursorFindOptions(makeTitleFilter(filter), sort, after, limit)
+	var categories []models.Category
+	cursor, err := cs.categoryCollection.Find(context.TODO(), collectionFilter, findOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = cursor.All(context.TODO(), &categories); err != nil {
+		return nil, nil, err
+	}
+	categories, next := dto.NextCategoryPage(categories, sort, limit)
+	return categories, next, nil
 }
 
 func (cs *CategoryService) GetById(id primitive.ObjectID) (*models.Category, error) {
 	category := models.Category{}
-	if err := cs.categoryCollection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&category); err != nil {
-		return nil, err
+	if err := cs.categoryCollection.FindOne(context.TODO(), bson.M{"_id": id, "active": true}).Decode(&category); err != nil {
+		return nil, notFound(err)
 	}
 	return &category, nil
 }
 
-func (cs *CategoryService) Create(insertCategory dto.InsertCategory) (*models.Category, error) {
+func (cs *CategoryService) Create(ctx context.Context, insertCategory dto.InsertCategory) (*models.Category, error) {
+	author, createdAt := domain.Stamp(ctx)
 	convertedCategory := insertCategory.ConvertToCategory()
-	_, err := cs.categoryCollection.InsertOne(context.TODO(), &convertedCategory)
+	convertedCategory.Audit = models.NewAudit(author, createdAt)
+	convertedCategory.OwnerID = author
+	err := inTransaction(cs.categoryCollection, func(ctx context.Context) error {
+		if _, err := cs.categoryCollection.InsertOne(ctx, &convertedCategory); err != nil {
+			return err
+		}
+		return recordAudit(ctx, cs.categoryCollection, domain.NewAuditEntry(author, createdAt, models.CategoryResource,
+			convertedCategory.ID, models.CreateOperation, nil, convertedCategory))
+	})
 	if err != nil {
 		return nil, err
 	}
 	return &convertedCategory, nil
 }
 
-func (cs *CategoryService) Update(id primitive.ObjectID, newData dto.InsertCategory) (*models.Category, error) {
-	var category *models.Category
-	if err := cs.categoryCollection.FindOneAndUpdate(
-		context.Background(),
-		bson.D{
-			primitive.E{Key: "_id", Value: id},
-		},
-		bson.D{primitive.E{Key: "$set", Value: newData}},
-		options.FindOneAndUpdate().SetReturnDocument(1),
-	).Decode(&category); err != nil {
+func (cs *CategoryService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertCategory, version int64) (*models.Category, error) {
+	if id.IsZero() && newData.Titulo != models.GetFreeCategory().Titulo {
+		return nil, interfaces.ErrProtectedCategory
+	}
+	return cs.update(ctx, id, version, bson.M{
+		"titulo":        newData.Titulo,
+		"titulo_search": dto.NormalizeSearch(newData.Titulo),
+		"cor":           newData.Cor,
+	})
+}
+
+// Patch changes only the fields present in the merge patch.
+func (cs *CategoryService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error) {
+	if err := domain.PrepareCategoryPatch(id, &patch); err != nil {
 		return nil, err
 	}
-	return category, nil
+	fields := bson.M{}
+	if patch.Titulo != nil {
+		fields["titulo"] = *patch.Titulo
+		fields["titulo_search"] = dto.NormalizeSearch(*patch.Titulo)
+	}
+	if patch.Cor != nil {
+		fields["cor"] = *patch.Cor
+	}
+	if len(fields) == 0 {
+		category, err := cs.GetById(id)
+		if err == nil && version != 0 && category.Version != version {
+			return nil, interfaces.ErrVersionMismatch
+		}
+		return category, err
+	}
+	return cs.update(ctx, id, version, fields)
 }
 
-func (cs *CategoryService) Delete(id primitive.ObjectID) error {
-	result, err := cs.categoryCollection.DeleteOne(context.TODO(), bson.M{"_id": id})
-	if result.DeletedCount == 0 {
-		return errors.New("no document deleted")
+func (cs *CategoryService) update(ctx context.Context, id primitive.ObjectID, version int64, fields bson.M) (*models.Category, error) {
+	author, updatedAt := domain.Stamp(ctx)
+	var before, category models.Category
+	err := audited(ctx, cs.categoryCollection, models.CategoryResource, id, true, models.UpdateOperation, author, updatedAt, &before, &category,
+		func(ctx context.Context) error {
+			if err := updateVersioned(ctx, cs.categoryCollection, id, version, touched(author, updatedAt, fields), &category); err != nil {
+				return err
+			}
+			return keepRevision(ctx, cs.categoryCollection, models.CategoryResource, id, before.Version, before)
+		})
+	if err != nil {
+		return nil, err
 	}
+	return &category, nil
+}
+
+// Delete soft deletes a category, handling its videos according to the
+// configured delete policy. The FREE category can never be deleted.
+func (cs *CategoryService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	if id.IsZero() {
+		return interfaces.ErrProtectedCategory
+	}
+	if cs.deletePolicy == domain.ReassignPolicy && cs.GetFreeCategory() == nil {
+		return errors.New("could not load the free category")
+	}
+	author, deletedAt := domain.Stamp(ctx)
+	request := ctx
+	var before, category models.Category
+	err := audited(ctx, cs.categoryCollection, models.CategoryResource, id, true, models.DeleteOperation, author, deletedAt, &before, &category,
+		func(ctx context.Context) error {
+			// Anything but cascade or reassign, including an unset policy, rejects.
+			if cs.deletePolicy != domain.CascadePolicy && cs.deletePolicy != domain.ReassignPolicy {
+				count, err := cs.videosCollection.CountDocuments(ctx, bson.M{"category_id": id, "active": true})
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					return interfaces.ErrCategoryHasVideos
+				}
+			}
+			videos, err := cs.findReleasedVideos(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err = domain.AuthorizeVideos(request, videos); err != nil {
+				return err
+			}
+			if err = softDelete(ctx, cs.categoryCollection, id, version, author, deletedAt); err != nil {
+				return err
+			}
+			category = before
+			category.Active = false
+			category.DeletedAt = &deletedAt
+			category.Touch(author, deletedAt)
+			category.Version++
+			return cs.releaseVideos(ctx, videos, author, deletedAt)
+		})
 	return err
 }
 
-func (cs *CategoryService) GetVideosByCategoryId(id primitive.ObjectID) ([]models.Video, error) {
+// findReleasedVideos finds the videos the delete policy deletes or reassigns
+// along with the category.
+func (cs *CategoryService) findReleasedVideos(ctx context.Context, id primitive.ObjectID) ([]models.Video, error) {
+	var filter bson.M
+	switch cs.deletePolicy {
+	case domain.CascadePolicy:
+		filter = bson.M{"category_id": id, "active": true}
+	case domain.ReassignPolicy:
+		filter = bson.M{"category_id": id}
+	default:
+		return nil, nil
+	}
+	var videos []models.Video
+	cursor, err := cs.videosCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(ctx, &videos); err != nil {
+		return nil, err
+	}
+	return videos, nil
+}
+
+// releaseVideos deletes or reassigns the videos of a category being deleted,
+// as the delete policy says, recording each video it changes.
+func (cs *CategoryService) releaseVideos(ctx context.Context, videos []models.Video, author string, deletedAt time.Time) error {
+	if len(videos) == 0 {
+		return nil
+	}
+	ids := make([]primitive.ObjectID, len(videos))
+	for i, video := range videos {
+		ids[i] = video.ID
+	}
+	fields, operation := bson.M{"category_id": models.GetFreeCategory().ID, "updated_at": deletedAt, "updated_by": author}, models.UpdateOperation
+	if cs.deletePolicy == domain.CascadePolicy {
+		fields, operation = deletedFields(author, deletedAt), models.DeleteOperation
+	}
+	if _, err := cs.videosCollection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}}, bson.M{"$set": fields, "$inc": bson.M{"version": 1}}); err != nil {
+		return err
+	}
+	if operation == models.DeleteOperation {
+		if err := removeFromPlaylists(ctx, cs.videosCollection, ids, author, deletedAt); err != nil {
+			return err
+		}
+	}
+	for _, before := range videos {
+		video := before
+		if operation == models.DeleteOperation {
+			video.Active = false
+			video.DeletedAt = &deletedAt
+		} else {
+			video.CategoryID = models.GetFreeCategory().ID
+		}
+		video.Touch(author, deletedAt)
+		video.Version++
+		if err := recordAudit(ctx, cs.videosCollection, domain.NewAuditEntry(author, deletedAt, models.VideoResource, video.ID,
+			operation, before, video)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs *CategoryService) GetVideosByCategoryId(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
 	var videos []models.Video
-	cursor, err := cs.videosCollection.Find(context.TODO(), bson.M{"category_id": id})
+	cursor, err := cs.videosCollection.Find(context.TODO(), categoryVideosFilter(id, publishedOnly))
 	if err != nil {
 		return nil, err
 	}
@@ -84,10 +249,35 @@ func (cs *CategoryService) GetVideosByCategoryId(id primitive.ObjectID) ([]model
 	return videos, err
 }
 
+func (cs *CategoryService) GetVideosByCategoryIdAfter(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	collectionFilter, findOptions := makeCursorFindOptions(categoryVideosFilter(id, publishedOnly), dto.Sort{}, after, limit)
+	var videos []models.Video
+	cursor, err := cs.videosCollection.Find(context.TODO(), collectionFilter, findOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = cursor.All(context.TODO(), &videos); err != nil {
+		return nil, nil, err
+	}
+	videos, next := dto.NextVideoPage(videos, dto.Sort{}, limit)
+	return videos, next, nil
+}
+
+// categoryVideosFilter matches the active videos of the category, leaving out
+// the ones not published yet when publishedOnly is set.
+func categoryVideosFilter(id primitive.ObjectID, publishedOnly bool) bson.M {
+	filter := bson.M{"category_id": id, "active": true}
+	if publishedOnly {
+		filter["status"] = models.PublishedStatus
+	}
+	return filter
+}
+
 func (cs *CategoryService) GetFreeCategory() *models.Category {
 	category := models.Category{}
 	if err := cs.categoryCollection.FindOne(context.TODO(), bson.M{"titulo": "FREE"}).Decode(&category); err != nil {
 		category = *models.GetFreeCategory()
+		category.Audit = models.NewAudit(domain.Stamp(context.TODO()))
 		_, err := cs.categoryCollection.InsertOne(context.TODO(), &category)
 		if err != nil {
 			return nil
@@ -96,3 +286,64 @@ func (cs *CategoryService) GetFreeCategory() *models.Category {
 	}
 	return &category
 }
+
+func (cs *CategoryService) GetDeleted(page int64, pageSize int64) ([]models.Category, error) {
+	collectionFilter, findOptions := makeTrashFindOptions(page, pageSize)
+	var Categories []models.Category
+	cursor, err := cs.categoryCollection.Find(context.TODO(), collectionFilter, findOptions)
+
+	if err != nil {
+		return nil, err
+	}
+	_ = cursor.All(context.TODO(), &Categories)
+	return Categories, err
+}
+
+func (cs *CategoryService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	author, restoredAt := domain.Stamp(ctx)
+	var before, category models.Category
+	err := audited(ctx, cs.categoryCollection, models.CategoryResource, id, false, models.RestoreOperation, author, restoredAt, &before, &category,
+		func(ctx context.Context) error {
+			return restore(ctx, cs.categoryCollection, id, author, restoredAt, &category)
+		})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// Purge removes the expired categories of the trash ctx may purge that no
+// video references, with their revisions.
+func (cs *CategoryService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	filter, err := makeExpiredFilter(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	referenced, err := cs.videosCollection.Distinct(context.TODO(), "category_id", bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	if referenced == nil {
+		referenced = []interface{}{}
+	}
+	filter["_id"] = bson.M{"$nin": referenced}
+	return purge(cs.categoryCollection, models.CategoryResource, filter)
+}
+
+func (cs *CategoryService) GetRevisions(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+	revisions := []models.CategoryRevision{}
+	if err := findRevisions(cs.categoryCollection, models.CategoryResource, id, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the category back to the fields it had at a revision,
+// with the same checks as any other update.
+func (cs *CategoryService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+	var snapshot models.CategoryRevision
+	if err := findRevision(cs.categoryCollection, models.CategoryResource, id, revision, &snapshot); err != nil {
+		return nil, err
+	}
+	return cs.Update(ctx, id, dto.InsertCategoryFrom(snapshot.Category), version)
+}
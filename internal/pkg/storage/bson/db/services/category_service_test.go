@@ -1,9 +1,13 @@
 package services
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
 	"github.com/stretchr/testify/assert"
@@ -27,11 +31,13 @@ func TestCategoryService(t *testing.T) {
 		secondCategory := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(secondId)))
 
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
-		mt.AddMockResponses(firstCategory, secondCategory, killCursors)
+		count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 7}})
+		mt.AddMockResponses(firstCategory, secondCategory, killCursors, count)
 
-		response, err := categoryService.GetAll("", 1, 5)
+		response, total, err := categoryService.GetAll("", dto.Sort{}, 1, 5)
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(response))
+		assert.Equal(t, int64(7), total)
 		mt.ClearMockResponses()
 	})
 
@@ -42,7 +48,7 @@ func TestCategoryService(t *testing.T) {
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
 		mt.AddMockResponses(bson.D{}, killCursors)
 
-		response, err := categoryService.GetAll("", 1, 5)
+		response, _, err := categoryService.GetAll("", dto.Sort{}, 1, 5)
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(response))
 		mt.ClearMockResponses()
@@ -87,7 +93,7 @@ func TestCategoryService(t *testing.T) {
 			Message: "Con't insert data",
 		}))
 
-		response, err := categoryService.Create(dto.InsertCategory{})
+		response, err := categoryService.Create(context.TODO(), dto.InsertCategory{})
 		assert.Nil(t, response)
 		assert.NotNil(t, err)
 		mt.ClearMockResponses()
@@ -97,9 +103,9 @@ func TestCategoryService(t *testing.T) {
 		var categoryService = CategoryService{}
 		categoryService.categoryCollection = mt.Coll
 
-		mt.AddMockResponses(mtest.CreateSuccessResponse())
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
 
-		response, err := categoryService.Create(mocked_data.GetValidInsertCategoryDto())
+		response, err := categoryService.Create(context.TODO(), mocked_data.GetValidInsertCategoryDto())
 
 		assert.NotNil(t, response)
 		assert.Nil(t, err)
@@ -117,7 +123,7 @@ func TestCategoryService(t *testing.T) {
 		}))
 		id := primitive.NewObjectID()
 
-		response, err := categoryService.Update(id, dto.InsertCategory{})
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), id, dto.InsertCategory{}, 0)
 
 		assert.Nil(t, response)
 		assert.NotNil(t, err)
@@ -130,47 +136,212 @@ func TestCategoryService(t *testing.T) {
 
 		id := primitive.NewObjectID()
 		categoryData := mocked_data.GetValidInsertCategoryDto()
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "value", Value: mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))},
-		})
-
-		response, err := categoryService.Update(id, categoryData)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), id, categoryData, 0)
 
 		assert.NotNil(t, response)
 		assert.Nil(t, err)
 		mt.ClearMockResponses()
 	})
 
-	mt.Run("DeleteCategory method Should delete an item When the item can be deleted", func(mt *mtest.T) {
+	mt.Run("PatchCategory method Should set only the fields in the patch When object exists", func(mt *mtest.T) {
 		var categoryService = CategoryService{}
 		categoryService.categoryCollection = mt.Coll
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "acknowledged", Value: true},
-			primitive.E{Key: "n", Value: 1},
-		})
+		id := primitive.NewObjectID()
+		color := "Green"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := categoryService.Patch(mocked_data.GetAdminContext(), id, dto.PatchCategory{Cor: &color}, 0)
 
-		err := categoryService.Delete(primitive.NewObjectID())
+		assert.Nil(t, err)
+		assert.Equal(t, id, response.ID)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		set := mt.GetStartedEvent().Command.Lookup("update", "$set").Document()
+		elements, _ := set.Elements()
+		assert.Equal(t, 3, len(elements))
+		assert.Equal(t, color, set.Lookup("cor").StringValue())
+		assert.Equal(t, "", set.Lookup("updated_by").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteCategory method Should delete an item When the item can be deleted", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 0}}),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 1},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), id, 0)
 		assert.Nil(t, err)
 		mt.ClearMockResponses()
 	})
 
-	mt.Run("DeleteCategory method Should return no document deleted error When document dont exists", func(mt *mtest.T) {
+	mt.Run("DeleteCategory method Should return ErrNotFound When document dont exists", func(mt *mtest.T) {
 		var categoryService = CategoryService{}
 		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
 
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "acknowledged", Value: true},
-			primitive.E{Key: "n", Value: 0},
-		})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch),
+			mtest.CreateSuccessResponse())
 
-		err := categoryService.Delete(primitive.NewObjectID())
-		assert.NotNil(t, err)
+		err := categoryService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteCategory method Should return ErrCategoryHasVideos When the reject policy finds videos", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		categoryService.deletePolicy = domain.RejectPolicy
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategory())),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 2}}),
+			mtest.CreateSuccessResponse())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Equal(t, interfaces.ErrCategoryHasVideos, err)
 		mt.ClearMockResponses()
 	})
 
+	mt.Run("DeleteCategory method Should delete the videos too When using the cascade policy", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		categoryService.deletePolicy = domain.CascadePolicy
+		id := primitive.NewObjectID()
+		video := mocked_data.GetValidVideo()
+		video.CategoryID = id
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(video)),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "n", Value: 1},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "n", Value: 1},
+				primitive.E{Key: "nModified", Value: 1},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), id, 0)
+		assert.Nil(t, err)
+		for _, name := range []string{"find", "find", "update", "update"} {
+			assert.Equal(t, name, mt.GetStartedEvent().CommandName)
+		}
+		playlists := mt.GetStartedEvent().Command
+		assert.Equal(t, PlaylistCollection, playlists.Lookup("update").StringValue())
+		pulled := playlists.Lookup("updates", "0", "u", "$pull", "items", "video_id", "$in").Array().Index(0).Value().ObjectID()
+		assert.Equal(t, video.ID, pulled)
+		videoEntry := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, models.VideoResource, videoEntry.Lookup("resource").StringValue())
+		assert.Equal(t, models.DeleteOperation, videoEntry.Lookup("operation").StringValue())
+		categoryEntry := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, models.CategoryResource, categoryEntry.Lookup("resource").StringValue())
+		assert.Equal(t, id, categoryEntry.Lookup("resource_id").ObjectID())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteCategory method Should move the videos to the free category When using the reassign policy", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		categoryService.deletePolicy = domain.ReassignPolicy
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(models.GetFreeCategory())),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategory())),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideo())),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "n", Value: 1},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "n", Value: 1},
+				primitive.E{Key: "nModified", Value: 1},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Nil(t, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteCategory method Should run without a transaction When the server dont support them", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		mt.AddMockResponses(
+			mtest.CreateCommandErrorResponse(mtest.CommandError{
+				Code:    20,
+				Name:    "IllegalOperation",
+				Message: "Transaction numbers are only allowed on a replica set member or mongos",
+			}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(mocked_data.GetValidCategory())),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 0}}),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "n", Value: 1},
+			},
+			mtest.CreateSuccessResponse())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Nil(t, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteCategory method Should return ErrProtectedCategory When deleting the free category", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), models.GetFreeCategory().ID, 0)
+		assert.Equal(t, interfaces.ErrProtectedCategory, err)
+	})
+
+	mt.Run("UpdateCategory method Should return ErrProtectedCategory When renaming the free category", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), models.GetFreeCategory().ID, mocked_data.GetValidInsertCategoryDto(), 0)
+		assert.Equal(t, interfaces.ErrProtectedCategory, err)
+		assert.Nil(t, response)
+	})
+
 	mt.Run("GetVideosByCategoryId method Should return object when has objects", func(mt *mtest.T) {
 		var categoryService = CategoryService{}
 		categoryService.categoryCollection = mt.Coll
@@ -186,7 +357,7 @@ func TestCategoryService(t *testing.T) {
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
 		mt.AddMockResponses(firstCategory, secondCategory, killCursors)
 
-		response, err := categoryService.GetVideosByCategoryId(primitive.ObjectID{})
+		response, err := categoryService.GetVideosByCategoryId(primitive.ObjectID{}, false)
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(response))
 		mt.ClearMockResponses()
@@ -200,7 +371,7 @@ func TestCategoryService(t *testing.T) {
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
 		mt.AddMockResponses(bson.D{}, killCursors)
 
-		response, err := categoryService.GetVideosByCategoryId(primitive.ObjectID{})
+		response, err := categoryService.GetVideosByCategoryId(primitive.ObjectID{}, false)
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(response))
 		mt.ClearMockResponses()
@@ -237,6 +408,8 @@ func TestCategoryService(t *testing.T) {
 		response := categoryService.GetFreeCategory()
 
 		assert.NotNil(t, response)
+		assert.False(t, response.CreatedAt.IsZero())
+		expectedFreeCategory.Audit = response.Audit
 		assert.Equal(t, expectedFreeCategory, response)
 		mt.ClearMockResponses()
 	})
@@ -262,4 +435,96 @@ func TestCategoryService(t *testing.T) {
 		assert.Nil(t, response)
 		mt.ClearMockResponses()
 	})
+
+	mt.Run("RestoreCategory method Should return the restored object When object is in the trash", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		id := primitive.NewObjectID()
+		deleted := mocked_data.GetValidCategoryWithId(id)
+		deleted.Active = false
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromCategory(deleted)),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := categoryService.Restore(mocked_data.GetAdminContext(), id)
+		assert.Nil(t, err)
+		assert.Equal(t, id, response.ID)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("PurgeCategories method Should only remove objects not referenced by videos", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		categoryService.videosCollection = mt.Coll
+		mt.AddMockResponses(
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "values", Value: bson.A{primitive.NewObjectID()}},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "values", Value: bson.A{primitive.NewObjectID()}},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 0},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 1},
+			})
+
+		purged, err := categoryService.Purge(mocked_data.GetAdminContext(), time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetRevisions method Should return the revisions newest first", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.revisions", mtest.FirstBatch, bson.D{
+			primitive.E{Key: "revision", Value: int64(1)},
+			primitive.E{Key: "document", Value: mocked_data.GetBsonFromCategory(mocked_data.GetValidCategoryWithId(id))},
+		}))
+
+		revisions, err := categoryService.GetRevisions(id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(revisions))
+		assert.Equal(t, int64(1), revisions[0].Revision)
+		assert.Equal(t, id, revisions[0].Category.ID)
+		find := mt.GetStartedEvent().Command
+		assert.Equal(t, models.CategoryResource, find.Lookup("filter", "resource").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreRevision method Should return error When the revision dont exists", func(mt *mtest.T) {
+		var categoryService = CategoryService{}
+		categoryService.categoryCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.revisions", mtest.FirstBatch))
+
+		response, err := categoryService.RestoreRevision(mocked_data.GetAdminContext(), primitive.NewObjectID(), 1, 0)
+
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+		mt.ClearMockResponses()
+	})
+}
+
+func TestCategoryService_categoryVideosFilter(t *testing.T) {
+	t.Run("Should match the published videos only When publishedOnly is set", func(t *testing.T) {
+		id := primitive.NewObjectID()
+
+		assert.Equal(t, bson.M{"category_id": id, "active": true}, categoryVideosFilter(id, false))
+		assert.Equal(t, bson.M{"category_id": id, "active": true, "status": models.PublishedStatus}, categoryVideosFilter(id, true))
+	})
 }
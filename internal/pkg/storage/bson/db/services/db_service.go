@@ -2,19 +2,35 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"log"
 	"os"
+	"regexp"
 	"time"
 )
 
 const (
 	VideoCollection      = "videos"
 	CategoriesCollection = "categories"
+	AuditCollection      = "audit"
+	RevisionCollection   = "revisions"
+	APIKeyCollection     = "api_keys"
+	UserCollection       = "users"
+	PlaylistCollection   = "playlists"
 )
 
+// illegalOperationCode is returned by standalone servers for transactional commands.
+const illegalOperationCode = 20
+
 type DatabaseService struct {
 	*mongo.Database
 }
@@ -36,7 +52,164 @@ func ProvideDatabaseService() DatabaseService {
 		return DatabaseService{}
 	}
 
-	return DatabaseService{client.Database(os.Getenv("APP_DB_NAME"))}
+	database := DatabaseService{client.Database(os.Getenv("APP_DB_NAME"))}
+	go func() {
+		for _, name := range []string{VideoCollection, CategoriesCollection} {
+			if err := backfillTitleSearch(database.Collection(name)); err != nil {
+				log.Printf("could not backfill titulo_search of %s: %v", name, err)
+			}
+		}
+		if err := backfillUrlHost(database.Collection(VideoCollection)); err != nil {
+			log.Printf("could not backfill url_host of %s: %v", VideoCollection, err)
+		}
+		if err := backfillStatus(database.Collection(VideoCollection)); err != nil {
+			log.Printf("could not backfill the status of %s: %v", VideoCollection, err)
+		}
+		for _, name := range []string{VideoCollection, CategoriesCollection} {
+			if err := backfillAudit(database.Collection(name)); err != nil {
+				log.Printf("could not backfill the audit fields of %s: %v", name, err)
+			}
+		}
+		for _, name := range []string{VideoCollection, CategoriesCollection} {
+			if err := backfillOwner(database.Collection(name)); err != nil {
+				log.Printf("could not backfill the owner of %s: %v", name, err)
+			}
+		}
+		if err := createTextIndexes(database); err != nil {
+			log.Printf("could not create the text indexes: %v", err)
+		}
+		if err := createSuggestIndexes(database); err != nil {
+			log.Printf("could not create the suggest indexes: %v", err)
+		}
+		if err := createAuditIndexes(database); err != nil {
+			log.Printf("could not create the audit indexes: %v", err)
+		}
+		if err := createRevisionIndexes(database); err != nil {
+			log.Printf("could not create the revision indexes: %v", err)
+		}
+		if err := createPublicationIndexes(database); err != nil {
+			log.Printf("could not create the publication indexes: %v", err)
+		}
+		if err := createAPIKeyIndexes(database); err != nil {
+			log.Printf("could not create the API key indexes: %v", err)
+		}
+		if err := createUserIndexes(database); err != nil {
+			log.Printf("could not create the user indexes: %v", err)
+		}
+		if err := createPlaylistIndexes(database); err != nil {
+			log.Printf("could not create the playlist indexes: %v", err)
+		}
+	}()
+	return database
+}
+
+// backfillTitleSearch stores the folded title of the documents written before
+// titulo_search existed, so that searching finds them too.
+func backfillTitleSearch(collection *mongo.Collection) error {
+	cursor, err := collection.Find(context.TODO(), bson.M{"titulo_search": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"titulo": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID     primitive.ObjectID `bson:"_id"`
+			Titulo string             `bson:"titulo"`
+		}
+		if err = cursor.Decode(&document); err != nil {
+			return err
+		}
+		if _, err = collection.UpdateOne(context.TODO(), bson.M{"_id": document.ID},
+			bson.M{"$set": bson.M{"titulo_search": dto.NormalizeSearch(document.Titulo)}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// backfillUrlHost stores the folded URL host of the videos written before
+// url_host existed, so that the host filter and facet count them too.
+func backfillUrlHost(collection *mongo.Collection) error {
+	cursor, err := collection.Find(context.TODO(), bson.M{"url_host": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"url": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID  primitive.ObjectID `bson:"_id"`
+			Url string             `bson:"url"`
+		}
+		if err = cursor.Decode(&document); err != nil {
+			return err
+		}
+		if _, err = collection.UpdateOne(context.TODO(), bson.M{"_id": document.ID},
+			bson.M{"$set": bson.M{"url_host": dto.URLHost(document.Url)}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// backfillStatus publishes the videos written before they had a status, as
+// they were all public then.
+func backfillStatus(collection *mongo.Collection) error {
+	_, err := collection.UpdateMany(context.TODO(), bson.M{"status": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"status": models.PublishedStatus}})
+	return err
+}
+
+// backfillAudit dates the documents written before the audit fields existed
+// from their ObjectID. Their authors are unknown, so they are left empty.
+func backfillAudit(collection *mongo.Collection) error {
+	cursor, err := collection.Find(context.TODO(), bson.M{"created_at": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err = cursor.Decode(&document); err != nil {
+			return err
+		}
+		createdAt := document.ID.Timestamp().UTC()
+		if _, err = collection.UpdateOne(context.TODO(), bson.M{"_id": document.ID}, bson.M{"$set": bson.M{
+			"created_at": createdAt, "updated_at": createdAt, "created_by": "", "updated_by": "",
+		}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// backfillOwner gives the documents written before owner_id existed to their
+// author, leaving the ones without an author to nobody.
+func backfillOwner(collection *mongo.Collection) error {
+	cursor, err := collection.Find(context.TODO(), bson.M{"owner_id": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"created_by": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(context.TODO())
+	for cursor.Next(context.TODO()) {
+		var document struct {
+			ID        primitive.ObjectID `bson:"_id"`
+			CreatedBy string             `bson:"created_by"`
+		}
+		if err = cursor.Decode(&document); err != nil {
+			return err
+		}
+		if _, err = collection.UpdateOne(context.TODO(), bson.M{"_id": document.ID},
+			bson.M{"$set": bson.M{"owner_id": document.CreatedBy}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
 }
 
 func mountServerConnection(env, user, password, hostname, dbname string) string {
@@ -46,13 +219,315 @@ func mountServerConnection(env, user, password, hostname, dbname string) string
 	return fmt.Sprintf("mongodb+srv://%s:%s@%s/%s?retryWrites=true&w=majority", user, password, hostname, dbname)
 }
 
-func makeFindOptions(filter string, page int64, pageSize int64) (bson.M, *options.FindOptions) {
-	collectionFilter := bson.M{}
+// makeFindOptions returns the filter, the order and the pagination of a
+// listing. The filter is also used to count the matches across all pages.
+func makeFindOptions(collectionFilter bson.M, sort dto.Sort, page int64, pageSize int64) (bson.M, *options.FindOptions) {
 	findOptions := options.Find()
+	findOptions.SetSort(makeSort(sort))
 	findOptions.SetLimit(pageSize)
 	findOptions.SetSkip((page - 1) * pageSize)
+	return collectionFilter, findOptions
+}
+
+// makeTitleFilter matches the titles containing the search, ignoring case and
+// accents. The search is quoted, so regex metacharacters only match themselves.
+func makeTitleFilter(filter string) bson.M {
+	collectionFilter := bson.M{"active": true}
 	if filter != "" {
-		collectionFilter = bson.M{"titulo": bson.M{"$regex": fmt.Sprintf(".*%s.*", filter)}}
+		collectionFilter["titulo_search"] = bson.M{"$regex": regexp.QuoteMeta(dto.NormalizeSearch(filter))}
+	}
+	return collectionFilter
+}
+
+// makeSort orders by the sort field, then by _id in the same direction so
+// that documents sharing a value keep a stable order.
+func makeSort(sort dto.Sort) bson.D {
+	direction := 1
+	if sort.Descending {
+		direction = -1
 	}
+	if sort.Field == "" {
+		return bson.D{primitive.E{Key: "_id", Value: direction}}
+	}
+	return bson.D{primitive.E{Key: sort.Field, Value: direction}, primitive.E{Key: "_id", Value: direction}}
+}
+
+// makeCursorFindOptions narrows a filter to the keyset page after the cursor,
+// fetching one extra document to know whether another page follows.
+func makeCursorFindOptions(collectionFilter bson.M, sort dto.Sort, after *dto.Cursor, limit int64) (bson.M, *options.FindOptions) {
+	if after != nil {
+		operator := "$gt"
+		if sort.Descending {
+			operator = "$lt"
+		}
+		if sort.Field == "" {
+			collectionFilter["_id"] = bson.M{operator: after.ID}
+		} else {
+			value := sort.Value(after.Value)
+			collectionFilter["$or"] = bson.A{
+				bson.M{sort.Field: bson.M{operator: value}},
+				bson.M{sort.Field: value, "_id": bson.M{operator: after.ID}},
+			}
+		}
+	}
+	findOptions := options.Find()
+	findOptions.SetSort(makeSort(sort))
+	findOptions.SetLimit(limit + 1)
 	return collectionFilter, findOptions
 }
+
+// makeTrashFindOptions lists soft deleted documents, most recently deleted first.
+func makeTrashFindOptions(page int64, pageSize int64) (bson.M, *options.FindOptions) {
+	findOptions := options.Find()
+	findOptions.SetLimit(pageSize)
+	findOptions.SetSkip((page - 1) * pageSize)
+	findOptions.SetSort(bson.D{primitive.E{Key: "deleted_at", Value: -1}})
+	return bson.M{"active": false}, findOptions
+}
+
+// makeVersionFilter matches an active document, and its version when one is expected.
+func makeVersionFilter(id primitive.ObjectID, version int64) bson.M {
+	filter := bson.M{"_id": id, "active": true}
+	if version != 0 {
+		filter["version"] = version
+	}
+	return filter
+}
+
+// updateVersioned sets fields on an active document, bumping its version, and
+// decodes the updated document into model.
+func updateVersioned(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64, fields bson.M, model interface{}) error {
+	err := collection.FindOneAndUpdate(ctx,
+		makeVersionFilter(id, version),
+		bson.M{"$set": fields, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(model)
+	if err == mongo.ErrNoDocuments {
+		return notMatched(ctx, collection, id, version, interfaces.ErrNotFound)
+	}
+	return err
+}
+
+// touched adds to fields the audit fields of a write made by author.
+func touched(author string, updatedAt time.Time, fields bson.M) bson.M {
+	fields["updated_at"], fields["updated_by"] = updatedAt, author
+	return fields
+}
+
+// notMatched tells a missing document apart from one whose version changed.
+func notMatched(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64, err error) error {
+	if version != 0 {
+		if count, countErr := collection.CountDocuments(ctx, bson.M{"_id": id, "active": true}); countErr == nil && count > 0 {
+			return interfaces.ErrVersionMismatch
+		}
+	}
+	return err
+}
+
+// checkCategory fails unless the category exists and is active. Called with
+// the ctx of the transaction writing a video, so the check and the write see
+// the same category.
+func checkCategory(ctx context.Context, videos *mongo.Collection, id primitive.ObjectID) error {
+	err := videos.Database().Collection(CategoriesCollection).FindOne(ctx, bson.M{"_id": id, "active": true}).Err()
+	if err == mongo.ErrNoDocuments {
+		return interfaces.CategoryNotFoundError{ID: id}
+	}
+	return err
+}
+
+// checkVideo fails with ErrVideoNotFound unless the video is active. Called
+// with the ctx of the transaction adding the video to a playlist.
+func checkVideo(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID) error {
+	err := collection.Database().Collection(VideoCollection).FindOne(ctx, bson.M{"_id": id, "active": true}).Err()
+	if err == mongo.ErrNoDocuments {
+		return interfaces.ErrVideoNotFound
+	}
+	return err
+}
+
+// softDelete marks an active document as deleted by author instead of
+// removing it.
+func softDelete(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, version int64, author string, deletedAt time.Time) error {
+	result, err := collection.UpdateOne(ctx,
+		makeVersionFilter(id, version),
+		bson.M{"$set": deletedFields(author, deletedAt), "$inc": bson.M{"version": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return notMatched(ctx, collection, id, version, interfaces.ErrNotFound)
+	}
+	return nil
+}
+
+// deletedFields are the fields set on a document deleted by author.
+func deletedFields(author string, deletedAt time.Time) bson.M {
+	return bson.M{"active": false, "deleted_at": deletedAt, "updated_at": deletedAt, "updated_by": author}
+}
+
+// restore brings a soft deleted document back on behalf of author, decoding
+// the restored document into model.
+func restore(ctx context.Context, collection *mongo.Collection, id primitive.ObjectID, author string, updatedAt time.Time, model interface{}) error {
+	return notFound(collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": id, "active": false},
+		bson.M{"$set": touched(author, updatedAt, bson.M{"active": true}), "$unset": bson.M{"deleted_at": ""}, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(model))
+}
+
+// audited runs write in a transaction and records its change of the document
+// in the audit log.
+func audited(request context.Context, collection *mongo.Collection, resource string, id primitive.ObjectID, active bool, operation, author string, at time.Time,
+	before models.Owned, after interface{}, write func(ctx context.Context) error) error {
+	return inTransaction(collection, func(ctx context.Context) error {
+		if err := collection.FindOne(ctx, bson.M{"_id": id, "active": active}).Decode(before); err != nil {
+			return notFound(err)
+		}
+		if err := domain.Authorize(request, before.Owner()); err != nil {
+			return err
+		}
+		if err := write(ctx); err != nil {
+			return err
+		}
+		return recordAudit(ctx, collection, domain.NewAuditEntry(author, at, resource, id, operation, before, after))
+	})
+}
+
+func recordAudit(ctx context.Context, collection *mongo.Collection, entry models.AuditEntry) error {
+	_, err := collection.Database().Collection(AuditCollection).InsertOne(ctx, &entry)
+	return err
+}
+
+// createAuditIndexes creates the indexes behind the history of a document and
+// the global audit log, both listed newest first.
+func createAuditIndexes(database DatabaseService) error {
+	_, err := database.Collection(AuditCollection).Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{primitive.E{Key: "resource", Value: 1}, primitive.E{Key: "resource_id", Value: 1}, primitive.E{Key: "timestamp", Value: -1}}},
+		{Keys: bson.D{primitive.E{Key: "timestamp", Value: -1}, primitive.E{Key: "_id", Value: -1}}},
+	})
+	return err
+}
+
+// createPublicationIndexes creates the index the scheduler looks the due
+// videos up with.
+func createPublicationIndexes(database DatabaseService) error {
+	_, err := database.Collection(VideoCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{primitive.E{Key: "status", Value: 1}, primitive.E{Key: "publish_at", Value: 1}},
+	})
+	return err
+}
+
+// revision is a version of a document an update replaced, kept in the
+// revisions collection of its database.
+type revision struct {
+	Resource   string             `bson:"resource"`
+	ResourceID primitive.ObjectID `bson:"resource_id"`
+	Revision   int64              `bson:"revision"`
+	Document   interface{}        `bson:"document"`
+}
+
+// keepRevision stores the version of a document an update replaces, as part
+// of the transaction of the update.
+func keepRevision(ctx context.Context, collection *mongo.Collection, resource string, id primitive.ObjectID, version int64, document interface{}) error {
+	_, err := collection.Database().Collection(RevisionCollection).InsertOne(ctx,
+		&revision{Resource: resource, ResourceID: id, Revision: version, Document: document})
+	return err
+}
+
+// findRevisions decodes into results the revisions kept of a document, newest
+// first.
+func findRevisions(collection *mongo.Collection, resource string, id primitive.ObjectID, results interface{}) error {
+	cursor, err := collection.Database().Collection(RevisionCollection).Find(context.TODO(),
+		bson.M{"resource": resource, "resource_id": id},
+		options.Find().SetSort(bson.D{primitive.E{Key: "revision", Value: -1}}))
+	if err != nil {
+		return err
+	}
+	return cursor.All(context.TODO(), results)
+}
+
+// findRevision decodes into result the revision kept of a document at the
+// given version.
+func findRevision(collection *mongo.Collection, resource string, id primitive.ObjectID, version int64, result interface{}) error {
+	return notFound(collection.Database().Collection(RevisionCollection).FindOne(context.TODO(),
+		bson.M{"resource": resource, "resource_id": id, "revision": version}).Decode(result))
+}
+
+func makeExpiredFilter(ctx context.Context, deletedBefore time.Time) (bson.M, error) {
+	owner, err := domain.TrashOwner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filter := bson.M{"active": false, "deleted_at": bson.M{"$lt": deletedBefore}}
+	if owner != "" {
+		filter["owner_id"] = owner
+	}
+	return filter, nil
+}
+
+// purge removes the documents of collection matching filter along with their
+// revisions, returning how many documents were removed.
+func purge(collection *mongo.Collection, resource string, filter bson.M) (int64, error) {
+	ids, err := collection.Distinct(context.TODO(), "_id", filter)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	if _, err = collection.Database().Collection(RevisionCollection).DeleteMany(context.TODO(),
+		bson.M{"resource": resource, "resource_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+	result, err := collection.DeleteMany(context.TODO(), bson.M{"$and": bson.A{filter, bson.M{"_id": bson.M{"$in": ids}}}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// createRevisionIndexes creates the index listing the revisions of a document
+// newest first, which also keeps a single revision per version.
+func createRevisionIndexes(database DatabaseService) error {
+	_, err := database.Collection(RevisionCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{primitive.E{Key: "resource", Value: 1}, primitive.E{Key: "resource_id", Value: 1}, primitive.E{Key: "revision", Value: -1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// inTransaction runs fn inside a multi-document transaction. Standalone servers
+// reject transactions, so there fn runs again without one.
+func inTransaction(collection *mongo.Collection, fn func(ctx context.Context) error) error {
+	session, err := collection.Database().Client().StartSession()
+	if err != nil {
+		return fn(context.TODO())
+	}
+	defer session.EndSession(context.TODO())
+
+	err = mongo.WithSession(context.TODO(), session, func(sc mongo.SessionContext) error {
+		if err := sc.StartTransaction(); err != nil {
+			return err
+		}
+		if err := fn(sc); err != nil {
+			_ = sc.AbortTransaction(context.TODO())
+			return err
+		}
+		return sc.CommitTransaction(context.TODO())
+	})
+	if isTransactionNotSupported(err) {
+		return fn(context.TODO())
+	}
+	return err
+}
+
+func isTransactionNotSupported(err error) bool {
+	var commandErr mongo.CommandError
+	return errors.As(err, &commandErr) && commandErr.Code == illegalOperationCode
+}
+
+// notFound reports a missing document as interfaces.ErrNotFound, which is what
+// the service interfaces expect from every backend.
+func notFound(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return interfaces.ErrNotFound
+	}
+	return err
+}
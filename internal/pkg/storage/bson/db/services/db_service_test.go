@@ -2,8 +2,12 @@ package services
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
 func TestDBService_mountServerConnection(t *testing.T) {
@@ -27,3 +31,82 @@ func TestDBService_mountServerConnection(t *testing.T) {
 		}
 	})
 }
+
+func TestDBService_makeTitleFilter(t *testing.T) {
+	t.Run("Should match the folded title with the search quoted", func(t *testing.T) {
+		filter := makeTitleFilter("Programação (C++)")
+
+		assert.Equal(t, bson.M{"active": true, "titulo_search": bson.M{"$regex": `programacao \(c\+\+\)`}}, filter)
+	})
+}
+
+func TestDBService_backfillTitleSearch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("Should store the folded title of documents without one", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+				primitive.E{Key: "_id", Value: id},
+				primitive.E{Key: "titulo", Value: "Introdução à Lógica"},
+			}),
+			mtest.CreateSuccessResponse())
+
+		err := backfillTitleSearch(mt.Coll)
+
+		assert.Nil(t, err)
+		find := mt.GetStartedEvent().Command
+		assert.False(t, find.Lookup("filter", "titulo_search", "$exists").Boolean())
+		update := mt.GetStartedEvent().Command
+		assert.Equal(t, id, update.Lookup("updates", "0", "q", "_id").ObjectID())
+		assert.Equal(t, "introducao a logica", update.Lookup("updates", "0", "u", "$set", "titulo_search").StringValue())
+	})
+}
+
+func TestDBService_backfillUrlHost(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("Should store the folded url host of videos without one", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+				primitive.E{Key: "_id", Value: id},
+				primitive.E{Key: "url", Value: "https://www.YouTube.com/watch?v=1"},
+			}),
+			mtest.CreateSuccessResponse())
+
+		err := backfillUrlHost(mt.Coll)
+
+		assert.Nil(t, err)
+		find := mt.GetStartedEvent().Command
+		assert.False(t, find.Lookup("filter", "url_host", "$exists").Boolean())
+		update := mt.GetStartedEvent().Command
+		assert.Equal(t, id, update.Lookup("updates", "0", "q", "_id").ObjectID())
+		assert.Equal(t, "youtube.com", update.Lookup("updates", "0", "u", "$set", "url_host").StringValue())
+	})
+}
+
+func TestDBService_backfillAudit(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("Should date the documents without audit fields from their id", func(mt *mtest.T) {
+		createdAt := time.Date(2021, 8, 14, 12, 0, 0, 0, time.UTC)
+		id := primitive.NewObjectIDFromTimestamp(createdAt)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "_id", Value: id}}),
+			mtest.CreateSuccessResponse())
+
+		err := backfillAudit(mt.Coll)
+
+		assert.Nil(t, err)
+		find := mt.GetStartedEvent().Command
+		assert.False(t, find.Lookup("filter", "created_at", "$exists").Boolean())
+		set := mt.GetStartedEvent().Command.Lookup("updates", "0", "u", "$set").Document()
+		assert.Equal(t, createdAt, set.Lookup("created_at").Time().UTC())
+		assert.Equal(t, createdAt, set.Lookup("updated_at").Time().UTC())
+		assert.Equal(t, "", set.Lookup("created_by").StringValue())
+	})
+}
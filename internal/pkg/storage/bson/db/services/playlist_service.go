@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PlaylistService struct {
+	videoService       interfaces.IVideoService
+	playlistCollection *mongo.Collection
+}
+
+func ProvidePlaylistService(vs VideoService, database DatabaseService) PlaylistService {
+	return PlaylistService{&vs, database.Collection(PlaylistCollection)}
+}
+
+func (ps *PlaylistService) GetAll(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+	collectionFilter, findOptions := makeFindOptions(makePlaylistFilter(filter), dto.Sort{}, page, pageSize)
+	var playlists []models.Playlist
+	cursor, err := ps.playlistCollection.Find(context.TODO(), collectionFilter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = cursor.All(context.TODO(), &playlists); err != nil {
+		return nil, 0, err
+	}
+	total, err := ps.playlistCollection.CountDocuments(context.TODO(), collectionFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return playlists, total, nil
+}
+
+// makePlaylistFilter matches the playlists passing the filter. A viewer
+// matches the public playlists and their own.
+func makePlaylistFilter(filter dto.PlaylistFilter) bson.M {
+	collectionFilter := bson.M{}
+	if filter.OwnerID != "" {
+		collectionFilter["owner_id"] = filter.OwnerID
+	}
+	if filter.PublicOnly {
+		collectionFilter["visibility"] = models.PublicVisibility
+	} else if filter.Viewer != "" {
+		collectionFilter["$or"] = bson.A{
+			bson.M{"visibility": models.PublicVisibility},
+			bson.M{"owner_id": filter.Viewer},
+		}
+	}
+	return collectionFilter
+}
+
+func (ps *PlaylistService) GetByID(id primitive.ObjectID) (*models.Playlist, error) {
+	playlist := models.Playlist{}
+	if err := ps.playlistCollection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&playlist); err != nil {
+		return nil, notFound(err)
+	}
+	return &playlist, nil
+}
+
+func (ps *PlaylistService) Create(ctx context.Context, insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&insertPlaylist); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
+	playlist := insertPlaylist.ConvertToPlaylist()
+	playlist.Audit = models.NewAudit(author, createdAt)
+	playlist.OwnerID = author
+	if _, err := ps.playlistCollection.InsertOne(ctx, &playlist); err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (ps *PlaylistService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&newData); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(_ context.Context, playlist *models.Playlist, _ time.Time) error {
+		playlist.Titulo = newData.Titulo
+		playlist.Descricao = newData.Descricao
+		playlist.Visibility = newData.Visibility
+		return nil
+	})
+}
+
+// Delete removes the playlist for good, as only its owner has a use for it.
+func (ps *PlaylistService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	for {
+		playlist, err := ps.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err = domain.AuthorizePlaylist(ctx, *playlist, version); err != nil {
+			return err
+		}
+		result, err := ps.playlistCollection.DeleteOne(context.TODO(), bson.M{"_id": id, "version": playlist.Version})
+		if err != nil {
+			return err
+		}
+		if result.DeletedCount > 0 {
+			return nil
+		}
+	}
+}
+
+func (ps *PlaylistService) AddVideo(ctx context.Context, id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylistItem(ps.videoService, item); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(ctx context.Context, playlist *models.Playlist, addedAt time.Time) (err error) {
+		if err = checkVideo(ctx, ps.playlistCollection, item.VideoID); err != nil {
+			return err
+		}
+		playlist.Items, err = domain.AddPlaylistItem(playlist.Items, item, addedAt)
+		return err
+	})
+}
+
+func (ps *PlaylistService) RemoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(_ context.Context, playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.RemovePlaylistItem(playlist.Items, videoID)
+		return err
+	})
+}
+
+func (ps *PlaylistService) MoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(_ context.Context, playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.MovePlaylistItem(playlist.Items, videoID, position)
+		return err
+	})
+}
+
+// modify applies change to the playlist in a transaction and replaces it,
+// starting over when someone else changed it meanwhile.
+func (ps *PlaylistService) modify(request context.Context, id primitive.ObjectID, version int64,
+	change func(ctx context.Context, playlist *models.Playlist, at time.Time) error) (*models.Playlist, error) {
+	author, updatedAt := domain.Stamp(request)
+	for {
+		var playlist models.Playlist
+		replaced := false
+		err := inTransaction(ps.playlistCollection, func(ctx context.Context) error {
+			if err := ps.playlistCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&playlist); err != nil {
+				return notFound(err)
+			}
+			if err := domain.AuthorizePlaylist(request, playlist, version); err != nil {
+				return err
+			}
+			read := playlist.Version
+			if err := change(ctx, &playlist, updatedAt); err != nil {
+				return err
+			}
+			playlist.Touch(author, updatedAt)
+			playlist.Version++
+			result, err := ps.playlistCollection.ReplaceOne(ctx, bson.M{"_id": id, "version": read}, &playlist)
+			replaced = err == nil && result.MatchedCount > 0
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if replaced {
+			return &playlist, nil
+		}
+	}
+}
+
+// removeFromPlaylists takes the deleted videos out of every playlist, as part
+// of the transaction deleting them.
+func removeFromPlaylists(ctx context.Context, collection *mongo.Collection, ids []primitive.ObjectID, author string, deletedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := collection.Database().Collection(PlaylistCollection).UpdateMany(ctx,
+		bson.M{"items.video_id": bson.M{"$in": ids}},
+		bson.M{
+			"$pull": bson.M{"items": bson.M{"video_id": bson.M{"$in": ids}}},
+			"$set":  touched(author, deletedAt, bson.M{}),
+			"$inc":  bson.M{"version": 1},
+		})
+	return err
+}
+
+// createPlaylistIndexes creates the indexes finding the playlists of an owner
+// and the ones holding a video.
+func createPlaylistIndexes(database DatabaseService) error {
+	_, err := database.Collection(PlaylistCollection).Indexes().CreateMany(context.TODO(), []mongo.IndexModel{
+		{Keys: bson.D{primitive.E{Key: "owner_id", Value: 1}, primitive.E{Key: "_id", Value: 1}}},
+		{Keys: bson.D{primitive.E{Key: "items.video_id", Value: 1}}},
+	})
+	return err
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestPlaylistService(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	replaced := bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 1}, primitive.E{Key: "nModified", Value: 1}}
+
+	mt.Run("GetAll method Should return a page of the playlists the viewer may see and their total", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 3}}))
+
+		response, total, err := playlistService.GetAll(dto.PlaylistFilter{Viewer: "auth0|123"}, 1, 5)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, playlist.ID, response[0].ID)
+		visible := mt.GetStartedEvent().Command.Lookup("filter", "$or").Array()
+		assert.Equal(t, models.PublicVisibility, visible.Index(0).Value().Document().Lookup("visibility").StringValue())
+		assert.Equal(t, "auth0|123", visible.Index(1).Value().Document().Lookup("owner_id").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Create method Should create a private playlist owned by the token subject", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		response, err := playlistService.Create(mocked_data.GetContextWithSubject("auth0|123"), dto.InsertPlaylist{Titulo: " Favorites "})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Favorites", response.Titulo)
+		assert.Equal(t, models.PrivateVisibility, response.Visibility)
+		assert.Equal(t, "auth0|123", response.OwnerID)
+		assert.Equal(t, []models.PlaylistItem{}, response.Items)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Create method Should return a validation error When the visibility is unknown", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+
+		response, err := playlistService.Create(context.TODO(), dto.InsertPlaylist{Titulo: "Favorites", Visibility: "hidden"})
+
+		assert.Nil(t, response)
+		assert.IsType(t, dto.ValidationError{}, err)
+	})
+
+	mt.Run("AddVideo method Should insert the video at its position and bump the version", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlistService.videoService = &mocked_services.VideoServiceMock{}
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return mocked_data.GetValidVideoWithId(id), nil
+		}
+		first, second, added := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID(), first, second)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)),
+			mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(added))),
+			replaced,
+			mtest.CreateSuccessResponse())
+
+		response, err := playlistService.AddVideo(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID,
+			dto.InsertPlaylistItem{VideoID: added, Position: 2}, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), response.Version)
+		assert.Equal(t, []primitive.ObjectID{first, added, second},
+			[]primitive.ObjectID{response.Items[0].VideoID, response.Items[1].VideoID, response.Items[2].VideoID})
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.Equal(t, VideoCollection, mt.GetStartedEvent().Command.Lookup("find").StringValue())
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		assert.Equal(t, int64(1), update.Lookup("q", "version").AsInt64())
+		assert.Equal(t, added, update.Lookup("u", "items", "1", "video_id").ObjectID())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("AddVideo method Should return ErrVideoNotFound When the video is not active", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlistService.videoService = &mocked_services.VideoServiceMock{}
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return nil, interfaces.ErrNotFound
+		}
+
+		response, err := playlistService.AddVideo(context.TODO(), primitive.NewObjectID(),
+			dto.InsertPlaylistItem{VideoID: primitive.NewObjectID()}, 0)
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrVideoNotFound, err)
+	})
+
+	mt.Run("AddVideo method Should return ErrVideoNotFound When the video is deleted before the playlist is written", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlistService.videoService = &mocked_services.VideoServiceMock{}
+		mocked_services.VideoServiceMockGetById = func(id primitive.ObjectID) (*models.Video, error) {
+			return mocked_data.GetValidVideoWithId(id), nil
+		}
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)),
+			mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch))
+
+		response, err := playlistService.AddVideo(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID,
+			dto.InsertPlaylistItem{VideoID: primitive.NewObjectID()}, 0)
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrVideoNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("MoveVideo method Should read the playlist again When someone else changed it meanwhile", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		first, second := primitive.NewObjectID(), primitive.NewObjectID()
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID(), first, second)
+		changed := *playlist
+		changed.Version = 2
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)),
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 0}},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(&changed)),
+			replaced,
+			mtest.CreateSuccessResponse())
+
+		response, err := playlistService.MoveVideo(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID, second, 1, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), response.Version)
+		assert.Equal(t, []primitive.ObjectID{second, first}, []primitive.ObjectID{response.Items[0].VideoID, response.Items[1].VideoID})
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("MoveVideo method Should return ErrVersionMismatch When the expected version is stale", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		videoID := primitive.NewObjectID()
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID(), videoID)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)))
+
+		response, err := playlistService.MoveVideo(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID, videoID, 1, 2)
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RemoveVideo method Should return ErrNotOwner When neither the owner nor an admin changes the playlist", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		videoID := primitive.NewObjectID()
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID(), videoID)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)))
+
+		response, err := playlistService.RemoveVideo(mocked_data.GetContextWithSubject("auth0|456"), playlist.ID, videoID, 0)
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RemoveVideo method Should return ErrVideoNotInPlaylist When the playlist does not hold the video", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID(), primitive.NewObjectID())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)))
+
+		response, err := playlistService.RemoveVideo(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID, primitive.NewObjectID(), 0)
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrVideoNotInPlaylist, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Delete method Should remove the playlist at the version it was read at", func(mt *mtest.T) {
+		var playlistService = PlaylistService{}
+		playlistService.playlistCollection = mt.Coll
+		playlist := mocked_data.GetValidPlaylistWithId(primitive.NewObjectID())
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromPlaylist(playlist)),
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 1}})
+
+		err := playlistService.Delete(mocked_data.GetContextWithSubject("auth0|123"), playlist.ID, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		filter := mt.GetStartedEvent().Command.Lookup("deletes", "0", "q").Document()
+		assert.Equal(t, playlist.ID, filter.Lookup("_id").ObjectID())
+		assert.Equal(t, int64(1), filter.Lookup("version").AsInt64())
+		mt.ClearMockResponses()
+	})
+}
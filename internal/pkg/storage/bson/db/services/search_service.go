@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/search"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SearchService struct {
+	videosCollection   *mongo.Collection
+	categoryCollection *mongo.Collection
+}
+
+func ProvideSearchService(database DatabaseService) SearchService {
+	return SearchService{database.Collection(VideoCollection), database.Collection(CategoriesCollection)}
+}
+
+// createTextIndexes creates the text indexes that Search ranks with, weighted
+// like the in-process scorer of the other backends.
+func createTextIndexes(database DatabaseService) error {
+	_, err := database.Collection(VideoCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{primitive.E{Key: "titulo", Value: "text"}, primitive.E{Key: "descricao", Value: "text"}},
+		Options: options.Index().
+			SetWeights(bson.M{"titulo": search.TituloWeight, "descricao": search.DescricaoWeight}).
+			SetDefaultLanguage(search.Language),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = database.Collection(CategoriesCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys:    bson.D{primitive.E{Key: "titulo", Value: "text"}},
+		Options: options.Index().SetDefaultLanguage(search.Language),
+	})
+	return err
+}
+
+// createSuggestIndexes creates the ascending titulo_search indexes that let
+// Suggest match a prefix without scanning the collections.
+func createSuggestIndexes(database DatabaseService) error {
+	for _, name := range []string{VideoCollection, CategoriesCollection} {
+		_, err := database.Collection(name).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+			Keys: bson.D{primitive.E{Key: "titulo_search", Value: 1}},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeTextFindOptions matches the active documents holding any of the terms,
+// from the highest text score.
+func makeTextFindOptions(terms []string, limit int64) (bson.M, *options.FindOptions) {
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find()
+	findOptions.SetProjection(bson.M{"score": score})
+	findOptions.SetSort(bson.M{"score": score})
+	findOptions.SetLimit(limit)
+	return bson.M{"$text": bson.M{"$search": strings.Join(terms, " ")}, "active": true}, findOptions
+}
+
+// makePublishedFilter copies the filter of a video query, narrowing it down to
+// the published videos when publishedOnly is set.
+func makePublishedFilter(collectionFilter bson.M, publishedOnly bool) bson.M {
+	videoFilter := bson.M{}
+	for key, value := range collectionFilter {
+		videoFilter[key] = value
+	}
+	if publishedOnly {
+		videoFilter["status"] = models.PublishedStatus
+	}
+	return videoFilter
+}
+
+// Search ranks with the text indexes and highlights the matches in process.
+func (ss *SearchService) Search(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+	terms := search.Terms(query)
+	results := dto.SearchResults{Query: query, Videos: []dto.VideoHit{}, Categories: []dto.CategoryHit{}}
+	collectionFilter, findOptions := makeTextFindOptions(terms, limit)
+
+	var videos []struct {
+		models.Video `bson:",inline"`
+		Score        float64 `bson:"score"`
+	}
+	cursor, err := ss.videosCollection.Find(context.TODO(), makePublishedFilter(collectionFilter, publishedOnly), findOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &videos); err != nil {
+		return nil, err
+	}
+	for _, video := range videos {
+		results.Videos = append(results.Videos, dto.VideoHit{
+			Video: video.Video, Score: video.Score, Highlights: search.VideoHighlights(video.Video, terms),
+		})
+	}
+
+	var categories []struct {
+		models.Category `bson:",inline"`
+		Score           float64 `bson:"score"`
+	}
+	cursor, err = ss.categoryCollection.Find(context.TODO(), collectionFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &categories); err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		results.Categories = append(results.Categories, dto.CategoryHit{
+			Category: category.Category, Score: category.Score, Highlights: search.CategoryHighlights(category.Category, terms),
+		})
+	}
+	return &results, nil
+}
+
+// makeSuggestFindOptions matches the active documents whose folded title
+// starts with the prefix. An anchored regex on titulo_search is answered from
+// its index.
+func makeSuggestFindOptions(prefix string, limit int64) (bson.M, *options.FindOptions) {
+	findOptions := options.Find()
+	findOptions.SetProjection(bson.M{"_id": 1, "titulo": 1})
+	findOptions.SetSort(bson.D{primitive.E{Key: "titulo_search", Value: 1}})
+	findOptions.SetLimit(limit)
+	return bson.M{
+		"titulo_search": bson.M{"$regex": "^" + regexp.QuoteMeta(dto.NormalizeSearch(prefix))},
+		"active":        true,
+	}, findOptions
+}
+
+// Suggest completes the prefix with video titles and, unless freeOnly is set,
+// category titles. With freeOnly only the published videos of the FREE
+// category match.
+func (ss *SearchService) Suggest(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+	collectionFilter, findOptions := makeSuggestFindOptions(prefix, limit)
+	var suggestions []dto.Suggestion
+
+	videoFilter := makePublishedFilter(collectionFilter, freeOnly || publishedOnly)
+	if freeOnly {
+		videoFilter["category_id"] = models.GetFreeCategory().ID
+	}
+	var videos []models.Video
+	cursor, err := ss.videosCollection.Find(context.TODO(), videoFilter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &videos); err != nil {
+		return nil, err
+	}
+	for _, video := range videos {
+		suggestions = append(suggestions, dto.Suggestion{ID: video.ID, Titulo: video.Titulo, Type: dto.VideoSuggestion})
+	}
+
+	if !freeOnly {
+		var categories []models.Category
+		cursor, err = ss.categoryCollection.Find(context.TODO(), collectionFilter, findOptions)
+		if err != nil {
+			return nil, err
+		}
+		if err = cursor.All(context.TODO(), &categories); err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			suggestions = append(suggestions, dto.Suggestion{ID: category.ID, Titulo: category.Titulo, Type: dto.CategorySuggestion})
+		}
+	}
+	return search.SortSuggestions(suggestions, limit), nil
+}
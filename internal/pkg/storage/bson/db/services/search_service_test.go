@@ -0,0 +1,146 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSearchService(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("Search method Should rank by text score and highlight the matches", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		video := mocked_data.GetValidVideoWithId(primitive.NewObjectID())
+		category := mocked_data.GetValidCategory()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch,
+				append(mocked_data.GetBsonFromVideo(video), primitive.E{Key: "score", Value: 1.5})),
+			mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch,
+				append(mocked_data.GetBsonFromCategory(category), primitive.E{Key: "score", Value: 0.75})))
+
+		results, err := searchService.Search("Unit de TEST", false, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(results.Videos))
+		assert.Equal(t, video.ID, results.Videos[0].Video.ID)
+		assert.Equal(t, 1.5, results.Videos[0].Score)
+		assert.Equal(t, "<mark>unit</mark> <mark>test</mark> title", results.Videos[0].Highlights["titulo"])
+		assert.Equal(t, 1, len(results.Categories))
+		assert.Equal(t, 0.75, results.Categories[0].Score)
+
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, "unit test", command.Lookup("filter", "$text", "$search").StringValue())
+		assert.True(t, command.Lookup("filter", "active").Boolean())
+		assert.Equal(t, "textScore", command.Lookup("sort", "score", "$meta").StringValue())
+		assert.Equal(t, int64(10), command.Lookup("limit").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Search method Should only query published videos When publishedOnly is set", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch))
+
+		_, err := searchService.Search("go", true, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.PublishedStatus, mt.GetStartedEvent().Command.Lookup("filter", "status").StringValue())
+		_, missing := mt.GetStartedEvent().Command.Lookup("filter").Document().LookupErr("status")
+		assert.Error(t, missing)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Search method Should return the error When the text index is missing", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{
+			Code: 27, Message: "text index required for $text query",
+		}))
+
+		results, err := searchService.Search("go", false, 10)
+
+		assert.Nil(t, results)
+		assert.NotNil(t, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("createTextIndexes method Should weight the title above the description", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		err := createTextIndexes(DatabaseService{mt.DB})
+
+		assert.Nil(t, err)
+		index := mt.GetStartedEvent().Command.Lookup("indexes", "0").Document()
+		assert.Equal(t, "text", index.Lookup("key", "descricao").StringValue())
+		assert.Equal(t, int64(3), index.Lookup("weights", "titulo").AsInt64())
+		assert.Equal(t, "portuguese", index.Lookup("default_language").StringValue())
+	})
+
+	mt.Run("Suggest method Should complete the prefix with videos and categories", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		video := mocked_data.GetValidVideoWithId(primitive.NewObjectID())
+		category := mocked_data.GetValidCategory()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch, mocked_data.GetBsonFromVideo(video)),
+			mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch, mocked_data.GetBsonFromCategory(category)))
+
+		suggestions, err := searchService.Suggest("Ún(", false, false, 5)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(suggestions))
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, "^un\\(", command.Lookup("filter", "titulo_search", "$regex").StringValue())
+		assert.True(t, command.Lookup("filter", "active").Boolean())
+		_, missing := command.Lookup("filter").Document().LookupErr("category_id")
+		assert.Error(t, missing)
+		assert.Equal(t, int32(1), command.Lookup("sort", "titulo_search").Int32())
+		assert.Equal(t, int64(5), command.Lookup("limit").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Suggest method Should only query FREE videos When freeOnly is set", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		video := mocked_data.GetValidVideoWithId(primitive.NewObjectID())
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.videos", mtest.FirstBatch, mocked_data.GetBsonFromVideo(video)))
+
+		suggestions, err := searchService.Suggest("un", true, false, 5)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.Suggestion{{ID: video.ID, Titulo: video.Titulo, Type: dto.VideoSuggestion}}, suggestions)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, primitive.NilObjectID, command.Lookup("filter", "category_id").ObjectID())
+		assert.Nil(t, mt.GetStartedEvent())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Suggest method Should return the error When the find fails", func(mt *mtest.T) {
+		var searchService = SearchService{mt.Coll, mt.Coll}
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "failure"}))
+
+		suggestions, err := searchService.Suggest("un", false, false, 5)
+
+		assert.Nil(t, suggestions)
+		assert.NotNil(t, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("createSuggestIndexes method Should index titulo_search of both collections", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		err := createSuggestIndexes(DatabaseService{mt.DB})
+
+		assert.Nil(t, err)
+		for _, name := range []string{VideoCollection, CategoriesCollection} {
+			command := mt.GetStartedEvent().Command
+			assert.Equal(t, name, command.Lookup("createIndexes").StringValue())
+			assert.Equal(t, int32(1), command.Lookup("indexes", "0", "key", "titulo_search").Int32())
+		}
+	})
+}
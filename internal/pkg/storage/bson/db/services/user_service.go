@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UserService struct {
+	userCollection *mongo.Collection
+}
+
+func ProvideUserService(database DatabaseService) UserService {
+	return UserService{database.Collection(UserCollection)}
+}
+
+func (us *UserService) GetAll(page int64, pageSize int64) ([]models.User, int64, error) {
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "created_at", Value: -1}, primitive.E{Key: "_id", Value: 1}})
+	findOptions.SetLimit(pageSize)
+	findOptions.SetSkip((page - 1) * pageSize)
+	var users []models.User
+	cursor, err := us.userCollection.Find(context.TODO(), bson.M{}, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = cursor.All(context.TODO(), &users); err != nil {
+		return nil, 0, err
+	}
+	total, err := us.userCollection.CountDocuments(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (us *UserService) GetByID(id string) (*models.User, error) {
+	var user models.User
+	if err := us.userCollection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&user); err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (us *UserService) Save(user models.User) (*models.User, error) {
+	_, err := us.userCollection.UpdateOne(context.TODO(), bson.M{"_id": user.ID},
+		bson.M{
+			"$set": bson.M{
+				"name":         user.Name,
+				"nickname":     user.Nickname,
+				"email":        user.Email,
+				"picture":      user.Picture,
+				"last_seen_at": user.LastSeenAt.UTC().Truncate(time.Millisecond),
+			},
+			"$setOnInsert": bson.M{"created_at": user.CreatedAt.UTC().Truncate(time.Millisecond)},
+		},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return nil, err
+	}
+	return us.GetByID(user.ID)
+}
+
+// createUserIndexes creates the index users are listed by, and the one
+// finding the videos of an owner.
+func createUserIndexes(database DatabaseService) error {
+	_, err := database.Collection(UserCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{primitive.E{Key: "created_at", Value: -1}, primitive.E{Key: "_id", Value: 1}},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = database.Collection(VideoCollection).Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{primitive.E{Key: "owner_id", Value: 1}},
+	})
+	return err
+}
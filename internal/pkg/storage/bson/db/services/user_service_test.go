@@ -0,0 +1,64 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUserService(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	defer mt.Close()
+
+	mt.Run("GetAll method Should return a page of the users newest first and their total", func(mt *mtest.T) {
+		var userService = UserService{}
+		userService.userCollection = mt.Coll
+		user := mocked_data.GetValidUser()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromUser(user)),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 3}}))
+
+		response, total, err := userService.GetAll(2, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []string{user.ID}, []string{response[0].ID})
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetByID method Should return ErrNotFound When the user is unknown", func(mt *mtest.T) {
+		var userService = UserService{}
+		userService.userCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		response, err := userService.GetByID("unknown")
+
+		assert.Nil(t, response)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("Save method Should upsert the profile and only set the creation on insert", func(mt *mtest.T) {
+		var userService = UserService{}
+		userService.userCollection = mt.Coll
+		user := mocked_data.GetValidUser()
+		mt.AddMockResponses(
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "n", Value: 1}},
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromUser(user)))
+
+		response, err := userService.Save(user)
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, *response)
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0")
+		assert.True(t, update.Document().Lookup("upsert").Boolean())
+		assert.Equal(t, user.Name, update.Document().Lookup("u", "$set", "name").StringValue())
+		assert.Equal(t, user.CreatedAt, update.Document().Lookup("u", "$setOnInsert", "created_at").Time().UTC())
+		mt.ClearMockResponses()
+	})
+}
@@ -2,8 +2,9 @@ package services
 
 import (
 	"context"
-	"errors"
+	"time"
 
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
@@ -25,7 +26,8 @@ func ProvideVideoService(cs CategoryService, service DatabaseService) VideoServi
 func (vs *VideoService) GetAllFreeVideos() ([]models.Video, error) {
 	var Videos []models.Video
 	freeCategory := vs.categoryService.GetFreeCategory()
-	cursor, err := vs.videosCollection.Find(context.TODO(), bson.M{"category_id": freeCategory.ID})
+	cursor, err := vs.videosCollection.Find(context.TODO(), bson.M{"category_id": freeCategory.ID, "active": true,
+		"status": models.PublishedStatus})
 
 	if err != nil {
 		return nil, err
@@ -35,61 +37,351 @@ func (vs *VideoService) GetAllFreeVideos() ([]models.Video, error) {
 	return Videos, nil
 }
 
-func (vs *VideoService) GetAll(filter string, page int64, pageSize int64) ([]models.Video, error) {
-	collectionFilter, findOptions := makeFindOptions(filter, page, pageSize)
+func (vs *VideoService) GetAll(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+	collectionFilter, findOptions := makeFindOptions(makeVideoFilter(filter), sort, page, pageSize)
 	var Videos []models.Video
 	cursor, err := vs.videosCollection.Find(context.TODO(), collectionFilter, findOptions)
 
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	_ = cursor.All(context.TODO(), &Videos)
-	return Videos, err
+	total, err := vs.videosCollection.CountDocuments(context.TODO(), collectionFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return Videos, total, nil
+}
+
+func (vs *VideoService) GetAllAfter(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	collectionFilter, findOptions := makeCursorFindOptions(makeVideoFilter(filter), sort, after, limit)
+	var videos []models.Video
+	cursor, err := vs.videosCollection.Find(context.TODO(), collectionFilter, findOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = cursor.All(context.TODO(), &videos); err != nil {
+		return nil, nil, err
+	}
+	videos, next := dto.NextVideoPage(videos, sort, limit)
+	return videos, next, nil
+}
+
+// GetFacets counts both facets in a single aggregation. The shared filters
+// are matched first, then each facet matches the filter of the other one.
+func (vs *VideoService) GetFacets(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+	cursor, err := vs.videosCollection.Aggregate(context.TODO(), makeFacetPipeline(filter))
+	if err != nil {
+		return nil, err
+	}
+	var results []struct {
+		Categories []struct {
+			ID    primitive.ObjectID `bson:"_id"`
+			Count int64              `bson:"count"`
+		} `bson:"categorias"`
+		Hosts []struct {
+			ID    string `bson:"_id"`
+			Count int64  `bson:"count"`
+		} `bson:"hosts"`
+	}
+	if err = cursor.All(context.TODO(), &results); err != nil {
+		return nil, err
+	}
+	facets := dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}
+	if len(results) == 0 {
+		return &facets, nil
+	}
+	for _, category := range results[0].Categories {
+		facets.Categories = append(facets.Categories, dto.FacetCount{Value: category.ID.Hex(), Count: category.Count})
+	}
+	for _, host := range results[0].Hosts {
+		facets.Hosts = append(facets.Hosts, dto.FacetCount{Value: host.ID, Count: host.Count})
+	}
+	dto.SortFacet(facets.Categories)
+	dto.SortFacet(facets.Hosts)
+	return &facets, nil
+}
+
+// makeVideoFilter matches the videos passing the filter. The creation range
+// bounds _id, whose leading bytes are its creation time.
+func makeVideoFilter(filter dto.VideoFilter) bson.M {
+	collectionFilter := makeTitleFilter(filter.Search)
+	collectionFilter["active"] = filter.IsActive()
+	created := bson.M{}
+	if !filter.CreatedFrom.IsZero() {
+		created["$gte"] = dto.CreatedBound(filter.CreatedFrom)
+	}
+	if !filter.CreatedBefore.IsZero() {
+		created["$lt"] = dto.CreatedBound(filter.CreatedBefore)
+	}
+	if len(created) > 0 {
+		collectionFilter["_id"] = created
+	}
+	if len(filter.CategoryIDs) > 0 {
+		collectionFilter["category_id"] = bson.M{"$in": filter.CategoryIDs}
+	}
+	if len(filter.Hosts) > 0 {
+		collectionFilter["url_host"] = bson.M{"$in": filter.Hosts}
+	}
+	if len(filter.Statuses) > 0 {
+		collectionFilter["status"] = bson.M{"$in": filter.Statuses}
+	}
+	if filter.OwnerID != "" {
+		collectionFilter["owner_id"] = filter.OwnerID
+	}
+	return collectionFilter
+}
+
+func makeFacetPipeline(filter dto.VideoFilter) mongo.Pipeline {
+	byCategory, byHost := bson.M{}, bson.M{}
+	if len(filter.Hosts) > 0 {
+		byCategory["url_host"] = bson.M{"$in": filter.Hosts}
+	}
+	if len(filter.CategoryIDs) > 0 {
+		byHost["category_id"] = bson.M{"$in": filter.CategoryIDs}
+	}
+	count := bson.M{"$sum": 1}
+	return mongo.Pipeline{
+		{{Key: "$match", Value: makeVideoFilter(filter.WithoutCategories().WithoutHosts())}},
+		{{Key: "$facet", Value: bson.M{
+			"categorias": bson.A{
+				bson.M{"$match": byCategory},
+				bson.M{"$group": bson.M{"_id": "$category_id", "count": count}},
+			},
+			"hosts": bson.A{
+				bson.M{"$match": byHost},
+				bson.M{"$group": bson.M{"_id": "$url_host", "count": count}},
+			},
+		}}},
+	}
 }
 
 func (vs *VideoService) GetByID(id primitive.ObjectID) (*models.Video, error) {
 	Video := models.Video{}
-	if err := vs.videosCollection.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&Video); err != nil {
-		return nil, err
+	if err := vs.videosCollection.FindOne(context.TODO(), bson.M{"_id": id, "active": true}).Decode(&Video); err != nil {
+		return nil, notFound(err)
 	}
 	return &Video, nil
 }
 
-func (vs *VideoService) Create(model dto.InsertVideo) (*models.Video, error) {
+func (vs *VideoService) Create(ctx context.Context, model dto.InsertVideo) (*models.Video, error) {
+	if err := domain.PrepareVideo(vs.categoryService, &model); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
 	convertedVideo := model.ConvertToVideo()
-	if convertedVideo.CategoryID.IsZero() {
-		_ = vs.categoryService.GetFreeCategory()
+	convertedVideo.Audit = models.NewAudit(author, createdAt)
+	convertedVideo.OwnerID = author
+	err := inTransaction(vs.videosCollection, func(ctx context.Context) error {
+		if err := checkCategory(ctx, vs.videosCollection, convertedVideo.CategoryID); err != nil {
+			return err
+		}
+		if _, err := vs.videosCollection.InsertOne(ctx, &convertedVideo); err != nil {
+			return err
+		}
+		return recordAudit(ctx, vs.videosCollection, domain.NewAuditEntry(author, createdAt, models.VideoResource,
+			convertedVideo.ID, models.CreateOperation, nil, convertedVideo))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &convertedVideo, nil
+}
+
+func (vs *VideoService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoUpdate(vs.categoryService, &newData); err != nil {
+		return nil, err
+	}
+	fields := bson.M{
+		"titulo":        newData.Titulo,
+		"titulo_search": dto.NormalizeSearch(newData.Titulo),
+		"descricao":     newData.Descricao,
+		"url":           newData.Url,
+		"url_host":      dto.URLHost(newData.Url),
+		"category_id":   newData.CategoryID,
 	}
+	if newData.Status != "" {
+		fields["status"] = newData.Status
+		fields["publish_at"] = newData.PublishAt
+	}
+	return vs.update(ctx, id, version, fields)
+}
 
-	if _, err := vs.categoryService.GetById(convertedVideo.CategoryID); err == mongo.ErrNoDocuments {
-		return nil, errors.New("Category with id " + convertedVideo.CategoryID.Hex() + " dont exists.")
+// Patch changes only the fields present in the merge patch.
+func (vs *VideoService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoPatch(vs.categoryService, &patch); err != nil {
+		return nil, err
+	}
+	fields := bson.M{}
+	if patch.Titulo != nil {
+		fields["titulo"] = *patch.Titulo
+		fields["titulo_search"] = dto.NormalizeSearch(*patch.Titulo)
+	}
+	if patch.Descricao != nil {
+		fields["descricao"] = *patch.Descricao
+	}
+	if patch.Url != nil {
+		fields["url"] = *patch.Url
+		fields["url_host"] = dto.URLHost(*patch.Url)
 	}
-	_, err := vs.videosCollection.InsertOne(context.TODO(), &convertedVideo)
+	if patch.CategoryID != nil {
+		fields["category_id"] = *patch.CategoryID
+	}
+	if patch.Status != nil {
+		fields["status"] = *patch.Status
+		fields["publish_at"] = patch.PublishAt
+	}
+	if len(fields) == 0 {
+		video, err := vs.GetByID(id)
+		if err == nil && version != 0 && video.Version != version {
+			return nil, interfaces.ErrVersionMismatch
+		}
+		return video, err
+	}
+	return vs.update(ctx, id, version, fields)
+}
+
+func (vs *VideoService) update(ctx context.Context, id primitive.ObjectID, version int64, fields bson.M) (*models.Video, error) {
+	author, updatedAt := domain.Stamp(ctx)
+	var before, video models.Video
+	err := audited(ctx, vs.videosCollection, models.VideoResource, id, true, models.UpdateOperation, author, updatedAt, &before, &video,
+		func(ctx context.Context) error {
+			if categoryID, ok := fields["category_id"].(primitive.ObjectID); ok {
+				if err := checkCategory(ctx, vs.videosCollection, categoryID); err != nil {
+					return err
+				}
+			}
+			if err := updateVersioned(ctx, vs.videosCollection, id, version, touched(author, updatedAt, fields), &video); err != nil {
+				return err
+			}
+			return keepRevision(ctx, vs.videosCollection, models.VideoResource, id, before.Version, before)
+		})
 	if err != nil {
 		return nil, err
 	}
-	return &convertedVideo, err
+	return &video, nil
 }
 
-func (vs *VideoService) Update(id primitive.ObjectID, newData dto.InsertVideo) (*models.Video, error) {
-	var video *models.Video
-	if err := vs.videosCollection.FindOneAndUpdate(
-		context.Background(),
-		bson.D{
-			primitive.E{Key: "_id", Value: id},
-		},
-		bson.D{primitive.E{Key: "$set", Value: newData}},
-		options.FindOneAndUpdate().SetReturnDocument(1),
-	).Decode(&video); err != nil {
+func (vs *VideoService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	author, deletedAt := domain.Stamp(ctx)
+	var before, video models.Video
+	err := audited(ctx, vs.videosCollection, models.VideoResource, id, true, models.DeleteOperation, author, deletedAt, &before, &video,
+		func(ctx context.Context) error {
+			if err := softDelete(ctx, vs.videosCollection, id, version, author, deletedAt); err != nil {
+				return err
+			}
+			if err := removeFromPlaylists(ctx, vs.videosCollection, []primitive.ObjectID{id}, author, deletedAt); err != nil {
+				return err
+			}
+			video = before
+			video.Active = false
+			video.DeletedAt = &deletedAt
+			video.Touch(author, deletedAt)
+			video.Version++
+			return nil
+		})
+	return err
+}
+
+func (vs *VideoService) GetDeleted(page int64, pageSize int64) ([]models.Video, error) {
+	collectionFilter, findOptions := makeTrashFindOptions(page, pageSize)
+	var Videos []models.Video
+	cursor, err := vs.videosCollection.Find(context.TODO(), collectionFilter, findOptions)
+
+	if err != nil {
 		return nil, err
 	}
-	return video, nil
+	_ = cursor.All(context.TODO(), &Videos)
+	return Videos, err
 }
 
-func (vs *VideoService) Delete(id primitive.ObjectID) error {
-	result, err := vs.videosCollection.DeleteOne(context.TODO(), bson.M{"_id": id})
-	if result.DeletedCount == 0 {
-		return errors.New("no document deleted")
+func (vs *VideoService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Video, error) {
+	author, restoredAt := domain.Stamp(ctx)
+	var before, video models.Video
+	err := audited(ctx, vs.videosCollection, models.VideoResource, id, false, models.RestoreOperation, author, restoredAt, &before, &video,
+		func(ctx context.Context) error {
+			if err := checkCategory(ctx, vs.videosCollection, before.CategoryID); err != nil {
+				return err
+			}
+			return restore(ctx, vs.videosCollection, id, author, restoredAt, &video)
+		})
+	if err != nil {
+		return nil, err
 	}
-	return err
+	return &video, nil
+}
+
+// Purge removes the expired videos of the trash ctx may purge, with their
+// revisions.
+func (vs *VideoService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	filter, err := makeExpiredFilter(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return purge(vs.videosCollection, models.VideoResource, filter)
+}
+
+func (vs *VideoService) GetRevisions(id primitive.ObjectID) ([]models.VideoRevision, error) {
+	revisions := []models.VideoRevision{}
+	if err := findRevisions(vs.videosCollection, models.VideoResource, id, &revisions); err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the video back to the fields it had at a revision,
+// with the same checks as any other update.
+func (vs *VideoService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+	var snapshot models.VideoRevision
+	if err := findRevision(vs.videosCollection, models.VideoResource, id, revision, &snapshot); err != nil {
+		return nil, err
+	}
+	return vs.Update(ctx, id, dto.InsertVideoFrom(snapshot.Video), version)
+}
+
+// PublishDue publishes the scheduled videos due by now, leaving a video
+// changed since it was selected for the next run.
+func (vs *VideoService) PublishDue(ctx context.Context, now time.Time) ([]models.Video, error) {
+	if err := domain.Authorize(ctx, ""); err != nil {
+		return nil, err
+	}
+	author, _ := domain.Stamp(ctx)
+	var due []models.Video
+	cursor, err := vs.videosCollection.Find(context.TODO(), bson.M{
+		"active":     true,
+		"status":     models.ScheduledStatus,
+		"publish_at": bson.M{"$lte": now},
+	}, options.Find().SetSort(bson.D{primitive.E{Key: "_id", Value: 1}}).SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	if err = cursor.All(context.TODO(), &due); err != nil {
+		return nil, err
+	}
+	publishedAt := now.UTC().Truncate(time.Millisecond)
+	var published []models.Video
+	for _, video := range due {
+		id := video.ID
+		var before, after models.Video
+		err = audited(ctx, vs.videosCollection, models.VideoResource, id, true, models.PublishOperation, author, publishedAt,
+			&before, &after, func(ctx context.Context) error {
+				return notFound(vs.videosCollection.FindOneAndUpdate(ctx,
+					bson.M{"_id": id, "active": true, "status": models.ScheduledStatus},
+					bson.M{
+						"$set":   touched(author, publishedAt, bson.M{"status": models.PublishedStatus}),
+						"$unset": bson.M{"publish_at": ""},
+						"$inc":   bson.M{"version": 1},
+					},
+					options.FindOneAndUpdate().SetReturnDocument(options.After),
+				).Decode(&after))
+			})
+		if err == interfaces.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return published, err
+		}
+		published = append(published, after)
+	}
+	return published, nil
 }
@@ -1,22 +1,27 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
 	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_services"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
 )
 
 func TestVideoService(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 	defer mt.Close()
+	activeCategory := mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch, mocked_data.GetBsonFromCategory(models.GetFreeCategory()))
 
 	mt.Run("GetAllFreeVideos method Should return object when has objects", func(mt *mtest.T) {
 
@@ -40,6 +45,8 @@ func TestVideoService(t *testing.T) {
 		videoResponse, err := videoService.GetAllFreeVideos()
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(videoResponse))
+		filter := mt.GetStartedEvent().Command.Lookup("filter")
+		assert.Equal(t, models.PublishedStatus, filter.Document().Lookup("status").StringValue())
 		mt.ClearMockResponses()
 	})
 
@@ -74,11 +81,13 @@ func TestVideoService(t *testing.T) {
 		secondVideo := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(secondId)))
 
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
-		mt.AddMockResponses(firstVideo, secondVideo, killCursors)
+		count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 7}})
+		mt.AddMockResponses(firstVideo, secondVideo, killCursors, count)
 
-		videoResponse, err := videoService.GetAll("", 1, 5)
+		videoResponse, total, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 5)
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(videoResponse))
+		assert.Equal(t, int64(7), total)
 		mt.ClearMockResponses()
 	})
 
@@ -94,11 +103,13 @@ func TestVideoService(t *testing.T) {
 		secondVideo := mtest.CreateCursorResponse(1, "foo.bar", mtest.NextBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(secondId)))
 
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
-		mt.AddMockResponses(firstVideo, secondVideo, killCursors)
+		count := mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 7}})
+		mt.AddMockResponses(firstVideo, secondVideo, killCursors, count)
 
-		videoResponse, err := videoService.GetAll("test", 1, 5)
+		videoResponse, total, err := videoService.GetAll(dto.VideoFilter{Search: "test"}, dto.Sort{}, 1, 5)
 		assert.Nil(t, err)
 		assert.Equal(t, 2, len(videoResponse))
+		assert.Equal(t, int64(7), total)
 		mt.ClearMockResponses()
 	})
 
@@ -109,12 +120,124 @@ func TestVideoService(t *testing.T) {
 		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
 		mt.AddMockResponses(bson.D{}, killCursors)
 
-		videoResponse, err := videoService.GetAll("", 1, 5)
+		videoResponse, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 5)
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(videoResponse))
 		mt.ClearMockResponses()
 	})
 
+	mt.Run("GetAllVideosAfter method Should query after the cursor and return the next one", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		after := primitive.NewObjectID()
+		firstId := primitive.NewObjectID()
+		secondId := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch,
+			mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(firstId)),
+			mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(secondId))))
+
+		response, next, err := videoService.GetAllAfter(dto.VideoFilter{}, dto.Sort{}, &dto.Cursor{ID: after}, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, &dto.Cursor{ID: firstId}, next)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, after, command.Lookup("filter", "_id", "$gt").ObjectID())
+		assert.Equal(t, int64(2), command.Lookup("limit").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetAllVideosAfter method Should sort by the field and page after its value When sorting by titulo", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		after := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		_, _, err := videoService.GetAllAfter(dto.VideoFilter{}, dto.Sort{Field: "titulo", Descending: true}, &dto.Cursor{ID: after, Sort: "-titulo", Value: "Go"}, 1)
+
+		assert.Nil(t, err)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, "Go", command.Lookup("filter", "$or", "0", "titulo", "$lt").StringValue())
+		assert.Equal(t, after, command.Lookup("filter", "$or", "1", "_id", "$lt").ObjectID())
+		sort := command.Lookup("sort").Document()
+		assert.Equal(t, int64(-1), sort.Lookup("titulo").AsInt64())
+		assert.Equal(t, int64(-1), sort.Lookup("_id").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetAllVideos method Should match the category, host, state and creation filters", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		category := primitive.NewObjectID()
+		createdFrom := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+		inactive := false
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 0}}))
+
+		_, _, err := videoService.GetAll(dto.VideoFilter{
+			CategoryIDs: []primitive.ObjectID{category},
+			Hosts:       []string{"youtube.com"},
+			Active:      &inactive,
+			CreatedFrom: createdFrom,
+			Statuses:    []string{models.DraftStatus},
+		}, dto.Sort{}, 1, 5)
+
+		assert.Nil(t, err)
+		filter := mt.GetStartedEvent().Command.Lookup("filter")
+		assert.False(t, filter.Document().Lookup("active").Boolean())
+		assert.Equal(t, category, filter.Document().Lookup("category_id", "$in", "0").ObjectID())
+		assert.Equal(t, "youtube.com", filter.Document().Lookup("url_host", "$in", "0").StringValue())
+		assert.Equal(t, models.DraftStatus, filter.Document().Lookup("status", "$in", "0").StringValue())
+		assert.Equal(t, dto.CreatedBound(createdFrom), filter.Document().Lookup("_id", "$gte").ObjectID())
+		_, err = filter.Document().LookupErr("_id", "$lt")
+		assert.Error(t, err)
+	})
+
+	mt.Run("GetFacets method Should count each facet in one aggregation without its own filter", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		category := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{
+			primitive.E{Key: "categorias", Value: bson.A{
+				bson.D{primitive.E{Key: "_id", Value: models.GetFreeCategory().ID}, primitive.E{Key: "count", Value: int32(1)}},
+				bson.D{primitive.E{Key: "_id", Value: category}, primitive.E{Key: "count", Value: int32(2)}},
+			}},
+			primitive.E{Key: "hosts", Value: bson.A{bson.D{primitive.E{Key: "_id", Value: "youtube.com"}, primitive.E{Key: "count", Value: int32(3)}}}},
+		}))
+
+		facets, err := videoService.GetFacets(dto.VideoFilter{
+			Search:      "go",
+			CategoryIDs: []primitive.ObjectID{category},
+			Hosts:       []string{"youtube.com"},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.FacetCount{{Value: category.Hex(), Count: 2}, {Value: models.GetFreeCategory().ID.Hex(), Count: 1}}, facets.Categories)
+		assert.Equal(t, []dto.FacetCount{{Value: "youtube.com", Count: 3}}, facets.Hosts)
+		pipeline := mt.GetStartedEvent().Command.Lookup("pipeline")
+		match := pipeline.Array().Index(0).Value().Document().Lookup("$match").Document()
+		assert.Equal(t, "go", match.Lookup("titulo_search", "$regex").StringValue())
+		_, err = match.LookupErr("category_id")
+		assert.Error(t, err)
+		facet := pipeline.Array().Index(1).Value().Document().Lookup("$facet").Document()
+		assert.Equal(t, "youtube.com", facet.Lookup("categorias", "0", "$match", "url_host", "$in", "0").StringValue())
+		assert.Equal(t, "$category_id", facet.Lookup("categorias", "1", "$group", "_id").StringValue())
+		assert.Equal(t, category, facet.Lookup("hosts", "0", "$match", "category_id", "$in", "0").ObjectID())
+		assert.Equal(t, "$url_host", facet.Lookup("hosts", "1", "$group", "_id").StringValue())
+	})
+
+	mt.Run("GetFacets method Should return error When the aggregation fails", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "failure"}))
+
+		facets, err := videoService.GetFacets(dto.VideoFilter{})
+
+		assert.Nil(t, facets)
+		assert.NotNil(t, err)
+	})
+
 	mt.Run("GetVideoByID method Should return object when object with id exists", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
@@ -149,30 +272,55 @@ func TestVideoService(t *testing.T) {
 		id := primitive.NewObjectID()
 		expectedCategory := mocked_data.GetValidCategoryWithId(id)
 
-		firstResponse := mtest.CreateSuccessResponse()
-		killCursors := mtest.CreateCursorResponse(0, "foo.bar", mtest.NextBatch)
-		mt.AddMockResponses(firstResponse, killCursors)
+		mt.AddMockResponses(activeCategory, mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
 
 		videoService.categoryService = &mocked_services.CategoryServiceMock{}
 		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
-			return nil
+			return models.GetFreeCategory()
 		}
 		mocked_services.CategoryServiceMockGetByID = func(id primitive.ObjectID) (*models.Category, error) {
 			return expectedCategory, nil
 		}
 
-		insertedVideo, err := videoService.Create(mocked_data.GetValidInsertVideoDto())
+		insertedVideo, err := videoService.Create(context.TODO(), mocked_data.GetValidInsertVideoDto())
 
 		assert.NotNil(t, insertedVideo)
 		assert.Nil(t, err)
 		mt.ClearMockResponses()
 	})
 
+	mt.Run("CreateVideo method Should stamp the audit fields with the token subject", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(activeCategory, mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
+
+		insertedVideo, err := videoService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertVideoDto())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|123", insertedVideo.CreatedBy)
+		assert.Equal(t, "auth0|123", insertedVideo.UpdatedBy)
+		assert.Equal(t, insertedVideo.CreatedAt, insertedVideo.UpdatedAt)
+		assert.Equal(t, CategoriesCollection, mt.GetStartedEvent().Command.Lookup("find").StringValue())
+		document := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		assert.Equal(t, "auth0|123", document.Lookup("created_by").StringValue())
+		assert.Equal(t, insertedVideo.CreatedAt, document.Lookup("created_at").Time().UTC())
+		entry := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		assert.Equal(t, models.CreateOperation, entry.Lookup("operation").StringValue())
+		assert.Equal(t, "auth0|123", entry.Lookup("actor").StringValue())
+		assert.Equal(t, insertedVideo.ID, entry.Lookup("resource_id").ObjectID())
+		mt.ClearMockResponses()
+	})
+
 	mt.Run("CreateVideo method Should return error when could not insert", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
 
-		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+		mt.AddMockResponses(activeCategory, mtest.CreateWriteErrorsResponse(mtest.WriteError{
 			Index:   1,
 			Code:    11000,
 			Message: "Con't insert data",
@@ -180,13 +328,10 @@ func TestVideoService(t *testing.T) {
 
 		videoService.categoryService = &mocked_services.CategoryServiceMock{}
 		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
-			return nil
-		}
-		mocked_services.CategoryServiceMockGetByID = func(id primitive.ObjectID) (*models.Category, error) {
-			return mocked_data.GetValidCategory(), nil
+			return models.GetFreeCategory()
 		}
 
-		insertedVideo, err := videoService.Create(dto.InsertVideo{})
+		insertedVideo, err := videoService.Create(context.TODO(), mocked_data.GetValidInsertVideoDto())
 		assert.Nil(t, insertedVideo)
 		assert.NotNil(t, err)
 		mt.ClearMockResponses()
@@ -200,26 +345,54 @@ func TestVideoService(t *testing.T) {
 
 		videoService.categoryService = &mocked_services.CategoryServiceMock{}
 		mocked_services.CategoryServiceMockGetByID = func(id primitive.ObjectID) (*models.Category, error) {
-			return nil, mongo.ErrNoDocuments
+			return nil, interfaces.ErrNotFound
 		}
+		videoData := mocked_data.GetValidInsertVideoDto()
+		videoData.CategoryID = primitive.NewObjectID()
 
-		insertedVideo, err := videoService.Create(dto.InsertVideo{})
+		insertedVideo, err := videoService.Create(context.TODO(), videoData)
 		assert.Nil(t, insertedVideo)
-		assert.NotNil(t, err)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: videoData.CategoryID}, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("CreateVideo method Should return CategoryNotFoundError When the category is deleted before the insert", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch))
+
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
+
+		insertedVideo, err := videoService.Create(context.TODO(), mocked_data.GetValidInsertVideoDto())
+		assert.Nil(t, insertedVideo)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: models.GetFreeCategory().ID}, err)
 		mt.ClearMockResponses()
 	})
 
 	mt.Run("UpdateVideo method Should update fields When object exists", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
 		id := primitive.NewObjectID()
 		videoData := mocked_data.GetValidInsertVideoDto()
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
-		})
-
-		_, err := videoService.Update(id, videoData)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			activeCategory,
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		_, err := videoService.Update(mocked_data.GetAdminContext(), id, videoData, 0)
 
 		assert.Nil(t, err)
 		mt.ClearMockResponses()
@@ -228,6 +401,10 @@ func TestVideoService(t *testing.T) {
 	mt.Run("UpdateVideo method Should return error When could not update object", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
 		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
 			Index:   1,
 			Code:    11000,
@@ -235,35 +412,431 @@ func TestVideoService(t *testing.T) {
 		}))
 		id := primitive.NewObjectID()
 
-		updateVideo, err := videoService.Update(id, dto.InsertVideo{})
+		updateVideo, err := videoService.Update(mocked_data.GetAdminContext(), id, mocked_data.GetValidInsertVideoDto(), 0)
 		assert.Nil(t, updateVideo)
 		assert.NotNil(t, err)
 		mt.ClearMockResponses()
 	})
 
+	mt.Run("UpdateVideo method Should return CategoryNotFoundError When category dont exist", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetByID = func(id primitive.ObjectID) (*models.Category, error) {
+			return nil, interfaces.ErrNotFound
+		}
+		videoData := mocked_data.GetValidInsertVideoDto()
+		videoData.CategoryID = primitive.NewObjectID()
+
+		updatedVideo, err := videoService.Update(mocked_data.GetAdminContext(), primitive.NewObjectID(), videoData, 0)
+		assert.Nil(t, updatedVideo)
+		assert.True(t, errors.Is(err, interfaces.ErrCategoryNotFound))
+	})
+
+	mt.Run("UpdateVideo method Should return validation error When fields are blank", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		videoData := mocked_data.GetValidInsertVideoDto()
+		videoData.Titulo = "   "
+
+		updatedVideo, err := videoService.Update(mocked_data.GetAdminContext(), primitive.NewObjectID(), videoData, 0)
+		assert.Nil(t, updatedVideo)
+		assert.Equal(t, dto.MissingFieldError("Titulo"), err)
+	})
+
+	mt.Run("PatchVideo method Should set only the fields in the patch When object exists", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		title := "Patched title"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), id, dto.PatchVideo{Titulo: &title}, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, id, response.ID)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		set := mt.GetStartedEvent().Command.Lookup("update", "$set").Document()
+		elements, _ := set.Elements()
+		assert.Equal(t, 4, len(elements))
+		assert.Equal(t, title, set.Lookup("titulo").StringValue())
+		assert.Equal(t, "patched title", set.Lookup("titulo_search").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("PatchVideo method Should match and bump the version When a version is expected", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		title := "Patched title"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		_, err := videoService.Patch(mocked_data.GetAdminContext(), id, dto.PatchVideo{Titulo: &title}, 3)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		command := mt.GetStartedEvent().Command
+		assert.Equal(t, int64(3), command.Lookup("query", "version").AsInt64())
+		assert.Equal(t, int64(1), command.Lookup("update", "$inc", "version").AsInt64())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("UpdateVideo method Should return ErrVersionMismatch When the expected version is stale", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			activeCategory,
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "value", Value: nil}},
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 1}}),
+		)
+
+		updatedVideo, err := videoService.Update(mocked_data.GetAdminContext(), id, mocked_data.GetValidInsertVideoDto(), 2)
+		assert.Nil(t, updatedVideo)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteVideo method Should return ErrVersionMismatch When the expected version is stale", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 0},
+			},
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "n", Value: 1}}),
+		)
+		err := videoService.Delete(mocked_data.GetAdminContext(), id, 2)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		mt.ClearMockResponses()
+	})
+
 	mt.Run("DeleteVideo method Should delete an item When the item can be deleted", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "acknowledged", Value: true},
-			primitive.E{Key: "n", Value: 1},
-		})
-		err := videoService.Delete(primitive.NewObjectID())
+		id := primitive.NewObjectID()
+		video := mocked_data.GetValidVideoWithId(id)
+		video.OwnerID = "auth0|123"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(video)),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 1},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+		err := videoService.Delete(mocked_data.GetContextWithSubject("auth0|123"), id, 0)
 		assert.Nil(t, err)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		set := mt.GetStartedEvent().Command.Lookup("updates", "0", "u", "$set")
+		assert.Equal(t, "auth0|123", set.Document().Lookup("updated_by").StringValue())
+		assert.Equal(t, set.Document().Lookup("deleted_at").Time(), set.Document().Lookup("updated_at").Time())
+		playlists := mt.GetStartedEvent().Command
+		assert.Equal(t, PlaylistCollection, playlists.Lookup("update").StringValue())
+		assert.Equal(t, id, playlists.Lookup("updates", "0", "q", "items.video_id", "$in").Array().Index(0).Value().ObjectID())
+		entry := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		assert.Equal(t, models.DeleteOperation, entry.Lookup("operation").StringValue())
+		change := entry.Lookup("changes", "0").Document()
+		assert.Equal(t, "active", change.Lookup("field").StringValue())
+		assert.True(t, change.Lookup("before").Boolean())
+		assert.False(t, change.Lookup("after").Boolean())
 		mt.ClearMockResponses()
 	})
 
-	mt.Run("DeleteVideo method Should return no document deleted error When document dont exists", func(mt *mtest.T) {
+	mt.Run("DeleteVideo method Should return ErrNotOwner When neither the owner nor an admin deletes the video", func(mt *mtest.T) {
 		var videoService = VideoService{}
 		videoService.videosCollection = mt.Coll
-		mt.AddMockResponses(bson.D{
-			primitive.E{Key: "ok", Value: 1},
-			primitive.E{Key: "acknowledged", Value: true},
-			primitive.E{Key: "n", Value: 0},
-		})
-		err := videoService.Delete(primitive.NewObjectID())
-		assert.NotNil(t, err)
+		id := primitive.NewObjectID()
+		video := mocked_data.GetValidVideoWithId(id)
+		video.OwnerID = "auth0|123"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(video)),
+			mtest.CreateSuccessResponse())
+		err := videoService.Delete(mocked_data.GetContextWithSubject("auth0|456"), id, 0)
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.NotEqual(t, "update", mt.GetStartedEvent().CommandName)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("DeleteVideo method Should return ErrNotFound When document dont exists", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+		err := videoService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetDeletedVideos method Should return objects When trash has objects", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		deleted := mocked_data.GetValidVideoWithId(primitive.NewObjectID())
+		deleted.Active = false
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(deleted)))
+
+		response, err := videoService.GetDeleted(1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.False(t, response[0].Active)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreVideo method Should return the restored object When object is in the trash", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		deleted := mocked_data.GetValidVideoWithId(id)
+		deleted.Active = false
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(deleted)),
+			activeCategory,
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := videoService.Restore(mocked_data.GetAdminContext(), id)
+		assert.Nil(t, err)
+		assert.Equal(t, id, response.ID)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreVideo method Should return error When object is not in the trash", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch))
+
+		response, err := videoService.Restore(mocked_data.GetAdminContext(), primitive.NewObjectID())
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreVideo method Should return CategoryNotFoundError When the category is not active", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		deleted := mocked_data.GetValidVideoWithId(id)
+		deleted.Active = false
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(deleted)),
+			mtest.CreateCursorResponse(0, "foo.categories", mtest.FirstBatch))
+
+		response, err := videoService.Restore(mocked_data.GetAdminContext(), id)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: deleted.CategoryID}, err)
+		assert.Nil(t, response)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		find := mt.GetStartedEvent().Command
+		assert.Equal(t, CategoriesCollection, find.Lookup("find").StringValue())
+		assert.True(t, find.Lookup("filter", "active").Boolean())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("PurgeVideos method Should return how many objects were removed along with their revisions", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "values", Value: bson.A{primitive.NewObjectID(), primitive.NewObjectID()}},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 3},
+			},
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "acknowledged", Value: true},
+				primitive.E{Key: "n", Value: 2},
+			})
+
+		purged, err := videoService.Purge(mocked_data.GetAdminContext(), time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), purged)
+		assert.Equal(t, "distinct", mt.GetStartedEvent().CommandName)
+		assert.Equal(t, RevisionCollection, mt.GetStartedEvent().Command.Lookup("delete").StringValue())
+		assert.Equal(t, "delete", mt.GetStartedEvent().CommandName)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("UpdateVideo method Should keep the replaced version as a revision", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			activeCategory,
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		_, err := videoService.Update(mocked_data.GetAdminContext(), id, mocked_data.GetValidInsertVideoDto(), 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.Equal(t, CategoriesCollection, mt.GetStartedEvent().Command.Lookup("find").StringValue())
+		assert.Equal(t, "findAndModify", mt.GetStartedEvent().CommandName)
+		insert := mt.GetStartedEvent().Command
+		assert.Equal(t, RevisionCollection, insert.Lookup("insert").StringValue())
+		kept := insert.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, models.VideoResource, kept.Lookup("resource").StringValue())
+		assert.Equal(t, id, kept.Lookup("resource_id").ObjectID())
+		assert.Equal(t, int64(1), kept.Lookup("revision").Int64())
+		assert.Equal(t, "unit test title", kept.Lookup("document", "titulo").StringValue())
+		assert.Equal(t, AuditCollection, mt.GetStartedEvent().Command.Lookup("insert").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("GetRevisions method Should return the revisions newest first", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.revisions", mtest.FirstBatch,
+			bson.D{
+				primitive.E{Key: "revision", Value: int64(2)},
+				primitive.E{Key: "document", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			bson.D{
+				primitive.E{Key: "revision", Value: int64(1)},
+				primitive.E{Key: "document", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			}))
+
+		revisions, err := videoService.GetRevisions(id)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(revisions))
+		assert.Equal(t, int64(2), revisions[0].Revision)
+		assert.Equal(t, id, revisions[0].Video.ID)
+		find := mt.GetStartedEvent().Command
+		assert.Equal(t, RevisionCollection, find.Lookup("find").StringValue())
+		assert.Equal(t, int32(-1), find.Lookup("sort", "revision").Int32())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreRevision method Should update the object back to the revision", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		videoService.categoryService = &mocked_services.CategoryServiceMock{}
+		mocked_services.CategoryServiceMockGetFreeCategory = func() *models.Category {
+			return models.GetFreeCategory()
+		}
+		id := primitive.NewObjectID()
+		snapshot := mocked_data.GetValidVideoWithId(id)
+		snapshot.Url = "https://www.unit-test.com"
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.revisions", mtest.FirstBatch, bson.D{
+				primitive.E{Key: "revision", Value: int64(1)},
+				primitive.E{Key: "document", Value: mocked_data.GetBsonFromVideo(snapshot)},
+			}),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			activeCategory,
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := videoService.RestoreRevision(mocked_data.GetAdminContext(), id, 1, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, id, response.ID)
+		assert.Equal(t, int64(1), mt.GetStartedEvent().Command.Lookup("filter", "revision").Int64())
+		assert.Equal(t, "find", mt.GetStartedEvent().CommandName)
+		assert.Equal(t, CategoriesCollection, mt.GetStartedEvent().Command.Lookup("find").StringValue())
+		set := mt.GetStartedEvent().Command.Lookup("update", "$set").Document()
+		assert.Equal(t, snapshot.Url, set.Lookup("url").StringValue())
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("RestoreRevision method Should return error When the revision dont exists", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "foo.revisions", mtest.FirstBatch))
+
+		response, err := videoService.RestoreRevision(mocked_data.GetAdminContext(), primitive.NewObjectID(), 1, 0)
+
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("PublishDue method Should publish the due videos on behalf of the scheduler", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		now := time.Now()
+		scheduled := mocked_data.GetValidVideoWithId(id)
+		scheduled.Status = models.ScheduledStatus
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "_id", Value: id}}),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(scheduled)),
+			bson.D{
+				primitive.E{Key: "ok", Value: 1},
+				primitive.E{Key: "value", Value: mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))},
+			},
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse())
+
+		response, err := videoService.PublishDue(domain.SystemContext(context.TODO(), domain.SchedulerActor), now)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, models.PublishedStatus, response[0].Status)
+		mt.ClearMockResponses()
+	})
+
+	mt.Run("PublishDue method Should skip a video changed since it was found due", func(mt *mtest.T) {
+		var videoService = VideoService{}
+		videoService.videosCollection = mt.Coll
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, bson.D{primitive.E{Key: "_id", Value: id}}),
+			mtest.CreateCursorResponse(0, "foo.bar", mtest.FirstBatch, mocked_data.GetBsonFromVideo(mocked_data.GetValidVideoWithId(id))),
+			bson.D{primitive.E{Key: "ok", Value: 1}, primitive.E{Key: "value", Value: nil}})
+
+		response, err := videoService.PublishDue(domain.SystemContext(context.TODO(), domain.SchedulerActor), time.Now())
+		assert.Nil(t, err)
+		assert.Empty(t, response)
 		mt.ClearMockResponses()
 	})
 }
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type APIKeyService struct {
+	database DatabaseService
+}
+
+func ProvideAPIKeyService(database DatabaseService) APIKeyService {
+	return APIKeyService{database}
+}
+
+func (ks *APIKeyService) GetAll() ([]models.APIKey, error) {
+	ks.database.mu.RLock()
+	defer ks.database.mu.RUnlock()
+
+	var keys []models.APIKey
+	for _, key := range ks.database.apiKeys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessObjectID(keys[j].ID, keys[i].ID) })
+	return keys, nil
+}
+
+func (ks *APIKeyService) GetByHash(hash string) (*models.APIKey, error) {
+	ks.database.mu.RLock()
+	defer ks.database.mu.RUnlock()
+
+	for _, key := range ks.database.apiKeys {
+		if key.Hash == hash {
+			return &key, nil
+		}
+	}
+	return nil, interfaces.ErrNotFound
+}
+
+func (ks *APIKeyService) Create(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	key.CreatedBy, key.CreatedAt = domain.Stamp(ctx)
+
+	ks.database.mu.Lock()
+	defer ks.database.mu.Unlock()
+	ks.database.apiKeys[key.ID] = key
+	return &key, nil
+}
+
+func (ks *APIKeyService) Revoke(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	author, revokedAt := domain.Stamp(ctx)
+
+	ks.database.mu.Lock()
+	defer ks.database.mu.Unlock()
+	key, ok := ks.database.apiKeys[id]
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	if key.RevokedAt == nil {
+		key.RevokedAt, key.RevokedBy = &revokedAt, author
+		ks.database.apiKeys[id] = key
+	}
+	return &key, nil
+}
+
+func (ks *APIKeyService) Touch(id primitive.ObjectID, usedAt time.Time) error {
+	ks.database.mu.Lock()
+	defer ks.database.mu.Unlock()
+	key, ok := ks.database.apiKeys[id]
+	if !ok {
+		return interfaces.ErrNotFound
+	}
+	usedAt = usedAt.UTC().Truncate(time.Millisecond)
+	key.LastUsedAt = &usedAt
+	ks.database.apiKeys[id] = key
+	return nil
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+type AuditService struct {
+	database DatabaseService
+}
+
+func ProvideAuditService(database DatabaseService) AuditService {
+	return AuditService{database}
+}
+
+func (as *AuditService) GetAll(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+	as.database.mu.RLock()
+	defer as.database.mu.RUnlock()
+
+	var entries []models.AuditEntry
+	for _, entry := range as.database.auditLog {
+		if filter.Matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.After(entries[j].Timestamp)
+		}
+		return lessObjectID(entries[j].ID, entries[i].ID)
+	})
+	start, end := paginate(len(entries), page, pageSize)
+	total := int64(len(entries))
+	if start == end {
+		return nil, total, nil
+	}
+	return entries[start:end], total, nil
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CategoryService struct {
+	database     DatabaseService
+	deletePolicy domain.CategoryDeletePolicy
+}
+
+func ProvideCategoryService(database DatabaseService) CategoryService {
+	return CategoryService{database, domain.GetCategoryDeletePolicy()}
+}
+
+func (cs *CategoryService) GetAll(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+	matches := makeTitleMatcher(filter)
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range cs.database.categories {
+		if category.Active && matches(category.TituloSearch) {
+			categories = append(categories, category)
+		}
+	}
+	sortCategories(categories, sort)
+	start, end := paginate(len(categories), page, pageSize)
+	total := int64(len(categories))
+	if start == end {
+		return nil, total, nil
+	}
+	return categories[start:end], total, nil
+}
+
+func (cs *CategoryService) GetAllAfter(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error) {
+	matches := makeTitleMatcher(filter)
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range cs.database.categories {
+		if category.Active && matches(category.TituloSearch) && isAfter(sort, sort.CategoryKey(category), category.ID, after) {
+			categories = append(categories, category)
+		}
+	}
+	sortCategories(categories, sort)
+	categories, next := dto.NextCategoryPage(categories, sort, limit)
+	return categories, next, nil
+}
+
+func (cs *CategoryService) GetById(id primitive.ObjectID) (*models.Category, error) {
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	category, ok := cs.database.categories[id]
+	if !ok || !category.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	return &category, nil
+}
+
+func (cs *CategoryService) Create(ctx context.Context, insertCategory dto.InsertCategory) (*models.Category, error) {
+	author, createdAt := domain.Stamp(ctx)
+	convertedCategory := insertCategory.ConvertToCategory()
+	convertedCategory.Audit = models.NewAudit(author, createdAt)
+	convertedCategory.OwnerID = author
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	cs.database.categories[convertedCategory.ID] = convertedCategory
+	cs.database.record(domain.NewAuditEntry(author, createdAt, models.CategoryResource, convertedCategory.ID,
+		models.CreateOperation, nil, convertedCategory))
+	return &convertedCategory, nil
+}
+
+func (cs *CategoryService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertCategory, version int64) (*models.Category, error) {
+	if id.IsZero() && newData.Titulo != models.GetFreeCategory().Titulo {
+		return nil, interfaces.ErrProtectedCategory
+	}
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	category, ok := cs.database.categories[id]
+	if !ok || !category.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, category.OwnerID); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		return nil, err
+	}
+	before := category
+	category.Titulo = newData.Titulo
+	category.TituloSearch = dto.NormalizeSearch(newData.Titulo)
+	category.Cor = newData.Cor
+	author, updatedAt := domain.Stamp(ctx)
+	category.Touch(author, updatedAt)
+	category.Version++
+	cs.database.categories[id] = category
+	cs.database.record(domain.NewAuditEntry(author, updatedAt, models.CategoryResource, id, models.UpdateOperation, before, category))
+	cs.database.keepCategoryRevision(before)
+	return &category, nil
+}
+
+// Patch changes only the fields present in the merge patch.
+func (cs *CategoryService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error) {
+	if err := domain.PrepareCategoryPatch(id, &patch); err != nil {
+		return nil, err
+	}
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	category, ok := cs.database.categories[id]
+	if !ok || !category.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, category.OwnerID); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		return nil, err
+	}
+	before := category
+	if patch.Titulo != nil {
+		category.Titulo = *patch.Titulo
+		category.TituloSearch = dto.NormalizeSearch(*patch.Titulo)
+	}
+	if patch.Cor != nil {
+		category.Cor = *patch.Cor
+	}
+	author, updatedAt := domain.Stamp(ctx)
+	category.Touch(author, updatedAt)
+	category.Version++
+	cs.database.categories[id] = category
+	cs.database.record(domain.NewAuditEntry(author, updatedAt, models.CategoryResource, id, models.UpdateOperation, before, category))
+	cs.database.keepCategoryRevision(before)
+	return &category, nil
+}
+
+// Delete soft deletes a category, handling its videos according to the
+// configured delete policy. The FREE category can never be deleted.
+func (cs *CategoryService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	if id.IsZero() {
+		return interfaces.ErrProtectedCategory
+	}
+	if cs.deletePolicy == domain.ReassignPolicy {
+		_ = cs.GetFreeCategory()
+	}
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	category, ok := cs.database.categories[id]
+	if !ok || !category.Active {
+		return interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, category.OwnerID); err != nil {
+		return err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		return err
+	}
+	var released []models.Video
+	for _, video := range cs.database.videos {
+		if video.CategoryID != id {
+			continue
+		}
+		switch cs.deletePolicy {
+		case domain.CascadePolicy:
+			if video.Active {
+				released = append(released, video)
+			}
+		case domain.ReassignPolicy:
+			released = append(released, video)
+		default:
+			if video.Active {
+				return interfaces.ErrCategoryHasVideos
+			}
+		}
+	}
+	if err := domain.AuthorizeVideos(ctx, released); err != nil {
+		return err
+	}
+	author, deletedAt := domain.Stamp(ctx)
+	for _, video := range released {
+		before, operation := video, models.UpdateOperation
+		if cs.deletePolicy == domain.CascadePolicy {
+			video.Active = false
+			video.DeletedAt = &deletedAt
+			operation = models.DeleteOperation
+			cs.database.removeFromPlaylists(author, deletedAt, video.ID)
+		} else {
+			video.CategoryID = models.GetFreeCategory().ID
+		}
+		video.Touch(author, deletedAt)
+		video.Version++
+		cs.database.videos[video.ID] = video
+		cs.database.record(domain.NewAuditEntry(author, deletedAt, models.VideoResource, video.ID, operation, before, video))
+	}
+	before := category
+	category.Active = false
+	category.DeletedAt = &deletedAt
+	category.Touch(author, deletedAt)
+	category.Version++
+	cs.database.categories[id] = category
+	cs.database.record(domain.NewAuditEntry(author, deletedAt, models.CategoryResource, id, models.DeleteOperation, before, category))
+	return nil
+}
+
+func (cs *CategoryService) GetVideosByCategoryId(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range cs.database.videos {
+		if video.Active && video.CategoryID == id && (!publishedOnly || video.Status == models.PublishedStatus) {
+			videos = append(videos, video)
+		}
+	}
+	sortVideos(videos, dto.Sort{})
+	return videos, nil
+}
+
+func (cs *CategoryService) GetVideosByCategoryIdAfter(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range cs.database.videos {
+		if video.Active && video.CategoryID == id && (!publishedOnly || video.Status == models.PublishedStatus) &&
+			isAfter(dto.Sort{}, "", video.ID, after) {
+			videos = append(videos, video)
+		}
+	}
+	sortVideos(videos, dto.Sort{})
+	videos, next := dto.NextVideoPage(videos, dto.Sort{}, limit)
+	return videos, next, nil
+}
+
+func (cs *CategoryService) GetFreeCategory() *models.Category {
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	for _, category := range cs.database.categories {
+		if category.Titulo == "FREE" {
+			return &category
+		}
+	}
+	category := *models.GetFreeCategory()
+	category.Audit = models.NewAudit(domain.Stamp(context.TODO()))
+	cs.database.categories[category.ID] = category
+	return &category
+}
+
+func (cs *CategoryService) GetDeleted(page int64, pageSize int64) ([]models.Category, error) {
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	var categories []models.Category
+	for _, category := range cs.database.categories {
+		if !category.Active {
+			categories = append(categories, category)
+		}
+	}
+	sort.Slice(categories, func(i, j int) bool { return lessDeletedAt(categories[i].DeletedAt, categories[j].DeletedAt) })
+	start, end := paginate(len(categories), page, pageSize)
+	if start == end {
+		return nil, nil
+	}
+	return categories[start:end], nil
+}
+
+func (cs *CategoryService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	category, ok := cs.database.categories[id]
+	if !ok || category.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, category.OwnerID); err != nil {
+		return nil, err
+	}
+	before := category
+	author, restoredAt := domain.Stamp(ctx)
+	category.Active = true
+	category.DeletedAt = nil
+	category.Touch(author, restoredAt)
+	category.Version++
+	cs.database.categories[id] = category
+	cs.database.record(domain.NewAuditEntry(author, restoredAt, models.CategoryResource, id, models.RestoreOperation, before, category))
+	return &category, nil
+}
+
+// Purge removes the expired categories of the trash ctx may purge that no
+// video references.
+func (cs *CategoryService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	owner, err := domain.TrashOwner(ctx)
+	if err != nil {
+		return 0, err
+	}
+	cs.database.mu.Lock()
+	defer cs.database.mu.Unlock()
+
+	referenced := map[primitive.ObjectID]bool{}
+	for _, video := range cs.database.videos {
+		referenced[video.CategoryID] = true
+	}
+	var purged int64
+	for id, category := range cs.database.categories {
+		if !category.Active && !referenced[id] && isExpired(category.DeletedAt, deletedBefore) && isInTrashOf(category.OwnerID, owner) {
+			delete(cs.database.categories, id)
+			delete(cs.database.categoryRevisions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (cs *CategoryService) GetRevisions(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+	cs.database.mu.RLock()
+	defer cs.database.mu.RUnlock()
+
+	kept := cs.database.categoryRevisions[id]
+	revisions := make([]models.CategoryRevision, 0, len(kept))
+	for i := len(kept) - 1; i >= 0; i-- {
+		revisions = append(revisions, kept[i])
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the category back to the fields it had at a revision,
+// with the same checks as any other update.
+func (cs *CategoryService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+	cs.database.mu.RLock()
+	var snapshot *models.Category
+	for _, kept := range cs.database.categoryRevisions[id] {
+		if kept.Revision == revision {
+			category := kept.Category
+			snapshot = &category
+		}
+	}
+	cs.database.mu.RUnlock()
+	if snapshot == nil {
+		return nil, interfaces.ErrNotFound
+	}
+	return cs.Update(ctx, id, dto.InsertCategoryFrom(*snapshot), version)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/storage_contract"
+)
+
+func provideTestServices(t *testing.T, policy domain.CategoryDeletePolicy) storage_contract.Services {
+	database := ProvideDatabaseService()
+	categoryService := ProvideCategoryService(database)
+	categoryService.deletePolicy = policy
+	videoService := ProvideVideoService(categoryService, database)
+	playlistService := ProvidePlaylistService(videoService, database)
+	apiKeyService := ProvideAPIKeyService(database)
+	auditService := ProvideAuditService(database)
+	searchService := ProvideSearchService(database)
+	userService := ProvideUserService(database)
+	return storage_contract.Services{
+		APIKeys:    &apiKeyService,
+		Audit:      &auditService,
+		Categories: &categoryService,
+		Playlists:  &playlistService,
+		Search:     &searchService,
+		Users:      &userService,
+		Videos:     &videoService,
+	}
+}
+
+func TestAPIKeyService(t *testing.T) {
+	storage_contract.TestAPIKeyService(t, provideTestServices)
+}
+
+func TestAuditService(t *testing.T) {
+	storage_contract.TestAuditService(t, provideTestServices)
+}
+
+func TestCategoryService(t *testing.T) {
+	storage_contract.TestCategoryService(t, provideTestServices)
+}
+
+func TestPlaylistService(t *testing.T) {
+	storage_contract.TestPlaylistService(t, provideTestServices)
+}
+
+func TestSearchService(t *testing.T) {
+	storage_contract.TestSearchService(t, provideTestServices)
+}
+
+func TestUserService(t *testing.T) {
+	storage_contract.TestUserService(t, provideTestServices)
+}
+
+func TestVideoService(t *testing.T) {
+	storage_contract.TestVideoService(t, provideTestServices)
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DatabaseService holds the in-memory collections shared by the services.
+// Copies share the same data, so it is passed by value like its Mongo
+// counterpart.
+type DatabaseService struct {
+	mu         *sync.RWMutex
+	videos     map[primitive.ObjectID]models.Video
+	categories map[primitive.ObjectID]models.Category
+	auditLog   map[primitive.ObjectID]models.AuditEntry
+	apiKeys    map[primitive.ObjectID]models.APIKey
+	users      map[string]models.User
+	playlists  map[primitive.ObjectID]models.Playlist
+
+	videoRevisions    map[primitive.ObjectID][]models.VideoRevision
+	categoryRevisions map[primitive.ObjectID][]models.CategoryRevision
+}
+
+func ProvideDatabaseService() DatabaseService {
+	return DatabaseService{
+		mu:         &sync.RWMutex{},
+		videos:     map[primitive.ObjectID]models.Video{},
+		categories: map[primitive.ObjectID]models.Category{},
+		auditLog:   map[primitive.ObjectID]models.AuditEntry{},
+		apiKeys:    map[primitive.ObjectID]models.APIKey{},
+		users:      map[string]models.User{},
+		playlists:  map[primitive.ObjectID]models.Playlist{},
+
+		videoRevisions:    map[primitive.ObjectID][]models.VideoRevision{},
+		categoryRevisions: map[primitive.ObjectID][]models.CategoryRevision{},
+	}
+}
+
+// record appends an entry to the audit log. The caller holds the write lock,
+// so the entry is recorded together with the write it describes.
+func (db DatabaseService) record(entry models.AuditEntry) {
+	db.auditLog[entry.ID] = entry
+}
+
+// keepVideoRevision keeps the version of a video an update replaces. The
+// caller holds the write lock.
+func (db DatabaseService) keepVideoRevision(video models.Video) {
+	db.videoRevisions[video.ID] = append(db.videoRevisions[video.ID], models.VideoRevision{Revision: video.Version, Video: video})
+}
+
+// keepCategoryRevision keeps the version of a category an update replaces.
+// The caller holds the write lock.
+func (db DatabaseService) keepCategoryRevision(category models.Category) {
+	db.categoryRevisions[category.ID] = append(db.categoryRevisions[category.ID],
+		models.CategoryRevision{Revision: category.Version, Category: category})
+}
+
+// checkCategory fails unless the category exists and is active. The caller
+// holds the write lock, so the category cannot be deleted before the video
+// referencing it is written.
+func (db DatabaseService) checkCategory(id primitive.ObjectID) error {
+	if category, ok := db.categories[id]; !ok || !category.Active {
+		return interfaces.CategoryNotFoundError{ID: id}
+	}
+	return nil
+}
+
+// checkVideo fails with ErrVideoNotFound unless the video is active. The
+// caller holds the write lock, so the video cannot be deleted before the
+// playlist holding it is written.
+func (db DatabaseService) checkVideo(id primitive.ObjectID) error {
+	if video, ok := db.videos[id]; !ok || !video.Active {
+		return interfaces.ErrVideoNotFound
+	}
+	return nil
+}
+
+// removeFromPlaylists takes the deleted videos out of every playlist holding
+// them. The caller holds the write lock, so the playlists change together
+// with the deletion.
+func (db DatabaseService) removeFromPlaylists(author string, deletedAt time.Time, ids ...primitive.ObjectID) {
+	for id, playlist := range db.playlists {
+		items := make([]models.PlaylistItem, 0, len(playlist.Items))
+		for _, item := range playlist.Items {
+			if !containsID(ids, item.VideoID) {
+				items = append(items, item)
+			}
+		}
+		if len(items) == len(playlist.Items) {
+			continue
+		}
+		playlist.Items = items
+		playlist.Touch(author, deletedAt)
+		playlist.Version++
+		db.playlists[id] = playlist
+	}
+}
+
+func containsID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// makeTitleMatcher mirrors the title filter applied by the bson services,
+// matching the folded search as plain text inside the folded title.
+func makeTitleMatcher(filter string) func(tituloSearch string) bool {
+	search := dto.NormalizeSearch(filter)
+	return func(tituloSearch string) bool { return strings.Contains(tituloSearch, search) }
+}
+
+// paginate returns the bounds of the requested page within a result set of
+// the given size, applying the same skip/limit semantics as makeFindOptions.
+func paginate(size int, page int64, pageSize int64) (int, int) {
+	start := (page - 1) * pageSize
+	if start < 0 {
+		start = 0
+	}
+	if start > int64(size) {
+		return size, size
+	}
+	end := int64(size)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+	}
+	return int(start), int(end)
+}
+
+// lessObjectID orders ids by creation time, which matches Mongo's natural order.
+func lessObjectID(a, b primitive.ObjectID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
+
+// lessSorted orders two documents by their sort key, then by id, both in the
+// direction of the sort.
+func lessSorted(order dto.Sort, aKey string, aID primitive.ObjectID, bKey string, bID primitive.ObjectID) bool {
+	if aKey != bKey {
+		return (aKey < bKey) != order.Descending
+	}
+	if aID == bID {
+		return false
+	}
+	return lessObjectID(aID, bID) != order.Descending
+}
+
+// isAfter reports whether a document belongs to the keyset page following the cursor.
+func isAfter(order dto.Sort, key string, id primitive.ObjectID, after *dto.Cursor) bool {
+	return after == nil || lessSorted(order, after.Value, after.ID, key, id)
+}
+
+// lessDeletedAt orders trashed documents from the most recently deleted.
+func lessDeletedAt(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return b == nil && a != nil
+	}
+	return a.After(*b)
+}
+
+// isPublic reports whether anonymous callers see a video: a published one of
+// the FREE category.
+func isPublic(video models.Video, freeCategoryID primitive.ObjectID) bool {
+	return video.CategoryID == freeCategoryID && video.Status == models.PublishedStatus
+}
+
+// checkVersion rejects a write when the caller expected another version. A
+// zero expected version skips the check.
+func checkVersion(stored int64, expected int64) error {
+	if expected != 0 && stored != expected {
+		return interfaces.ErrVersionMismatch
+	}
+	return nil
+}
+
+// isExpired reports whether a trashed document was deleted before the given time.
+func isExpired(deletedAt *time.Time, deletedBefore time.Time) bool {
+	return deletedAt != nil && deletedAt.Before(deletedBefore)
+}
+
+// isInTrashOf reports whether an item owned by ownerID is in the trash of
+// owner, where an empty owner stands for the trash of every user.
+func isInTrashOf(ownerID, owner string) bool {
+	return owner == "" || ownerID == owner
+}
+
+func sortVideos(videos []models.Video, order dto.Sort) {
+	sort.Slice(videos, func(i, j int) bool {
+		return lessSorted(order, order.VideoKey(videos[i]), videos[i].ID, order.VideoKey(videos[j]), videos[j].ID)
+	})
+}
+
+func sortCategories(categories []models.Category, order dto.Sort) {
+	sort.Slice(categories, func(i, j int) bool {
+		return lessSorted(order, order.CategoryKey(categories[i]), categories[i].ID, order.CategoryKey(categories[j]), categories[j].ID)
+	})
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PlaylistService struct {
+	videoService interfaces.IVideoService
+	database     DatabaseService
+}
+
+func ProvidePlaylistService(vs VideoService, database DatabaseService) PlaylistService {
+	return PlaylistService{&vs, database}
+}
+
+func (ps *PlaylistService) GetAll(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+	ps.database.mu.RLock()
+	defer ps.database.mu.RUnlock()
+
+	var playlists []models.Playlist
+	for _, playlist := range ps.database.playlists {
+		if filter.Matches(playlist) {
+			playlists = append(playlists, playlist)
+		}
+	}
+	sort.Slice(playlists, func(i, j int) bool { return lessObjectID(playlists[i].ID, playlists[j].ID) })
+	start, end := paginate(len(playlists), page, pageSize)
+	total := int64(len(playlists))
+	if start == end {
+		return nil, total, nil
+	}
+	return playlists[start:end], total, nil
+}
+
+func (ps *PlaylistService) GetByID(id primitive.ObjectID) (*models.Playlist, error) {
+	ps.database.mu.RLock()
+	defer ps.database.mu.RUnlock()
+
+	playlist, ok := ps.database.playlists[id]
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	return &playlist, nil
+}
+
+func (ps *PlaylistService) Create(ctx context.Context, insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&insertPlaylist); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
+	playlist := insertPlaylist.ConvertToPlaylist()
+	playlist.Audit = models.NewAudit(author, createdAt)
+	playlist.OwnerID = author
+
+	ps.database.mu.Lock()
+	defer ps.database.mu.Unlock()
+	ps.database.playlists[playlist.ID] = playlist
+	return &playlist, nil
+}
+
+func (ps *PlaylistService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&newData); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(playlist *models.Playlist, _ time.Time) error {
+		playlist.Titulo = newData.Titulo
+		playlist.Descricao = newData.Descricao
+		playlist.Visibility = newData.Visibility
+		return nil
+	})
+}
+
+// Delete removes the playlist for good, as only its owner has a use for it.
+func (ps *PlaylistService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	ps.database.mu.Lock()
+	defer ps.database.mu.Unlock()
+
+	playlist, ok := ps.database.playlists[id]
+	if !ok {
+		return interfaces.ErrNotFound
+	}
+	if err := domain.AuthorizePlaylist(ctx, playlist, version); err != nil {
+		return err
+	}
+	delete(ps.database.playlists, id)
+	return nil
+}
+
+func (ps *PlaylistService) AddVideo(ctx context.Context, id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylistItem(ps.videoService, item); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(playlist *models.Playlist, addedAt time.Time) (err error) {
+		if err = ps.database.checkVideo(item.VideoID); err != nil {
+			return err
+		}
+		playlist.Items, err = domain.AddPlaylistItem(playlist.Items, item, addedAt)
+		return err
+	})
+}
+
+func (ps *PlaylistService) RemoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.RemovePlaylistItem(playlist.Items, videoID)
+		return err
+	})
+}
+
+func (ps *PlaylistService) MoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.MovePlaylistItem(playlist.Items, videoID, position)
+		return err
+	})
+}
+
+// modify applies change to the playlist under the write lock.
+func (ps *PlaylistService) modify(ctx context.Context, id primitive.ObjectID, version int64,
+	change func(playlist *models.Playlist, at time.Time) error) (*models.Playlist, error) {
+	ps.database.mu.Lock()
+	defer ps.database.mu.Unlock()
+
+	playlist, ok := ps.database.playlists[id]
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.AuthorizePlaylist(ctx, playlist, version); err != nil {
+		return nil, err
+	}
+	author, updatedAt := domain.Stamp(ctx)
+	if err := change(&playlist, updatedAt); err != nil {
+		return nil, err
+	}
+	playlist.Touch(author, updatedAt)
+	playlist.Version++
+	ps.database.playlists[id] = playlist
+	return &playlist, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/search"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+type SearchService struct {
+	database DatabaseService
+}
+
+func ProvideSearchService(database DatabaseService) SearchService {
+	return SearchService{database}
+}
+
+// Search scores every active item in process.
+func (ss *SearchService) Search(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+	terms := search.Terms(query)
+	results := dto.SearchResults{Query: query, Videos: []dto.VideoHit{}, Categories: []dto.CategoryHit{}}
+	ss.database.mu.RLock()
+	defer ss.database.mu.RUnlock()
+
+	for _, video := range ss.database.videos {
+		if !video.Active || (publishedOnly && video.Status != models.PublishedStatus) {
+			continue
+		}
+		if hit, ok := search.MatchVideo(video, terms); ok {
+			results.Videos = append(results.Videos, hit)
+		}
+	}
+	for _, category := range ss.database.categories {
+		if !category.Active {
+			continue
+		}
+		if hit, ok := search.MatchCategory(category, terms); ok {
+			results.Categories = append(results.Categories, hit)
+		}
+	}
+	results.Videos = search.RankVideos(results.Videos, limit)
+	results.Categories = search.RankCategories(results.Categories, limit)
+	return &results, nil
+}
+
+// Suggest matches the prefix against the folded titles.
+func (ss *SearchService) Suggest(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+	folded := dto.NormalizeSearch(prefix)
+	freeCategory := models.GetFreeCategory()
+	var suggestions []dto.Suggestion
+	ss.database.mu.RLock()
+	defer ss.database.mu.RUnlock()
+
+	for _, video := range ss.database.videos {
+		if video.Active && strings.HasPrefix(video.TituloSearch, folded) && (!freeOnly || isPublic(video, freeCategory.ID)) &&
+			(!publishedOnly || video.Status == models.PublishedStatus) {
+			suggestions = append(suggestions, dto.Suggestion{ID: video.ID, Titulo: video.Titulo, Type: dto.VideoSuggestion})
+		}
+	}
+	if !freeOnly {
+		for _, category := range ss.database.categories {
+			if category.Active && strings.HasPrefix(category.TituloSearch, folded) {
+				suggestions = append(suggestions, dto.Suggestion{ID: category.ID, Titulo: category.Titulo, Type: dto.CategorySuggestion})
+			}
+		}
+	}
+	return search.SortSuggestions(suggestions, limit), nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+type UserService struct {
+	database DatabaseService
+}
+
+func ProvideUserService(database DatabaseService) UserService {
+	return UserService{database}
+}
+
+func (us *UserService) GetAll(page int64, pageSize int64) ([]models.User, int64, error) {
+	us.database.mu.RLock()
+	defer us.database.mu.RUnlock()
+
+	var users []models.User
+	for _, user := range us.database.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.After(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+	start, end := paginate(len(users), page, pageSize)
+	if start == end {
+		return nil, int64(len(users)), nil
+	}
+	return users[start:end], int64(len(users)), nil
+}
+
+func (us *UserService) GetByID(id string) (*models.User, error) {
+	us.database.mu.RLock()
+	defer us.database.mu.RUnlock()
+
+	user, ok := us.database.users[id]
+	if !ok {
+		return nil, interfaces.ErrNotFound
+	}
+	return &user, nil
+}
+
+func (us *UserService) Save(user models.User) (*models.User, error) {
+	user.CreatedAt = user.CreatedAt.UTC().Truncate(time.Millisecond)
+	user.LastSeenAt = user.LastSeenAt.UTC().Truncate(time.Millisecond)
+
+	us.database.mu.Lock()
+	defer us.database.mu.Unlock()
+	if existing, ok := us.database.users[user.ID]; ok {
+		user.CreatedAt = existing.CreatedAt
+	}
+	us.database.users[user.ID] = user
+	return &user, nil
+}
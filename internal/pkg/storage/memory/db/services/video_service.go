@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type VideoService struct {
+	categoryService interfaces.ICategoryService
+	database        DatabaseService
+}
+
+func ProvideVideoService(cs CategoryService, database DatabaseService) VideoService {
+	return VideoService{&cs, database}
+}
+
+func (vs *VideoService) GetAllFreeVideos() ([]models.Video, error) {
+	freeCategory := vs.categoryService.GetFreeCategory()
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range vs.database.videos {
+		if video.Active && isPublic(video, freeCategory.ID) {
+			videos = append(videos, video)
+		}
+	}
+	sortVideos(videos, dto.Sort{})
+	return videos, nil
+}
+
+func (vs *VideoService) GetAll(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range vs.database.videos {
+		if filter.Matches(video) {
+			videos = append(videos, video)
+		}
+	}
+	sortVideos(videos, sort)
+	start, end := paginate(len(videos), page, pageSize)
+	total := int64(len(videos))
+	if start == end {
+		return nil, total, nil
+	}
+	return videos[start:end], total, nil
+}
+
+func (vs *VideoService) GetAllAfter(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range vs.database.videos {
+		if filter.Matches(video) && isAfter(sort, sort.VideoKey(video), video.ID, after) {
+			videos = append(videos, video)
+		}
+	}
+	sortVideos(videos, sort)
+	videos, next := dto.NextVideoPage(videos, sort, limit)
+	return videos, next, nil
+}
+
+func (vs *VideoService) GetFacets(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	var categories, hosts []string
+	byCategory, byHost := filter.WithoutCategories(), filter.WithoutHosts()
+	for _, video := range vs.database.videos {
+		if byCategory.Matches(video) {
+			categories = append(categories, video.CategoryID.Hex())
+		}
+		if byHost.Matches(video) {
+			hosts = append(hosts, video.UrlHost)
+		}
+	}
+	return &dto.VideoFacets{Categories: dto.CountFacet(categories), Hosts: dto.CountFacet(hosts)}, nil
+}
+
+func (vs *VideoService) GetByID(id primitive.ObjectID) (*models.Video, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	video, ok := vs.database.videos[id]
+	if !ok || !video.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	return &video, nil
+}
+
+func (vs *VideoService) Create(ctx context.Context, model dto.InsertVideo) (*models.Video, error) {
+	if err := domain.PrepareVideo(vs.categoryService, &model); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
+	convertedVideo := model.ConvertToVideo()
+	convertedVideo.Audit = models.NewAudit(author, createdAt)
+	convertedVideo.OwnerID = author
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	if err := vs.database.checkCategory(convertedVideo.CategoryID); err != nil {
+		return nil, err
+	}
+	vs.database.videos[convertedVideo.ID] = convertedVideo
+	vs.database.record(domain.NewAuditEntry(author, createdAt, models.VideoResource, convertedVideo.ID,
+		models.CreateOperation, nil, convertedVideo))
+	return &convertedVideo, nil
+}
+
+func (vs *VideoService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoUpdate(vs.categoryService, &newData); err != nil {
+		return nil, err
+	}
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	video, ok := vs.database.videos[id]
+	if !ok || !video.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, video.OwnerID); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(video.Version, version); err != nil {
+		return nil, err
+	}
+	if err := vs.database.checkCategory(newData.CategoryID); err != nil {
+		return nil, err
+	}
+	before := video
+	video.Titulo = newData.Titulo
+	video.TituloSearch = dto.NormalizeSearch(newData.Titulo)
+	video.Descricao = newData.Descricao
+	video.Url = newData.Url
+	video.UrlHost = dto.URLHost(newData.Url)
+	video.CategoryID = newData.CategoryID
+	if newData.Status != "" {
+		video.Status = newData.Status
+		video.PublishAt = newData.PublishAt
+	}
+	author, updatedAt := domain.Stamp(ctx)
+	video.Touch(author, updatedAt)
+	video.Version++
+	vs.database.videos[id] = video
+	vs.database.record(domain.NewAuditEntry(author, updatedAt, models.VideoResource, id, models.UpdateOperation, before, video))
+	vs.database.keepVideoRevision(before)
+	return &video, nil
+}
+
+// Patch changes only the fields present in the merge patch.
+func (vs *VideoService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoPatch(vs.categoryService, &patch); err != nil {
+		return nil, err
+	}
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	video, ok := vs.database.videos[id]
+	if !ok || !video.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, video.OwnerID); err != nil {
+		return nil, err
+	}
+	if err := checkVersion(video.Version, version); err != nil {
+		return nil, err
+	}
+	if patch.CategoryID != nil {
+		if err := vs.database.checkCategory(*patch.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	before := video
+	if patch.Titulo != nil {
+		video.Titulo = *patch.Titulo
+		video.TituloSearch = dto.NormalizeSearch(*patch.Titulo)
+	}
+	if patch.Descricao != nil {
+		video.Descricao = *patch.Descricao
+	}
+	if patch.Url != nil {
+		video.Url = *patch.Url
+		video.UrlHost = dto.URLHost(*patch.Url)
+	}
+	if patch.CategoryID != nil {
+		video.CategoryID = *patch.CategoryID
+	}
+	if patch.Status != nil {
+		video.Status = *patch.Status
+		video.PublishAt = patch.PublishAt
+	}
+	author, updatedAt := domain.Stamp(ctx)
+	video.Touch(author, updatedAt)
+	video.Version++
+	vs.database.videos[id] = video
+	vs.database.record(domain.NewAuditEntry(author, updatedAt, models.VideoResource, id, models.UpdateOperation, before, video))
+	vs.database.keepVideoRevision(before)
+	return &video, nil
+}
+
+func (vs *VideoService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	video, ok := vs.database.videos[id]
+	if !ok || !video.Active {
+		return interfaces.ErrNotFound
+	}
+	if err := domain.Authorize(ctx, video.OwnerID); err != nil {
+		return err
+	}
+	if err := checkVersion(video.Version, version); err != nil {
+		return err
+	}
+	before := video
+	author, deletedAt := domain.Stamp(ctx)
+	video.Active = false
+	video.DeletedAt = &deletedAt
+	video.Touch(author, deletedAt)
+	video.Version++
+	vs.database.videos[id] = video
+	vs.database.removeFromPlaylists(author, deletedAt, id)
+	vs.database.record(domain.NewAuditEntry(author, deletedAt, models.VideoResource, id, models.DeleteOperation, before, video))
+	return nil
+}
+
+func (vs *VideoService) GetDeleted(page int64, pageSize int64) ([]models.Video, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	var videos []models.Video
+	for _, video := range vs.database.videos {
+		if !video.Active {
+			videos = append(videos, video)
+		}
+	}
+	sort.Slice(videos, func(i, j int) bool { return lessDeletedAt(videos[i].DeletedAt, videos[j].DeletedAt) })
+	start, end := paginate(len(videos), page, pageSize)
+	if start == end {
+		return nil, nil
+	}
+	return videos[start:end], nil
+}
+
+func (vs *VideoService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Video, error) {
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	video, ok := vs.database.videos[id]
+	if !ok || video.Active {
+		return nil, interfaces.ErrNotFound
+	}
+	if err := vs.database.checkCategory(video.CategoryID); err != nil {
+		return nil, err
+	}
+	if err := domain.Authorize(ctx, video.OwnerID); err != nil {
+		return nil, err
+	}
+	before := video
+	author, restoredAt := domain.Stamp(ctx)
+	video.Active = true
+	video.DeletedAt = nil
+	video.Touch(author, restoredAt)
+	video.Version++
+	vs.database.videos[id] = video
+	vs.database.record(domain.NewAuditEntry(author, restoredAt, models.VideoResource, id, models.RestoreOperation, before, video))
+	return &video, nil
+}
+
+// Purge removes the expired videos of the trash ctx may purge, with their
+// revisions.
+func (vs *VideoService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	owner, err := domain.TrashOwner(ctx)
+	if err != nil {
+		return 0, err
+	}
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	var purged int64
+	for id, video := range vs.database.videos {
+		if !video.Active && isExpired(video.DeletedAt, deletedBefore) && isInTrashOf(video.OwnerID, owner) {
+			delete(vs.database.videos, id)
+			delete(vs.database.videoRevisions, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (vs *VideoService) GetRevisions(id primitive.ObjectID) ([]models.VideoRevision, error) {
+	vs.database.mu.RLock()
+	defer vs.database.mu.RUnlock()
+
+	kept := vs.database.videoRevisions[id]
+	revisions := make([]models.VideoRevision, 0, len(kept))
+	for i := len(kept) - 1; i >= 0; i-- {
+		revisions = append(revisions, kept[i])
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the video back to the fields it had at a revision,
+// with the same checks as any other update.
+func (vs *VideoService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+	vs.database.mu.RLock()
+	var snapshot *models.Video
+	for _, kept := range vs.database.videoRevisions[id] {
+		if kept.Revision == revision {
+			video := kept.Video
+			snapshot = &video
+		}
+	}
+	vs.database.mu.RUnlock()
+	if snapshot == nil {
+		return nil, interfaces.ErrNotFound
+	}
+	return vs.Update(ctx, id, dto.InsertVideoFrom(*snapshot), version)
+}
+
+// PublishDue publishes the scheduled videos due by now.
+func (vs *VideoService) PublishDue(ctx context.Context, now time.Time) ([]models.Video, error) {
+	if err := domain.Authorize(ctx, ""); err != nil {
+		return nil, err
+	}
+	author, _ := domain.Stamp(ctx)
+	vs.database.mu.Lock()
+	defer vs.database.mu.Unlock()
+
+	var published []models.Video
+	for id, video := range vs.database.videos {
+		if !video.Active || video.Status != models.ScheduledStatus || video.PublishAt == nil || video.PublishAt.After(now) {
+			continue
+		}
+		before := video
+		publishedAt := now.UTC().Truncate(time.Millisecond)
+		video.Status, video.PublishAt = models.PublishedStatus, nil
+		video.Touch(author, publishedAt)
+		video.Version++
+		vs.database.videos[id] = video
+		vs.database.record(domain.NewAuditEntry(author, publishedAt, models.VideoResource, id,
+			models.PublishOperation, before, video))
+		published = append(published, video)
+	}
+	sortVideos(published, dto.Sort{})
+	return published, nil
+}
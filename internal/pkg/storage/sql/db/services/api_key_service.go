@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// apiKeyColumns are read by scanAPIKey. Scopes are stored space separated,
+// as in the scope claim of a token.
+const apiKeyColumns = "id, name, prefix, hash, scopes, expires_at, last_used_at, revoked_at, revoked_by, created_at, created_by"
+
+type APIKeyService struct {
+	database DatabaseService
+}
+
+func ProvideAPIKeyService(database DatabaseService) APIKeyService {
+	return APIKeyService{database}
+}
+
+func (ks *APIKeyService) GetAll() ([]models.APIKey, error) {
+	rows, err := ks.database.query("SELECT " + apiKeyColumns + " FROM api_keys ORDER BY id DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (ks *APIKeyService) GetByHash(hash string) (*models.APIKey, error) {
+	return ks.getOne("hash = ?", hash)
+}
+
+func (ks *APIKeyService) Create(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	key.CreatedBy, key.CreatedAt = domain.Stamp(ctx)
+	_, err := ks.database.exec("INSERT INTO api_keys ("+apiKeyColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		objectID(key.ID), key.Name, key.Prefix, key.Hash, strings.Join(key.Scopes, " "), key.ExpiresAt,
+		key.LastUsedAt, key.RevokedAt, key.RevokedBy, key.CreatedAt, key.CreatedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (ks *APIKeyService) Revoke(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	author, revokedAt := domain.Stamp(ctx)
+	if _, err := ks.database.exec("UPDATE api_keys SET revoked_at = ?, revoked_by = ? WHERE id = ? AND revoked_at IS NULL",
+		revokedAt, author, objectID(id)); err != nil {
+		return nil, err
+	}
+	return ks.getOne("id = ?", objectID(id))
+}
+
+func (ks *APIKeyService) Touch(id primitive.ObjectID, usedAt time.Time) error {
+	result, err := ks.database.exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?",
+		usedAt.UTC().Truncate(time.Millisecond), objectID(id))
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return interfaces.ErrNotFound
+	}
+	return nil
+}
+
+func (ks *APIKeyService) getOne(condition string, args ...interface{}) (*models.APIKey, error) {
+	key, err := scanAPIKey(ks.database.queryRow("SELECT "+apiKeyColumns+" FROM api_keys WHERE "+condition, args...))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &key, nil
+}
+
+func scanAPIKey(row scanner) (models.APIKey, error) {
+	key := models.APIKey{}
+	var scopes string
+	err := row.Scan((*objectID)(&key.ID), &key.Name, &key.Prefix, &key.Hash, &scopes, utcTime{&key.ExpiresAt},
+		nullTime{&key.LastUsedAt}, nullTime{&key.RevokedAt}, &key.RevokedBy, utcTime{&key.CreatedAt}, &key.CreatedBy)
+	key.Scopes = strings.Fields(scopes)
+	return key, err
+}
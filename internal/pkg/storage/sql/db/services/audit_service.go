@@ -0,0 +1,88 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+const auditColumns = "id, resource, resource_id, operation, actor, recorded_at, changes"
+
+type AuditService struct {
+	database DatabaseService
+}
+
+func ProvideAuditService(database DatabaseService) AuditService {
+	return AuditService{database}
+}
+
+func (as *AuditService) GetAll(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+	clauses, args := makeAuditFilterQuery(filter)
+	pagination, paginationArgs := makePagination(page, pageSize)
+	rows, err := as.database.query("SELECT "+auditColumns+" FROM audit_log"+clauses+" ORDER BY recorded_at DESC, id DESC"+pagination,
+		append(args, paginationArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var entries []models.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err = as.database.queryRow("SELECT COUNT(*) FROM audit_log"+clauses, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// makeAuditFilterQuery builds the WHERE clause shared by an audit log listing
+// and its count.
+func makeAuditFilterQuery(filter dto.AuditFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filter.Resource != "" {
+		conditions, args = append(conditions, "resource = ?"), append(args, filter.Resource)
+	}
+	if !filter.ResourceID.IsZero() {
+		conditions, args = append(conditions, "resource_id = ?"), append(args, objectID(filter.ResourceID))
+	}
+	if filter.Actor != "" {
+		conditions, args = append(conditions, "actor = ?"), append(args, filter.Actor)
+	}
+	if filter.Operation != "" {
+		conditions, args = append(conditions, "operation = ?"), append(args, filter.Operation)
+	}
+	if !filter.From.IsZero() {
+		conditions, args = append(conditions, "recorded_at >= ?"), append(args, filter.From.UTC())
+	}
+	if !filter.Before.IsZero() {
+		conditions, args = append(conditions, "recorded_at < ?"), append(args, filter.Before.UTC())
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func scanAuditEntry(row scanner) (models.AuditEntry, error) {
+	entry := models.AuditEntry{}
+	var changes string
+	err := row.Scan((*objectID)(&entry.ID), &entry.Resource, (*objectID)(&entry.ResourceID), &entry.Operation, &entry.Actor,
+		utcTime{&entry.Timestamp}, &changes)
+	if err != nil {
+		return entry, err
+	}
+	err = json.Unmarshal([]byte(changes), &entry.Changes)
+	return entry, err
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const categoryColumns = "id, titulo, cor, active, deleted_at, version, titulo_search," +
+	" created_at, updated_at, created_by, updated_by, owner_id"
+
+type CategoryService struct {
+	database     DatabaseService
+	deletePolicy domain.CategoryDeletePolicy
+}
+
+func ProvideCategoryService(database DatabaseService) CategoryService {
+	return CategoryService{database, domain.GetCategoryDeletePolicy()}
+}
+
+func (cs *CategoryService) GetAll(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+	clauses, args := makeFindQuery(filter, sort, page, pageSize)
+	categories, err := queryCategories(cs.database, "SELECT "+categoryColumns+" FROM categories"+clauses, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := cs.database.count("categories", filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return categories, total, nil
+}
+
+func (cs *CategoryService) GetAllAfter(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error) {
+	clauses, args := makeFilterQuery(filter)
+	clauses, args = makeCursorQuery(clauses, args, sort, after, limit)
+	categories, err := queryCategories(cs.database, "SELECT "+categoryColumns+" FROM categories"+clauses, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	categories, next := dto.NextCategoryPage(categories, sort, limit)
+	return categories, next, nil
+}
+
+func (cs *CategoryService) GetById(id primitive.ObjectID) (*models.Category, error) {
+	category, err := scanCategory(cs.database.queryRow("SELECT "+categoryColumns+" FROM categories WHERE id = ? AND active = TRUE", objectID(id)))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &category, nil
+}
+
+func (cs *CategoryService) Create(ctx context.Context, insertCategory dto.InsertCategory) (*models.Category, error) {
+	author, createdAt := domain.Stamp(ctx)
+	convertedCategory := insertCategory.ConvertToCategory()
+	convertedCategory.Audit = models.NewAudit(author, createdAt)
+	convertedCategory.OwnerID = author
+	err := cs.database.inTransaction(func(tx transaction) error {
+		if err := saveCategory(tx, convertedCategory); err != nil {
+			return err
+		}
+		return record(tx, domain.NewAuditEntry(author, createdAt, models.CategoryResource, convertedCategory.ID,
+			models.CreateOperation, nil, convertedCategory))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &convertedCategory, nil
+}
+
+func (cs *CategoryService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertCategory, version int64) (*models.Category, error) {
+	if id.IsZero() && newData.Titulo != models.GetFreeCategory().Titulo {
+		return nil, interfaces.ErrProtectedCategory
+	}
+	return cs.update(ctx, id, version,
+		[]string{"titulo = ?", "titulo_search = ?", "cor = ?"},
+		[]interface{}{newData.Titulo, dto.NormalizeSearch(newData.Titulo), newData.Cor})
+}
+
+// Patch changes only the fields present in the merge patch.
+func (cs *CategoryService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error) {
+	if err := domain.PrepareCategoryPatch(id, &patch); err != nil {
+		return nil, err
+	}
+	var columns []string
+	var args []interface{}
+	if patch.Titulo != nil {
+		columns, args = append(columns, "titulo = ?", "titulo_search = ?"), append(args, *patch.Titulo, dto.NormalizeSearch(*patch.Titulo))
+	}
+	if patch.Cor != nil {
+		columns, args = append(columns, "cor = ?"), append(args, *patch.Cor)
+	}
+	if len(columns) == 0 {
+		category, err := cs.GetById(id)
+		if err == nil && version != 0 && category.Version != version {
+			return nil, interfaces.ErrVersionMismatch
+		}
+		return category, err
+	}
+	return cs.update(ctx, id, version, columns, args)
+}
+
+func (cs *CategoryService) update(ctx context.Context, id primitive.ObjectID, version int64, columns []string, args []interface{}) (*models.Category, error) {
+	author, updatedAt := domain.Stamp(ctx)
+	columns, args = touched(author, updatedAt, columns, args)
+	return cs.write(ctx, id, true, models.UpdateOperation, author, updatedAt, func(tx transaction, before models.Category) error {
+		if err := updateVersioned(tx, "categories", id, version, columns, args); err != nil {
+			return err
+		}
+		return keepRevision(tx, models.CategoryResource, id, before.Version, before)
+	})
+}
+
+// Delete soft deletes a category, handling its videos according to the
+// configured delete policy. The FREE category can never be deleted.
+func (cs *CategoryService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	if id.IsZero() {
+		return interfaces.ErrProtectedCategory
+	}
+	if cs.deletePolicy == domain.ReassignPolicy && cs.GetFreeCategory() == nil {
+		return errors.New("could not load the free category")
+	}
+	author, deletedAt := domain.Stamp(ctx)
+	_, err := cs.write(ctx, id, true, models.DeleteOperation, author, deletedAt, func(tx transaction, _ models.Category) error {
+		// Anything but cascade or reassign, including an unset policy, rejects.
+		if cs.deletePolicy != domain.CascadePolicy && cs.deletePolicy != domain.ReassignPolicy {
+			var count int
+			if err := tx.queryRow("SELECT COUNT(*) FROM videos WHERE category_id = ? AND active = TRUE", objectID(id)).Scan(&count); err != nil {
+				return err
+			}
+			if count > 0 {
+				return interfaces.ErrCategoryHasVideos
+			}
+		}
+		if err := softDelete(tx, "categories", id, version, author, deletedAt); err != nil {
+			return err
+		}
+		return cs.releaseVideos(ctx, tx, id, author, deletedAt)
+	})
+	return err
+}
+
+// releaseVideos deletes or reassigns the videos of a category being deleted,
+// as the delete policy says, recording each video it changes. It fails
+// unless ctx may change every one of them.
+func (cs *CategoryService) releaseVideos(ctx context.Context, tx transaction, id primitive.ObjectID, author string, deletedAt time.Time) error {
+	var query, update string
+	var args []interface{}
+	operation := models.UpdateOperation
+	switch cs.deletePolicy {
+	case domain.CascadePolicy:
+		query = " WHERE category_id = ? AND active = TRUE"
+		update = "UPDATE videos SET active = FALSE, deleted_at = ?, updated_at = ?, updated_by = ?, version = version + 1" + query
+		args = []interface{}{deletedAt, deletedAt, author, objectID(id)}
+		operation = models.DeleteOperation
+	case domain.ReassignPolicy:
+		query = " WHERE category_id = ?"
+		update = "UPDATE videos SET category_id = ?, updated_at = ?, updated_by = ?, version = version + 1" + query
+		args = []interface{}{objectID(models.GetFreeCategory().ID), deletedAt, author, objectID(id)}
+	default:
+		return nil
+	}
+
+	videos, err := queryVideos(tx, "SELECT "+videoColumns+" FROM videos"+query, objectID(id))
+	if err != nil {
+		return err
+	}
+	if err = domain.AuthorizeVideos(ctx, videos); err != nil {
+		return err
+	}
+	if _, err = tx.exec(update, args...); err != nil {
+		return err
+	}
+	if operation == models.DeleteOperation {
+		ids := make([]primitive.ObjectID, len(videos))
+		for i, video := range videos {
+			ids[i] = video.ID
+		}
+		if err = removeFromPlaylists(tx, ids, author, deletedAt); err != nil {
+			return err
+		}
+	}
+	for _, before := range videos {
+		video := before
+		if operation == models.DeleteOperation {
+			video.Active = false
+			video.DeletedAt = &deletedAt
+		} else {
+			video.CategoryID = models.GetFreeCategory().ID
+		}
+		video.Touch(author, deletedAt)
+		video.Version++
+		if err = record(tx, domain.NewAuditEntry(author, deletedAt, models.VideoResource, video.ID, operation, before, video)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cs *CategoryService) GetVideosByCategoryId(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
+	where, args := categoryVideosQuery(id, publishedOnly)
+	return queryVideos(cs.database, "SELECT "+videoColumns+" FROM videos"+where+" ORDER BY id", args...)
+}
+
+func (cs *CategoryService) GetVideosByCategoryIdAfter(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	where, args := categoryVideosQuery(id, publishedOnly)
+	clauses, args := makeCursorQuery(where, args, dto.Sort{}, after, limit)
+	videos, err := queryVideos(cs.database, "SELECT "+videoColumns+" FROM videos"+clauses, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	videos, next := dto.NextVideoPage(videos, dto.Sort{}, limit)
+	return videos, next, nil
+}
+
+// categoryVideosQuery matches the active videos of the category, leaving out
+// the ones not published yet when publishedOnly is set.
+func categoryVideosQuery(id primitive.ObjectID, publishedOnly bool) (string, []interface{}) {
+	where, args := " WHERE category_id = ? AND active = TRUE", []interface{}{objectID(id)}
+	if publishedOnly {
+		where, args = where+" AND status = ?", append(args, models.PublishedStatus)
+	}
+	return where, args
+}
+
+func (cs *CategoryService) GetFreeCategory() *models.Category {
+	category, err := scanCategory(cs.database.queryRow("SELECT "+categoryColumns+" FROM categories WHERE titulo = ?", "FREE"))
+	if err != nil {
+		category = *models.GetFreeCategory()
+		category.Audit = models.NewAudit(domain.Stamp(context.TODO()))
+		if err := saveCategory(cs.database, category); err != nil {
+			return nil
+		}
+		return &category
+	}
+	return &category
+}
+
+func (cs *CategoryService) GetDeleted(page int64, pageSize int64) ([]models.Category, error) {
+	clauses, args := makeTrashQuery(page, pageSize)
+	return queryCategories(cs.database, "SELECT "+categoryColumns+" FROM categories"+clauses, args...)
+}
+
+func (cs *CategoryService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	author, restoredAt := domain.Stamp(ctx)
+	return cs.write(ctx, id, false, models.RestoreOperation, author, restoredAt, func(tx transaction, _ models.Category) error {
+		return restore(tx, "categories", id, author, restoredAt)
+	})
+}
+
+// Purge removes the expired categories of the trash ctx may purge that no
+// video references, with their revisions.
+func (cs *CategoryService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	expired, args, err := expiredClauses(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	expired += " AND NOT EXISTS (SELECT 1 FROM videos WHERE videos.category_id = categories.id)"
+	var purged int64
+	err = cs.database.inTransaction(func(tx transaction) error {
+		if _, err := tx.exec("DELETE FROM revisions WHERE resource = ? AND resource_id IN (SELECT id FROM categories"+expired+")",
+			append([]interface{}{models.CategoryResource}, args...)...); err != nil {
+			return err
+		}
+		result, err := tx.exec("DELETE FROM categories"+expired, args...)
+		if err != nil {
+			return err
+		}
+		purged, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+func (cs *CategoryService) GetRevisions(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+	revisions := []models.CategoryRevision{}
+	err := queryRevisions(cs.database, models.CategoryResource, id, func(revision int64, document []byte) error {
+		kept := models.CategoryRevision{Revision: revision}
+		if err := json.Unmarshal(document, &kept.Category); err != nil {
+			return err
+		}
+		revisions = append(revisions, kept)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the category back to the fields it had at a
+// revision, with the same checks as any other update.
+func (cs *CategoryService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+	var snapshot models.Category
+	if err := findRevision(cs.database, models.CategoryResource, id, revision, &snapshot); err != nil {
+		return nil, err
+	}
+	return cs.Update(ctx, id, dto.InsertCategoryFrom(snapshot), version)
+}
+
+// write runs fn in a transaction and records its change of the category in
+// the audit log.
+func (cs *CategoryService) write(ctx context.Context, id primitive.ObjectID, active bool, operation, author string, at time.Time,
+	fn func(tx transaction, before models.Category) error) (*models.Category, error) {
+	var category models.Category
+	err := cs.database.inTransaction(func(tx transaction) error {
+		before, err := scanCategory(tx.queryRow("SELECT "+categoryColumns+" FROM categories WHERE id = ? AND active = ?", objectID(id), active))
+		if err != nil {
+			return notFound(err)
+		}
+		if err = domain.Authorize(ctx, before.OwnerID); err != nil {
+			return err
+		}
+		if err = fn(tx, before); err != nil {
+			return err
+		}
+		if category, err = scanCategory(tx.queryRow("SELECT "+categoryColumns+" FROM categories WHERE id = ?", objectID(id))); err != nil {
+			return err
+		}
+		return record(tx, domain.NewAuditEntry(author, at, models.CategoryResource, id, operation, before, category))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func saveCategory(db executor, category models.Category) error {
+	_, err := db.exec("INSERT INTO categories ("+categoryColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		objectID(category.ID), category.Titulo, category.Cor, category.Active, category.DeletedAt, category.Version,
+		category.TituloSearch, category.CreatedAt, category.UpdatedAt, category.CreatedBy, category.UpdatedBy, category.OwnerID)
+	return err
+}
+
+func queryCategories(db executor, query string, args ...interface{}) ([]models.Category, error) {
+	rows, err := db.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func scanCategory(row scanner) (models.Category, error) {
+	category := models.Category{}
+	err := row.Scan((*objectID)(&category.ID), &category.Titulo, &category.Cor, &category.Active,
+		nullTime{&category.DeletedAt}, &category.Version, &category.TituloSearch,
+		utcTime{&category.CreatedAt}, utcTime{&category.UpdatedAt}, &category.CreatedBy, &category.UpdatedBy, &category.OwnerID)
+	return category, err
+}
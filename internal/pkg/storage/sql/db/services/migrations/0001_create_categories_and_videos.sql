@@ -0,0 +1,17 @@
+CREATE TABLE categories (
+    id     CHAR(24) PRIMARY KEY,
+    titulo TEXT     NOT NULL,
+    cor    TEXT     NOT NULL,
+    active BOOLEAN  NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE videos (
+    id          CHAR(24) PRIMARY KEY,
+    category_id CHAR(24) NOT NULL REFERENCES categories (id),
+    titulo      TEXT     NOT NULL,
+    descricao   TEXT     NOT NULL,
+    url         TEXT     NOT NULL,
+    active      BOOLEAN  NOT NULL DEFAULT TRUE
+);
+
+CREATE INDEX idx_videos_category_id ON videos (category_id);
@@ -0,0 +1,3 @@
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMP NULL;
+
+ALTER TABLE videos ADD COLUMN deleted_at TIMESTAMP NULL;
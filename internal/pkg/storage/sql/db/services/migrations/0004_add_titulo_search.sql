@@ -0,0 +1,3 @@
+ALTER TABLE categories ADD COLUMN titulo_search TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE videos ADD COLUMN titulo_search TEXT NOT NULL DEFAULT '';
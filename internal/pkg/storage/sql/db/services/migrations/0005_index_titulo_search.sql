@@ -0,0 +1,3 @@
+CREATE INDEX idx_categories_titulo_search ON categories (titulo_search);
+
+CREATE INDEX idx_videos_titulo_search ON videos (titulo_search);
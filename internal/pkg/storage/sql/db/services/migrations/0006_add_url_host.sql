@@ -0,0 +1,3 @@
+ALTER TABLE videos ADD COLUMN url_host TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_videos_url_host ON videos (url_host);
@@ -0,0 +1,12 @@
+ALTER TABLE categories ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE categories ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE categories ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE videos ADD COLUMN created_at TIMESTAMP NULL;
+ALTER TABLE videos ADD COLUMN updated_at TIMESTAMP NULL;
+ALTER TABLE videos ADD COLUMN created_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE videos ADD COLUMN updated_by TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_categories_updated_at ON categories (updated_at);
+CREATE INDEX idx_videos_updated_at ON videos (updated_at);
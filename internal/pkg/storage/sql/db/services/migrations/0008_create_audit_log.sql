@@ -0,0 +1,12 @@
+CREATE TABLE audit_log (
+    id          CHAR(24)  PRIMARY KEY,
+    resource    TEXT      NOT NULL,
+    resource_id CHAR(24)  NOT NULL,
+    operation   TEXT      NOT NULL,
+    actor       TEXT      NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    changes     TEXT      NOT NULL
+);
+
+CREATE INDEX idx_audit_log_resource ON audit_log (resource, resource_id, recorded_at);
+CREATE INDEX idx_audit_log_recorded_at ON audit_log (recorded_at);
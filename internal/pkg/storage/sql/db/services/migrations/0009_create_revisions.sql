@@ -0,0 +1,7 @@
+CREATE TABLE revisions (
+    resource    TEXT     NOT NULL,
+    resource_id CHAR(24) NOT NULL,
+    revision    BIGINT   NOT NULL,
+    document    TEXT     NOT NULL,
+    PRIMARY KEY (resource, resource_id, revision)
+);
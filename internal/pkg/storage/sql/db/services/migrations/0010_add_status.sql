@@ -0,0 +1,4 @@
+ALTER TABLE videos ADD COLUMN status TEXT NOT NULL DEFAULT 'published';
+ALTER TABLE videos ADD COLUMN publish_at TIMESTAMP NULL;
+
+CREATE INDEX idx_videos_status_publish_at ON videos (status, publish_at);
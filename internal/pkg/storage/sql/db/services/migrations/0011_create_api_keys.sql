@@ -0,0 +1,13 @@
+CREATE TABLE api_keys (
+    id           CHAR(24)  PRIMARY KEY,
+    name         TEXT      NOT NULL,
+    prefix       TEXT      NOT NULL,
+    hash         CHAR(64)  NOT NULL UNIQUE,
+    scopes       TEXT      NOT NULL,
+    expires_at   TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NULL,
+    revoked_at   TIMESTAMP NULL,
+    revoked_by   TEXT      NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    created_by   TEXT      NOT NULL
+);
@@ -0,0 +1,19 @@
+ALTER TABLE categories ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE videos ADD COLUMN owner_id TEXT NOT NULL DEFAULT '';
+
+UPDATE categories SET owner_id = created_by;
+UPDATE videos SET owner_id = created_by;
+
+CREATE INDEX idx_videos_owner_id ON videos (owner_id);
+
+CREATE TABLE users (
+    id           TEXT      PRIMARY KEY,
+    name         TEXT      NOT NULL DEFAULT '',
+    nickname     TEXT      NOT NULL DEFAULT '',
+    email        TEXT      NOT NULL DEFAULT '',
+    picture      TEXT      NOT NULL DEFAULT '',
+    created_at   TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_users_created_at ON users (created_at);
@@ -0,0 +1,24 @@
+CREATE TABLE playlists (
+    id         CHAR(24)  PRIMARY KEY,
+    titulo     TEXT      NOT NULL,
+    descricao  TEXT      NOT NULL,
+    visibility TEXT      NOT NULL,
+    version    BIGINT    NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    created_by TEXT      NOT NULL,
+    updated_by TEXT      NOT NULL,
+    owner_id   TEXT      NOT NULL
+);
+
+CREATE INDEX idx_playlists_owner_id ON playlists (owner_id);
+
+CREATE TABLE playlist_items (
+    playlist_id CHAR(24)  NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    video_id    CHAR(24)  NOT NULL REFERENCES videos (id),
+    position    INTEGER   NOT NULL,
+    added_at    TIMESTAMP NOT NULL,
+    PRIMARY KEY (playlist_id, video_id)
+);
+
+CREATE INDEX idx_playlist_items_video_id ON playlist_items (video_id);
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const playlistColumns = "id, titulo, descricao, visibility, version, created_at, updated_at, created_by, updated_by, owner_id"
+
+// errPlaylistChanged reports a playlist someone else changed between reading
+// and writing it, which is then read and written again.
+var errPlaylistChanged = errors.New("the playlist changed while being written")
+
+type PlaylistService struct {
+	videoService interfaces.IVideoService
+	database     DatabaseService
+}
+
+func ProvidePlaylistService(vs VideoService, database DatabaseService) PlaylistService {
+	return PlaylistService{&vs, database}
+}
+
+func (ps *PlaylistService) GetAll(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+	clauses, args := makePlaylistFilterQuery(filter)
+	var total int64
+	if err := ps.database.queryRow("SELECT COUNT(*) FROM playlists"+clauses, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	pagination, pageArgs := makePagination(page, pageSize)
+	rows, err := ps.database.query("SELECT "+playlistColumns+" FROM playlists"+clauses+" ORDER BY id"+pagination,
+		append(args, pageArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var playlists []models.Playlist
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		playlists = append(playlists, playlist)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	for i := range playlists {
+		if playlists[i].Items, err = queryPlaylistItems(ps.database, playlists[i].ID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return playlists, total, nil
+}
+
+// makePlaylistFilterQuery builds the WHERE clause shared by a playlist
+// listing and its count. A viewer matches the public playlists and their own.
+func makePlaylistFilterQuery(filter dto.PlaylistFilter) (string, []interface{}) {
+	clauses := " WHERE 1 = 1"
+	var args []interface{}
+	if filter.OwnerID != "" {
+		clauses += " AND owner_id = ?"
+		args = append(args, filter.OwnerID)
+	}
+	if filter.PublicOnly {
+		clauses += " AND visibility = ?"
+		args = append(args, models.PublicVisibility)
+	} else if filter.Viewer != "" {
+		clauses += " AND (visibility = ? OR owner_id = ?)"
+		args = append(args, models.PublicVisibility, filter.Viewer)
+	}
+	return clauses, args
+}
+
+func (ps *PlaylistService) GetByID(id primitive.ObjectID) (*models.Playlist, error) {
+	return getPlaylist(ps.database, id)
+}
+
+func (ps *PlaylistService) Create(ctx context.Context, insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&insertPlaylist); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
+	playlist := insertPlaylist.ConvertToPlaylist()
+	playlist.Audit = models.NewAudit(author, createdAt)
+	playlist.OwnerID = author
+	_, err := ps.database.exec("INSERT INTO playlists ("+playlistColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		objectID(playlist.ID), playlist.Titulo, playlist.Descricao, playlist.Visibility, playlist.Version,
+		playlist.CreatedAt, playlist.UpdatedAt, playlist.CreatedBy, playlist.UpdatedBy, playlist.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func (ps *PlaylistService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylist(&newData); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(_ transaction, playlist *models.Playlist, _ time.Time) error {
+		playlist.Titulo = newData.Titulo
+		playlist.Descricao = newData.Descricao
+		playlist.Visibility = newData.Visibility
+		return nil
+	})
+}
+
+// Delete removes the playlist and its items for good, as only its owner has
+// a use for them.
+func (ps *PlaylistService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	for {
+		err := ps.database.inTransaction(func(tx transaction) error {
+			playlist, err := getPlaylist(tx, id)
+			if err != nil {
+				return err
+			}
+			if err = domain.AuthorizePlaylist(ctx, *playlist, version); err != nil {
+				return err
+			}
+			if _, err = tx.exec("DELETE FROM playlist_items WHERE playlist_id = ?", objectID(id)); err != nil {
+				return err
+			}
+			result, err := tx.exec("DELETE FROM playlists WHERE id = ? AND version = ?", objectID(id), playlist.Version)
+			if err != nil {
+				return err
+			}
+			if deleted, _ := result.RowsAffected(); deleted == 0 {
+				return errPlaylistChanged
+			}
+			return nil
+		})
+		if err != errPlaylistChanged {
+			return err
+		}
+	}
+}
+
+func (ps *PlaylistService) AddVideo(ctx context.Context, id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+	if err := domain.PreparePlaylistItem(ps.videoService, item); err != nil {
+		return nil, err
+	}
+	return ps.modify(ctx, id, version, func(tx transaction, playlist *models.Playlist, addedAt time.Time) (err error) {
+		if err = checkVideo(tx, item.VideoID); err != nil {
+			return err
+		}
+		playlist.Items, err = domain.AddPlaylistItem(playlist.Items, item, addedAt)
+		return err
+	})
+}
+
+func (ps *PlaylistService) RemoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(_ transaction, playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.RemovePlaylistItem(playlist.Items, videoID)
+		return err
+	})
+}
+
+func (ps *PlaylistService) MoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error) {
+	return ps.modify(ctx, id, version, func(_ transaction, playlist *models.Playlist, _ time.Time) (err error) {
+		playlist.Items, err = domain.MovePlaylistItem(playlist.Items, videoID, position)
+		return err
+	})
+}
+
+// modify applies change to the playlist and writes it back, starting over
+// when someone else changed it meanwhile.
+func (ps *PlaylistService) modify(ctx context.Context, id primitive.ObjectID, version int64,
+	change func(tx transaction, playlist *models.Playlist, at time.Time) error) (*models.Playlist, error) {
+	author, updatedAt := domain.Stamp(ctx)
+	for {
+		var playlist *models.Playlist
+		err := ps.database.inTransaction(func(tx transaction) (err error) {
+			if playlist, err = getPlaylist(tx, id); err != nil {
+				return err
+			}
+			if err = domain.AuthorizePlaylist(ctx, *playlist, version); err != nil {
+				return err
+			}
+			read := playlist.Version
+			if err = change(tx, playlist, updatedAt); err != nil {
+				return err
+			}
+			playlist.Touch(author, updatedAt)
+			playlist.Version++
+			result, err := tx.exec("UPDATE playlists SET titulo = ?, descricao = ?, visibility = ?, updated_at = ?, updated_by = ?,"+
+				" version = version + 1 WHERE id = ? AND version = ?",
+				playlist.Titulo, playlist.Descricao, playlist.Visibility, playlist.UpdatedAt, playlist.UpdatedBy, objectID(id), read)
+			if err != nil {
+				return err
+			}
+			if updated, _ := result.RowsAffected(); updated == 0 {
+				return errPlaylistChanged
+			}
+			if _, err = tx.exec("DELETE FROM playlist_items WHERE playlist_id = ?", objectID(id)); err != nil {
+				return err
+			}
+			for i, item := range playlist.Items {
+				if _, err = tx.exec("INSERT INTO playlist_items (playlist_id, video_id, position, added_at) VALUES (?, ?, ?, ?)",
+					objectID(id), objectID(item.VideoID), i+1, item.AddedAt); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != errPlaylistChanged {
+			if err != nil {
+				return nil, err
+			}
+			return playlist, nil
+		}
+	}
+}
+
+// removeFromPlaylists takes the deleted videos out of every playlist holding
+// them, as part of the transaction deleting them. The positions left behind
+// keep the order of the remaining items.
+func removeFromPlaylists(db executor, ids []primitive.ObjectID, author string, deletedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	in := " WHERE video_id IN (?" + strings.Repeat(", ?", len(ids)-1) + ")"
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = objectID(id)
+	}
+	if _, err := db.exec("UPDATE playlists SET updated_at = ?, updated_by = ?, version = version + 1"+
+		" WHERE id IN (SELECT playlist_id FROM playlist_items"+in+")", append([]interface{}{deletedAt, author}, args...)...); err != nil {
+		return err
+	}
+	_, err := db.exec("DELETE FROM playlist_items"+in, args...)
+	return err
+}
+
+func getPlaylist(db executor, id primitive.ObjectID) (*models.Playlist, error) {
+	playlist, err := scanPlaylist(db.queryRow("SELECT "+playlistColumns+" FROM playlists WHERE id = ?", objectID(id)))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	if playlist.Items, err = queryPlaylistItems(db, id); err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+func queryPlaylistItems(db executor, id primitive.ObjectID) ([]models.PlaylistItem, error) {
+	rows, err := db.query("SELECT video_id, added_at FROM playlist_items WHERE playlist_id = ? ORDER BY position", objectID(id))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.PlaylistItem{}
+	for rows.Next() {
+		item := models.PlaylistItem{}
+		if err = rows.Scan((*objectID)(&item.VideoID), utcTime{&item.AddedAt}); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+func scanPlaylist(row scanner) (models.Playlist, error) {
+	playlist := models.Playlist{}
+	err := row.Scan((*objectID)(&playlist.ID), &playlist.Titulo, &playlist.Descricao, &playlist.Visibility, &playlist.Version,
+		utcTime{&playlist.CreatedAt}, utcTime{&playlist.UpdatedAt}, &playlist.CreatedBy, &playlist.UpdatedBy, &playlist.OwnerID)
+	return playlist, err
+}
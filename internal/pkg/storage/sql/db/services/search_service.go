@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/search"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+type SearchService struct {
+	database DatabaseService
+}
+
+func ProvideSearchService(database DatabaseService) SearchService {
+	return SearchService{database}
+}
+
+// Search scores every active row in process. Descriptions are not stored
+// folded, so the rows cannot be narrowed down in SQL without missing accented
+// matches.
+func (ss *SearchService) Search(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+	terms := search.Terms(query)
+	results := dto.SearchResults{Query: query, Videos: []dto.VideoHit{}, Categories: []dto.CategoryHit{}}
+
+	clauses, args := " WHERE active = TRUE", []interface{}{}
+	if publishedOnly {
+		clauses, args = clauses+" AND status = ?", append(args, models.PublishedStatus)
+	}
+	videos, err := queryVideos(ss.database, "SELECT "+videoColumns+" FROM videos"+clauses, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, video := range videos {
+		if hit, ok := search.MatchVideo(video, terms); ok {
+			results.Videos = append(results.Videos, hit)
+		}
+	}
+	categories, err := queryCategories(ss.database, "SELECT "+categoryColumns+" FROM categories WHERE active = TRUE")
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		if hit, ok := search.MatchCategory(category, terms); ok {
+			results.Categories = append(results.Categories, hit)
+		}
+	}
+	results.Videos = search.RankVideos(results.Videos, limit)
+	results.Categories = search.RankCategories(results.Categories, limit)
+	return &results, nil
+}
+
+// Suggest matches the prefix against the indexed titulo_search column.
+func (ss *SearchService) Suggest(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+	pattern := escapeLike(dto.NormalizeSearch(prefix)) + "%"
+	clauses := " WHERE active = TRUE AND titulo_search LIKE ? ESCAPE '\\'"
+	args := []interface{}{pattern}
+	if freeOnly {
+		clauses += " AND category_id = ?"
+		args = append(args, objectID(models.GetFreeCategory().ID))
+	}
+	if freeOnly || publishedOnly {
+		clauses += " AND status = ?"
+		args = append(args, models.PublishedStatus)
+	}
+	videos, err := queryVideos(ss.database, "SELECT "+videoColumns+" FROM videos"+clauses+" ORDER BY titulo_search LIMIT ?", append(args, limit)...)
+	if err != nil {
+		return nil, err
+	}
+	var suggestions []dto.Suggestion
+	for _, video := range videos {
+		suggestions = append(suggestions, dto.Suggestion{ID: video.ID, Titulo: video.Titulo, Type: dto.VideoSuggestion})
+	}
+	if !freeOnly {
+		categories, err := queryCategories(ss.database, "SELECT "+categoryColumns+" FROM categories"+
+			" WHERE active = TRUE AND titulo_search LIKE ? ESCAPE '\\' ORDER BY titulo_search LIMIT ?", pattern, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			suggestions = append(suggestions, dto.Suggestion{ID: category.ID, Titulo: category.Titulo, Type: dto.CategorySuggestion})
+		}
+	}
+	return search.SortSuggestions(suggestions, limit), nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+const userColumns = "id, name, nickname, email, picture, created_at, last_seen_at"
+
+type UserService struct {
+	database DatabaseService
+}
+
+func ProvideUserService(database DatabaseService) UserService {
+	return UserService{database}
+}
+
+func (us *UserService) GetAll(page int64, pageSize int64) ([]models.User, int64, error) {
+	var total int64
+	if err := us.database.queryRow("SELECT COUNT(*) FROM users").Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	pagination, args := makePagination(page, pageSize)
+	rows, err := us.database.query("SELECT "+userColumns+" FROM users ORDER BY created_at DESC, id"+pagination, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
+}
+
+func (us *UserService) GetByID(id string) (*models.User, error) {
+	user, err := scanUser(us.database.queryRow("SELECT "+userColumns+" FROM users WHERE id = ?", id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &user, nil
+}
+
+func (us *UserService) Save(user models.User) (*models.User, error) {
+	user.CreatedAt = user.CreatedAt.UTC().Truncate(time.Millisecond)
+	user.LastSeenAt = user.LastSeenAt.UTC().Truncate(time.Millisecond)
+	_, err := us.database.exec("INSERT INTO users ("+userColumns+") VALUES (?, ?, ?, ?, ?, ?, ?)"+
+		" ON CONFLICT (id) DO UPDATE SET name = excluded.name, nickname = excluded.nickname, email = excluded.email,"+
+		" picture = excluded.picture, last_seen_at = excluded.last_seen_at",
+		user.ID, user.Name, user.Nickname, user.Email, user.Picture, user.CreatedAt, user.LastSeenAt)
+	if err != nil {
+		return nil, err
+	}
+	return us.GetByID(user.ID)
+}
+
+func scanUser(row scanner) (models.User, error) {
+	user := models.User{}
+	err := row.Scan(&user.ID, &user.Name, &user.Nickname, &user.Email, &user.Picture,
+		utcTime{&user.CreatedAt}, utcTime{&user.LastSeenAt})
+	return user, err
+}
@@ -0,0 +1,365 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const videoColumns = "id, category_id, titulo, descricao, url, active, deleted_at, version, titulo_search, url_host," +
+	" created_at, updated_at, created_by, updated_by, status, publish_at, owner_id"
+
+type VideoService struct {
+	categoryService interfaces.ICategoryService
+	database        DatabaseService
+}
+
+func ProvideVideoService(cs CategoryService, database DatabaseService) VideoService {
+	return VideoService{&cs, database}
+}
+
+func (vs *VideoService) GetAllFreeVideos() ([]models.Video, error) {
+	freeCategory := vs.categoryService.GetFreeCategory()
+	if freeCategory == nil {
+		return nil, errors.New("could not load the free category")
+	}
+	return queryVideos(vs.database, "SELECT "+videoColumns+" FROM videos WHERE category_id = ? AND active = TRUE AND status = ? ORDER BY id",
+		objectID(freeCategory.ID), models.PublishedStatus)
+}
+
+func (vs *VideoService) GetAll(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+	clauses, args := makeVideoFilterQuery(filter)
+	pagination, paginationArgs := makePagination(page, pageSize)
+	videos, err := queryVideos(vs.database, "SELECT "+videoColumns+" FROM videos"+clauses+makeOrderBy(sort)+pagination,
+		append(args, paginationArgs...)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err = vs.database.queryRow("SELECT COUNT(*) FROM videos"+clauses, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return videos, total, nil
+}
+
+func (vs *VideoService) GetAllAfter(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	clauses, args := makeVideoFilterQuery(filter)
+	clauses, args = makeCursorQuery(clauses, args, sort, after, limit)
+	videos, err := queryVideos(vs.database, "SELECT "+videoColumns+" FROM videos"+clauses, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	videos, next := dto.NextVideoPage(videos, sort, limit)
+	return videos, next, nil
+}
+
+func (vs *VideoService) GetFacets(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+	categories, err := vs.countBy("category_id", filter.WithoutCategories())
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := vs.countBy("url_host", filter.WithoutHosts())
+	if err != nil {
+		return nil, err
+	}
+	return &dto.VideoFacets{Categories: categories, Hosts: hosts}, nil
+}
+
+// countBy counts the videos matching the filter per value of column, which
+// is one of the faceted columns and so safe to interpolate.
+func (vs *VideoService) countBy(column string, filter dto.VideoFilter) ([]dto.FacetCount, error) {
+	clauses, args := makeVideoFilterQuery(filter)
+	rows, err := vs.database.query("SELECT "+column+", COUNT(*) FROM videos"+clauses+" GROUP BY "+column, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	facet := []dto.FacetCount{}
+	for rows.Next() {
+		var count dto.FacetCount
+		if err = rows.Scan(&count.Value, &count.Count); err != nil {
+			return nil, err
+		}
+		facet = append(facet, count)
+	}
+	return dto.SortFacet(facet), rows.Err()
+}
+
+func (vs *VideoService) GetByID(id primitive.ObjectID) (*models.Video, error) {
+	video, err := scanVideo(vs.database.queryRow("SELECT "+videoColumns+" FROM videos WHERE id = ? AND active = TRUE", objectID(id)))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &video, nil
+}
+
+func (vs *VideoService) Create(ctx context.Context, model dto.InsertVideo) (*models.Video, error) {
+	if err := domain.PrepareVideo(vs.categoryService, &model); err != nil {
+		return nil, err
+	}
+	author, createdAt := domain.Stamp(ctx)
+	convertedVideo := model.ConvertToVideo()
+	convertedVideo.Audit = models.NewAudit(author, createdAt)
+	convertedVideo.OwnerID = author
+	err := vs.database.inTransaction(func(tx transaction) error {
+		if err := checkCategory(tx, convertedVideo.CategoryID); err != nil {
+			return err
+		}
+		if _, err := tx.exec("INSERT INTO videos ("+videoColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			objectID(convertedVideo.ID), objectID(convertedVideo.CategoryID), convertedVideo.Titulo,
+			convertedVideo.Descricao, convertedVideo.Url, convertedVideo.Active, convertedVideo.DeletedAt, convertedVideo.Version,
+			convertedVideo.TituloSearch, convertedVideo.UrlHost,
+			convertedVideo.CreatedAt, convertedVideo.UpdatedAt, convertedVideo.CreatedBy, convertedVideo.UpdatedBy,
+			convertedVideo.Status, convertedVideo.PublishAt, convertedVideo.OwnerID); err != nil {
+			return err
+		}
+		return record(tx, domain.NewAuditEntry(author, createdAt, models.VideoResource, convertedVideo.ID,
+			models.CreateOperation, nil, convertedVideo))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &convertedVideo, nil
+}
+
+func (vs *VideoService) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoUpdate(vs.categoryService, &newData); err != nil {
+		return nil, err
+	}
+	columns := []string{"titulo = ?", "titulo_search = ?", "descricao = ?", "url = ?", "url_host = ?", "category_id = ?"}
+	args := []interface{}{newData.Titulo, dto.NormalizeSearch(newData.Titulo), newData.Descricao, newData.Url, dto.URLHost(newData.Url),
+		objectID(newData.CategoryID)}
+	if newData.Status != "" {
+		columns, args = append(columns, "status = ?", "publish_at = ?"), append(args, newData.Status, newData.PublishAt)
+	}
+	return vs.update(ctx, id, version, &newData.CategoryID, columns, args)
+}
+
+// Patch changes only the fields present in the merge patch.
+func (vs *VideoService) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+	if err := domain.PrepareVideoPatch(vs.categoryService, &patch); err != nil {
+		return nil, err
+	}
+	var columns []string
+	var args []interface{}
+	if patch.Titulo != nil {
+		columns, args = append(columns, "titulo = ?", "titulo_search = ?"), append(args, *patch.Titulo, dto.NormalizeSearch(*patch.Titulo))
+	}
+	if patch.Descricao != nil {
+		columns, args = append(columns, "descricao = ?"), append(args, *patch.Descricao)
+	}
+	if patch.Url != nil {
+		columns, args = append(columns, "url = ?", "url_host = ?"), append(args, *patch.Url, dto.URLHost(*patch.Url))
+	}
+	if patch.CategoryID != nil {
+		columns, args = append(columns, "category_id = ?"), append(args, objectID(*patch.CategoryID))
+	}
+	if patch.Status != nil {
+		columns, args = append(columns, "status = ?", "publish_at = ?"), append(args, *patch.Status, patch.PublishAt)
+	}
+	if len(columns) == 0 {
+		video, err := vs.GetByID(id)
+		if err == nil && version != 0 && video.Version != version {
+			return nil, interfaces.ErrVersionMismatch
+		}
+		return video, err
+	}
+	return vs.update(ctx, id, version, patch.CategoryID, columns, args)
+}
+
+// update sets the given columns on an active video, checking the category
+// it moves the video to, if any.
+func (vs *VideoService) update(ctx context.Context, id primitive.ObjectID, version int64, categoryID *primitive.ObjectID,
+	columns []string, args []interface{}) (*models.Video, error) {
+	author, updatedAt := domain.Stamp(ctx)
+	columns, args = touched(author, updatedAt, columns, args)
+	return vs.write(ctx, id, true, models.UpdateOperation, author, updatedAt, func(tx transaction, before models.Video) error {
+		if categoryID != nil {
+			if err := checkCategory(tx, *categoryID); err != nil {
+				return err
+			}
+		}
+		if err := updateVersioned(tx, "videos", id, version, columns, args); err != nil {
+			return err
+		}
+		return keepRevision(tx, models.VideoResource, id, before.Version, before)
+	})
+}
+
+func (vs *VideoService) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	author, deletedAt := domain.Stamp(ctx)
+	_, err := vs.write(ctx, id, true, models.DeleteOperation, author, deletedAt, func(tx transaction, _ models.Video) error {
+		if err := softDelete(tx, "videos", id, version, author, deletedAt); err != nil {
+			return err
+		}
+		return removeFromPlaylists(tx, []primitive.ObjectID{id}, author, deletedAt)
+	})
+	return err
+}
+
+func (vs *VideoService) GetDeleted(page int64, pageSize int64) ([]models.Video, error) {
+	clauses, args := makeTrashQuery(page, pageSize)
+	return queryVideos(vs.database, "SELECT "+videoColumns+" FROM videos"+clauses, args...)
+}
+
+func (vs *VideoService) Restore(ctx context.Context, id primitive.ObjectID) (*models.Video, error) {
+	author, restoredAt := domain.Stamp(ctx)
+	return vs.write(ctx, id, false, models.RestoreOperation, author, restoredAt, func(tx transaction, before models.Video) error {
+		if err := checkCategory(tx, before.CategoryID); err != nil {
+			return err
+		}
+		return restore(tx, "videos", id, author, restoredAt)
+	})
+}
+
+// Purge removes the expired videos of the trash ctx may purge, with their
+// revisions.
+func (vs *VideoService) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	expired, args, err := expiredClauses(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	var purged int64
+	err = vs.database.inTransaction(func(tx transaction) error {
+		if _, err := tx.exec("DELETE FROM revisions WHERE resource = ? AND resource_id IN (SELECT id FROM videos"+expired+")",
+			append([]interface{}{models.VideoResource}, args...)...); err != nil {
+			return err
+		}
+		result, err := tx.exec("DELETE FROM videos"+expired, args...)
+		if err != nil {
+			return err
+		}
+		purged, err = result.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+func (vs *VideoService) GetRevisions(id primitive.ObjectID) ([]models.VideoRevision, error) {
+	revisions := []models.VideoRevision{}
+	err := queryRevisions(vs.database, models.VideoResource, id, func(revision int64, document []byte) error {
+		kept := models.VideoRevision{Revision: revision}
+		if err := json.Unmarshal(document, &kept.Video); err != nil {
+			return err
+		}
+		revisions = append(revisions, kept)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// RestoreRevision updates the video back to the fields it had at a revision,
+// with the same checks as any other update.
+func (vs *VideoService) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+	var snapshot models.Video
+	if err := findRevision(vs.database, models.VideoResource, id, revision, &snapshot); err != nil {
+		return nil, err
+	}
+	return vs.Update(ctx, id, dto.InsertVideoFrom(snapshot), version)
+}
+
+// PublishDue publishes the scheduled videos due by now, leaving a video
+// changed since it was selected for the next run.
+func (vs *VideoService) PublishDue(ctx context.Context, now time.Time) ([]models.Video, error) {
+	if err := domain.Authorize(ctx, ""); err != nil {
+		return nil, err
+	}
+	author, _ := domain.Stamp(ctx)
+	due, err := queryVideos(vs.database, "SELECT "+videoColumns+" FROM videos"+
+		" WHERE active = TRUE AND status = ? AND publish_at <= ? ORDER BY id", models.ScheduledStatus, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	publishedAt := now.UTC().Truncate(time.Millisecond)
+	var published []models.Video
+	for _, video := range due {
+		id := video.ID
+		updated, err := vs.write(ctx, id, true, models.PublishOperation, author, publishedAt, func(tx transaction, _ models.Video) error {
+			result, err := tx.exec("UPDATE videos SET status = ?, publish_at = NULL, updated_at = ?, updated_by = ?, version = version + 1"+
+				" WHERE id = ? AND status = ?", models.PublishedStatus, publishedAt, author, objectID(id), models.ScheduledStatus)
+			if err != nil {
+				return err
+			}
+			affected, err := result.RowsAffected()
+			if err == nil && affected == 0 {
+				return interfaces.ErrNotFound
+			}
+			return err
+		})
+		if err == interfaces.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return published, err
+		}
+		published = append(published, *updated)
+	}
+	return published, nil
+}
+
+// write runs fn in a transaction and records its change of the video in the
+// audit log.
+func (vs *VideoService) write(ctx context.Context, id primitive.ObjectID, active bool, operation, author string, at time.Time,
+	fn func(tx transaction, before models.Video) error) (*models.Video, error) {
+	var video models.Video
+	err := vs.database.inTransaction(func(tx transaction) error {
+		before, err := scanVideo(tx.queryRow("SELECT "+videoColumns+" FROM videos WHERE id = ? AND active = ?", objectID(id), active))
+		if err != nil {
+			return notFound(err)
+		}
+		if err = domain.Authorize(ctx, before.OwnerID); err != nil {
+			return err
+		}
+		if err = fn(tx, before); err != nil {
+			return err
+		}
+		if video, err = scanVideo(tx.queryRow("SELECT "+videoColumns+" FROM videos WHERE id = ?", objectID(id))); err != nil {
+			return err
+		}
+		return record(tx, domain.NewAuditEntry(author, at, models.VideoResource, id, operation, before, video))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &video, nil
+}
+
+func queryVideos(db executor, query string, args ...interface{}) ([]models.Video, error) {
+	rows, err := db.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var videos []models.Video
+	for rows.Next() {
+		video, err := scanVideo(rows)
+		if err != nil {
+			return nil, err
+		}
+		videos = append(videos, video)
+	}
+	return videos, rows.Err()
+}
+
+func scanVideo(row scanner) (models.Video, error) {
+	video := models.Video{}
+	err := row.Scan((*objectID)(&video.ID), (*objectID)(&video.CategoryID), &video.Titulo,
+		&video.Descricao, &video.Url, &video.Active, nullTime{&video.DeletedAt}, &video.Version, &video.TituloSearch, &video.UrlHost,
+		utcTime{&video.CreatedAt}, utcTime{&video.UpdatedAt}, &video.CreatedBy, &video.UpdatedBy, &video.Status, nullTime{&video.PublishAt}, &video.OwnerID)
+	return video, err
+}
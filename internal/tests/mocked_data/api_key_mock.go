@@ -0,0 +1,35 @@
+package mocked_data
+
+import (
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/auth/apikey"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ValidAPIKeySecret is the secret of the key GetValidAPIKey returns.
+const ValidAPIKeySecret = "afk_unitTestSecretOfTheValidKey"
+
+func GetValidAPIKey() models.APIKey {
+	return models.APIKey{
+		ID:        primitive.NewObjectID(),
+		Name:      "Unit test key",
+		Prefix:    apikey.Prefix(ValidAPIKeySecret),
+		Hash:      apikey.Hash(ValidAPIKeySecret),
+		Scopes:    []string{"read:videos", "read:categories"},
+		ExpiresAt: time.Now().UTC().Add(time.Hour).Truncate(time.Millisecond),
+	}
+}
+
+func GetBsonFromAPIKey(model models.APIKey) bson.D {
+	return bson.D{
+		primitive.E{Key: "_id", Value: model.ID},
+		primitive.E{Key: "name", Value: model.Name},
+		primitive.E{Key: "prefix", Value: model.Prefix},
+		primitive.E{Key: "hash", Value: model.Hash},
+		primitive.E{Key: "scopes", Value: model.Scopes},
+		primitive.E{Key: "expires_at", Value: model.ExpiresAt},
+	}
+}
@@ -13,10 +13,11 @@ func GetValidCategory() *models.Category {
 
 func GetValidCategoryWithId(id primitive.ObjectID) *models.Category {
 	return &models.Category{
-		ID:     id,
-		Titulo: "unit test title",
-		Cor:    "blue",
-		Active: true,
+		ID:      id,
+		Titulo:  "unit test title",
+		Cor:     "blue",
+		Active:  true,
+		Version: 1,
 	}
 }
 
@@ -26,6 +27,7 @@ func GetBsonFromCategory(model *models.Category) bson.D {
 		primitive.E{Key: "titulo", Value: model.Titulo},
 		primitive.E{Key: "cor", Value: model.Cor},
 		primitive.E{Key: "active", Value: model.Active},
+		primitive.E{Key: "version", Value: model.Version},
 	}
 }
 
@@ -0,0 +1,30 @@
+package mocked_data
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+
+	"github.com/form3tech-oss/jwt-go"
+)
+
+// GetContextWithSubject returns the context of a request carrying a token
+// validated for the given subject.
+func GetContextWithSubject(subject string) context.Context {
+	return context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{"sub": subject}})
+}
+
+// GetContextWithPermissions returns the context of a request carrying a token
+// validated for the given subject and granting the permissions.
+func GetContextWithPermissions(subject string, permissions ...string) context.Context {
+	return context.WithValue(context.TODO(), "user", &jwt.Token{Claims: jwt.MapClaims{
+		"sub": subject, "scope": strings.Join(permissions, " "),
+	}})
+}
+
+// GetAdminContext returns the context of a request carrying a token without
+// a subject that may change every resource.
+func GetAdminContext() context.Context {
+	return GetContextWithPermissions("", domain.AdminPermission)
+}
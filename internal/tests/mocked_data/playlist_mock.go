@@ -0,0 +1,47 @@
+package mocked_data
+
+import (
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetValidPlaylistWithId returns a private playlist of the user "auth0|123"
+// holding the given videos in order.
+func GetValidPlaylistWithId(id primitive.ObjectID, videoIDs ...primitive.ObjectID) *models.Playlist {
+	addedAt := time.Now().UTC().Truncate(time.Millisecond)
+	items := []models.PlaylistItem{}
+	for _, videoID := range videoIDs {
+		items = append(items, models.PlaylistItem{VideoID: videoID, AddedAt: addedAt})
+	}
+	return &models.Playlist{
+		ID:         id,
+		Titulo:     "unit test playlist",
+		Descricao:  "unit test description",
+		Visibility: models.PrivateVisibility,
+		Items:      items,
+		Version:    1,
+		Ownership:  models.Ownership{OwnerID: "auth0|123"},
+	}
+}
+
+func GetBsonFromPlaylist(model *models.Playlist) bson.D {
+	items := bson.A{}
+	for _, item := range model.Items {
+		items = append(items, bson.D{
+			primitive.E{Key: "video_id", Value: item.VideoID},
+			primitive.E{Key: "added_at", Value: item.AddedAt},
+		})
+	}
+	return bson.D{
+		primitive.E{Key: "_id", Value: model.ID},
+		primitive.E{Key: "titulo", Value: model.Titulo},
+		primitive.E{Key: "descricao", Value: model.Descricao},
+		primitive.E{Key: "visibility", Value: model.Visibility},
+		primitive.E{Key: "items", Value: items},
+		primitive.E{Key: "version", Value: model.Version},
+		primitive.E{Key: "owner_id", Value: model.OwnerID},
+	}
+}
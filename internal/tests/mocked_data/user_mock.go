@@ -0,0 +1,34 @@
+package mocked_data
+
+import (
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func GetValidUser() models.User {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	return models.User{
+		ID:         "auth0|123",
+		Name:       "Unit Test",
+		Nickname:   "unit",
+		Email:      "unit@test.com",
+		Picture:    "https://test.com/unit.png",
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+}
+
+func GetBsonFromUser(model models.User) bson.D {
+	return bson.D{
+		primitive.E{Key: "_id", Value: model.ID},
+		primitive.E{Key: "name", Value: model.Name},
+		primitive.E{Key: "nickname", Value: model.Nickname},
+		primitive.E{Key: "email", Value: model.Email},
+		primitive.E{Key: "picture", Value: model.Picture},
+		primitive.E{Key: "created_at", Value: model.CreatedAt},
+		primitive.E{Key: "last_seen_at", Value: model.LastSeenAt},
+	}
+}
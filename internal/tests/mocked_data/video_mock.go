@@ -17,7 +17,9 @@ func GetValidVideoWithId(id primitive.ObjectID) *models.Video {
 		Titulo:    "unit test title",
 		Descricao: "unit test description",
 		Url:       "www.unit-test.com",
+		Status:    models.PublishedStatus,
 		Active:    true,
+		Version:   1,
 	}
 }
 
@@ -27,7 +29,10 @@ func GetBsonFromVideo(model *models.Video) bson.D {
 		primitive.E{Key: "titulo", Value: model.Titulo},
 		primitive.E{Key: "descricao", Value: model.Descricao},
 		primitive.E{Key: "url", Value: model.Url},
+		primitive.E{Key: "status", Value: model.Status},
 		primitive.E{Key: "active", Value: model.Active},
+		primitive.E{Key: "version", Value: model.Version},
+		primitive.E{Key: "owner_id", Value: model.OwnerID},
 	}
 }
 
@@ -0,0 +1,42 @@
+package mocked_services
+
+import (
+	"context"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ interfaces.IAPIKeyService = (*APIKeyServiceMock)(nil)
+
+var (
+	APIKeyServiceMockGetAll    func() ([]models.APIKey, error)
+	APIKeyServiceMockGetByHash func(hash string) (*models.APIKey, error)
+	APIKeyServiceMockCreate    func(ctx context.Context, key models.APIKey) (*models.APIKey, error)
+	APIKeyServiceMockRevoke    func(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error)
+	APIKeyServiceMockTouch     func(id primitive.ObjectID, usedAt time.Time) error
+)
+
+type APIKeyServiceMock struct{}
+
+func (ks *APIKeyServiceMock) GetAll() ([]models.APIKey, error) {
+	return APIKeyServiceMockGetAll()
+}
+
+func (ks *APIKeyServiceMock) GetByHash(hash string) (*models.APIKey, error) {
+	return APIKeyServiceMockGetByHash(hash)
+}
+
+func (ks *APIKeyServiceMock) Create(ctx context.Context, key models.APIKey) (*models.APIKey, error) {
+	return APIKeyServiceMockCreate(ctx, key)
+}
+
+func (ks *APIKeyServiceMock) Revoke(ctx context.Context, id primitive.ObjectID) (*models.APIKey, error) {
+	return APIKeyServiceMockRevoke(ctx, id)
+}
+
+func (ks *APIKeyServiceMock) Touch(id primitive.ObjectID, usedAt time.Time) error {
+	return APIKeyServiceMockTouch(id, usedAt)
+}
@@ -0,0 +1,17 @@
+package mocked_services
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+var _ interfaces.IAuditService = (*AuditServiceMock)(nil)
+
+var AuditServiceMockGetAll func(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error)
+
+type AuditServiceMock struct{}
+
+func (as *AuditServiceMock) GetAll(filter dto.AuditFilter, page int64, pageSize int64) ([]models.AuditEntry, int64, error) {
+	return AuditServiceMockGetAll(filter, page, pageSize)
+}
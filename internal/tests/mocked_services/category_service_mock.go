@@ -1,6 +1,9 @@
 package mocked_services
 
 import (
+	"context"
+	"time"
+
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
@@ -9,13 +12,21 @@ import (
 
 var _ interfaces.ICategoryService = (*CategoryServiceMock)(nil)
 
-var CategoryServiceMockGetAll func(filter string, page int64, pageSize int64) ([]models.Category, error)
+var CategoryServiceMockGetAll func(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error)
+var CategoryServiceMockGetAllAfter func(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error)
 var CategoryServiceMockGetByID func(id primitive.ObjectID) (*models.Category, error)
 var CategoryServiceMockCreate func(insertCategory dto.InsertCategory) (*models.Category, error)
-var CategoryServiceMockUpdate func(id primitive.ObjectID, insertCategory dto.InsertCategory) (*models.Category, error)
-var CategoryServiceMockDelete func(id primitive.ObjectID) error
-var CategoryServiceMockGetVideosByCategoryId func(id primitive.ObjectID) ([]models.Video, error)
+var CategoryServiceMockUpdate func(id primitive.ObjectID, insertCategory dto.InsertCategory, version int64) (*models.Category, error)
+var CategoryServiceMockPatch func(id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error)
+var CategoryServiceMockDelete func(id primitive.ObjectID, version int64) error
+var CategoryServiceMockGetVideosByCategoryId func(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error)
+var CategoryServiceMockGetVideosByCategoryIdAfter func(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error)
 var CategoryServiceMockGetFreeCategory func() *models.Category
+var CategoryServiceMockGetDeleted func(page int64, pageSize int64) ([]models.Category, error)
+var CategoryServiceMockRestore func(id primitive.ObjectID) (*models.Category, error)
+var CategoryServiceMockPurge func(ctx context.Context, deletedBefore time.Time) (int64, error)
+var CategoryServiceMockGetRevisions func(id primitive.ObjectID) ([]models.CategoryRevision, error)
+var CategoryServiceMockRestoreRevision func(id primitive.ObjectID, revision int64, version int64) (*models.Category, error)
 
 type CategoryServiceMock struct{}
 
@@ -23,26 +34,58 @@ func (cs *CategoryServiceMock) GetById(id primitive.ObjectID) (*models.Category,
 	return CategoryServiceMockGetByID(id)
 }
 
-func (cs *CategoryServiceMock) GetAll(filter string, page int64, pageSize int64) ([]models.Category, error) {
-	return CategoryServiceMockGetAll(filter, page, pageSize)
+func (cs *CategoryServiceMock) GetAll(filter string, sort dto.Sort, page int64, pageSize int64) ([]models.Category, int64, error) {
+	return CategoryServiceMockGetAll(filter, sort, page, pageSize)
+}
+
+func (cs *CategoryServiceMock) GetAllAfter(filter string, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Category, *dto.Cursor, error) {
+	return CategoryServiceMockGetAllAfter(filter, sort, after, limit)
 }
 
-func (cs *CategoryServiceMock) Create(insertCategory dto.InsertCategory) (*models.Category, error) {
+func (cs *CategoryServiceMock) Create(ctx context.Context, insertCategory dto.InsertCategory) (*models.Category, error) {
 	return CategoryServiceMockCreate(insertCategory)
 }
 
-func (cs *CategoryServiceMock) Update(id primitive.ObjectID, newData dto.InsertCategory) (*models.Category, error) {
-	return CategoryServiceMockUpdate(id, newData)
+func (cs *CategoryServiceMock) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertCategory, version int64) (*models.Category, error) {
+	return CategoryServiceMockUpdate(id, newData, version)
+}
+
+func (cs *CategoryServiceMock) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return CategoryServiceMockDelete(id, version)
 }
 
-func (cs *CategoryServiceMock) Delete(id primitive.ObjectID) error {
-	return CategoryServiceMockDelete(id)
+func (cs *CategoryServiceMock) GetVideosByCategoryId(id primitive.ObjectID, publishedOnly bool) ([]models.Video, error) {
+	return CategoryServiceMockGetVideosByCategoryId(id, publishedOnly)
 }
 
-func (cs *CategoryServiceMock) GetVideosByCategoryId(id primitive.ObjectID) ([]models.Video, error) {
-	return CategoryServiceMockGetVideosByCategoryId(id)
+func (cs *CategoryServiceMock) GetVideosByCategoryIdAfter(id primitive.ObjectID, publishedOnly bool, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	return CategoryServiceMockGetVideosByCategoryIdAfter(id, publishedOnly, after, limit)
 }
 
 func (cs *CategoryServiceMock) GetFreeCategory() *models.Category {
 	return CategoryServiceMockGetFreeCategory()
 }
+
+func (cs *CategoryServiceMock) GetDeleted(page int64, pageSize int64) ([]models.Category, error) {
+	return CategoryServiceMockGetDeleted(page, pageSize)
+}
+
+func (cs *CategoryServiceMock) Restore(ctx context.Context, id primitive.ObjectID) (*models.Category, error) {
+	return CategoryServiceMockRestore(id)
+}
+
+func (cs *CategoryServiceMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return CategoryServiceMockPurge(ctx, deletedBefore)
+}
+
+func (cs *CategoryServiceMock) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchCategory, version int64) (*models.Category, error) {
+	return CategoryServiceMockPatch(id, patch, version)
+}
+
+func (cs *CategoryServiceMock) GetRevisions(id primitive.ObjectID) ([]models.CategoryRevision, error) {
+	return CategoryServiceMockGetRevisions(id)
+}
+
+func (cs *CategoryServiceMock) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Category, error) {
+	return CategoryServiceMockRestoreRevision(id, revision, version)
+}
@@ -0,0 +1,57 @@
+package mocked_services
+
+import (
+	"context"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var _ interfaces.IPlaylistService = (*PlaylistServiceMock)(nil)
+
+var (
+	PlaylistServiceMockGetAll      func(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error)
+	PlaylistServiceMockGetByID     func(id primitive.ObjectID) (*models.Playlist, error)
+	PlaylistServiceMockCreate      func(insertPlaylist dto.InsertPlaylist) (*models.Playlist, error)
+	PlaylistServiceMockUpdate      func(id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error)
+	PlaylistServiceMockDelete      func(id primitive.ObjectID, version int64) error
+	PlaylistServiceMockAddVideo    func(id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error)
+	PlaylistServiceMockRemoveVideo func(id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error)
+	PlaylistServiceMockMoveVideo   func(id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error)
+)
+
+type PlaylistServiceMock struct{}
+
+func (ps *PlaylistServiceMock) GetAll(filter dto.PlaylistFilter, page int64, pageSize int64) ([]models.Playlist, int64, error) {
+	return PlaylistServiceMockGetAll(filter, page, pageSize)
+}
+
+func (ps *PlaylistServiceMock) GetByID(id primitive.ObjectID) (*models.Playlist, error) {
+	return PlaylistServiceMockGetByID(id)
+}
+
+func (ps *PlaylistServiceMock) Create(ctx context.Context, insertPlaylist dto.InsertPlaylist) (*models.Playlist, error) {
+	return PlaylistServiceMockCreate(insertPlaylist)
+}
+
+func (ps *PlaylistServiceMock) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertPlaylist, version int64) (*models.Playlist, error) {
+	return PlaylistServiceMockUpdate(id, newData, version)
+}
+
+func (ps *PlaylistServiceMock) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return PlaylistServiceMockDelete(id, version)
+}
+
+func (ps *PlaylistServiceMock) AddVideo(ctx context.Context, id primitive.ObjectID, item dto.InsertPlaylistItem, version int64) (*models.Playlist, error) {
+	return PlaylistServiceMockAddVideo(id, item, version)
+}
+
+func (ps *PlaylistServiceMock) RemoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, version int64) (*models.Playlist, error) {
+	return PlaylistServiceMockRemoveVideo(id, videoID, version)
+}
+
+func (ps *PlaylistServiceMock) MoveVideo(ctx context.Context, id primitive.ObjectID, videoID primitive.ObjectID, position int, version int64) (*models.Playlist, error) {
+	return PlaylistServiceMockMoveVideo(id, videoID, position, version)
+}
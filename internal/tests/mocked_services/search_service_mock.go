@@ -0,0 +1,21 @@
+package mocked_services
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+)
+
+var _ interfaces.ISearchService = (*SearchServiceMock)(nil)
+
+var SearchServiceMockSearch func(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error)
+var SearchServiceMockSuggest func(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error)
+
+type SearchServiceMock struct{}
+
+func (ss *SearchServiceMock) Search(query string, publishedOnly bool, limit int64) (*dto.SearchResults, error) {
+	return SearchServiceMockSearch(query, publishedOnly, limit)
+}
+
+func (ss *SearchServiceMock) Suggest(prefix string, freeOnly bool, publishedOnly bool, limit int64) ([]dto.Suggestion, error) {
+	return SearchServiceMockSuggest(prefix, freeOnly, publishedOnly, limit)
+}
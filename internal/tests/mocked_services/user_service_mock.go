@@ -0,0 +1,28 @@
+package mocked_services
+
+import (
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+)
+
+var _ interfaces.IUserService = (*UserServiceMock)(nil)
+
+var (
+	UserServiceMockGetAll  func(page int64, pageSize int64) ([]models.User, int64, error)
+	UserServiceMockGetByID func(id string) (*models.User, error)
+	UserServiceMockSave    func(user models.User) (*models.User, error)
+)
+
+type UserServiceMock struct{}
+
+func (us *UserServiceMock) GetAll(page int64, pageSize int64) ([]models.User, int64, error) {
+	return UserServiceMockGetAll(page, pageSize)
+}
+
+func (us *UserServiceMock) GetByID(id string) (*models.User, error) {
+	return UserServiceMockGetByID(id)
+}
+
+func (us *UserServiceMock) Save(user models.User) (*models.User, error) {
+	return UserServiceMockSave(user)
+}
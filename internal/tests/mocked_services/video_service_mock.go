@@ -1,6 +1,9 @@
 package mocked_services
 
 import (
+	"context"
+	"time"
+
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
 	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
@@ -10,33 +13,78 @@ import (
 var _ interfaces.IVideoService = (*VideoServiceMock)(nil)
 
 var VideoServiceMockGetAllFreeVideos func() ([]models.Video, error)
-var VideoServiceMockGetAll func(filter string, page int64, pageSize int64) ([]models.Video, error)
+var VideoServiceMockGetAll func(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error)
+var VideoServiceMockGetAllAfter func(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error)
+var VideoServiceMockGetFacets func(filter dto.VideoFilter) (*dto.VideoFacets, error)
 var VideoServiceMockGetById func(id primitive.ObjectID) (*models.Video, error)
 var VideoServiceMockCreate func(video dto.InsertVideo) (*models.Video, error)
-var VideoServiceMockUpdate func(id primitive.ObjectID, newData dto.InsertVideo) (*models.Video, error)
-var VideoServiceMockDelete func(id primitive.ObjectID) error
+var VideoServiceMockUpdate func(id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error)
+var VideoServiceMockPatch func(id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error)
+var VideoServiceMockDelete func(id primitive.ObjectID, version int64) error
+var VideoServiceMockGetDeleted func(page int64, pageSize int64) ([]models.Video, error)
+var VideoServiceMockRestore func(id primitive.ObjectID) (*models.Video, error)
+var VideoServiceMockPurge func(ctx context.Context, deletedBefore time.Time) (int64, error)
+var VideoServiceMockGetRevisions func(id primitive.ObjectID) ([]models.VideoRevision, error)
+var VideoServiceMockRestoreRevision func(id primitive.ObjectID, revision int64, version int64) (*models.Video, error)
+var VideoServiceMockPublishDue func(ctx context.Context, now time.Time) ([]models.Video, error)
 
 type VideoServiceMock struct{}
 
 func (vs *VideoServiceMock) GetAllFreeVideos() ([]models.Video, error) {
 	return VideoServiceMockGetAllFreeVideos()
 }
-func (vs *VideoServiceMock) GetAll(filter string, page int64, pageSize int64) ([]models.Video, error) {
-	return VideoServiceMockGetAll(filter, page, pageSize)
+func (vs *VideoServiceMock) GetAll(filter dto.VideoFilter, sort dto.Sort, page int64, pageSize int64) ([]models.Video, int64, error) {
+	return VideoServiceMockGetAll(filter, sort, page, pageSize)
+}
+
+func (vs *VideoServiceMock) GetAllAfter(filter dto.VideoFilter, sort dto.Sort, after *dto.Cursor, limit int64) ([]models.Video, *dto.Cursor, error) {
+	return VideoServiceMockGetAllAfter(filter, sort, after, limit)
+}
+
+func (vs *VideoServiceMock) GetFacets(filter dto.VideoFilter) (*dto.VideoFacets, error) {
+	return VideoServiceMockGetFacets(filter)
 }
 
 func (vs *VideoServiceMock) GetByID(id primitive.ObjectID) (*models.Video, error) {
 	return VideoServiceMockGetById(id)
 }
 
-func (vs *VideoServiceMock) Create(video dto.InsertVideo) (*models.Video, error) {
+func (vs *VideoServiceMock) Create(ctx context.Context, video dto.InsertVideo) (*models.Video, error) {
 	return VideoServiceMockCreate(video)
 }
 
-func (vs *VideoServiceMock) Update(id primitive.ObjectID, newData dto.InsertVideo) (*models.Video, error) {
-	return VideoServiceMockUpdate(id, newData)
+func (vs *VideoServiceMock) Update(ctx context.Context, id primitive.ObjectID, newData dto.InsertVideo, version int64) (*models.Video, error) {
+	return VideoServiceMockUpdate(id, newData, version)
+}
+
+func (vs *VideoServiceMock) Delete(ctx context.Context, id primitive.ObjectID, version int64) error {
+	return VideoServiceMockDelete(id, version)
+}
+
+func (vs *VideoServiceMock) GetDeleted(page int64, pageSize int64) ([]models.Video, error) {
+	return VideoServiceMockGetDeleted(page, pageSize)
+}
+
+func (vs *VideoServiceMock) Restore(ctx context.Context, id primitive.ObjectID) (*models.Video, error) {
+	return VideoServiceMockRestore(id)
+}
+
+func (vs *VideoServiceMock) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	return VideoServiceMockPurge(ctx, deletedBefore)
+}
+
+func (vs *VideoServiceMock) Patch(ctx context.Context, id primitive.ObjectID, patch dto.PatchVideo, version int64) (*models.Video, error) {
+	return VideoServiceMockPatch(id, patch, version)
+}
+
+func (vs *VideoServiceMock) GetRevisions(id primitive.ObjectID) ([]models.VideoRevision, error) {
+	return VideoServiceMockGetRevisions(id)
+}
+
+func (vs *VideoServiceMock) RestoreRevision(ctx context.Context, id primitive.ObjectID, revision int64, version int64) (*models.Video, error) {
+	return VideoServiceMockRestoreRevision(id, revision, version)
 }
 
-func (vs *VideoServiceMock) Delete(id primitive.ObjectID) error {
-	return VideoServiceMockDelete(id)
+func (vs *VideoServiceMock) PublishDue(ctx context.Context, now time.Time) ([]models.Video, error) {
+	return VideoServiceMockPublishDue(ctx, now)
 }
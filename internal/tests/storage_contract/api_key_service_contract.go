@@ -0,0 +1,84 @@
+package storage_contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAPIKeyService(t *testing.T, provide Provider) {
+	t.Run("Create method Should stamp the key with its author and GetByHash find it", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+		key := mocked_data.GetValidAPIKey()
+
+		created, err := service.Create(mocked_data.GetContextWithSubject("auth0|123"), key)
+		found, findErr := service.GetByHash(key.Hash)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|123", created.CreatedBy)
+		assert.False(t, created.CreatedAt.IsZero())
+		assert.Nil(t, findErr)
+		assert.Equal(t, created, found)
+	})
+
+	t.Run("GetByHash method Should return ErrNotFound When no key has the hash", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+
+		key, err := service.GetByHash("unknown")
+
+		assert.Nil(t, key)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("GetAll method Should list the keys newest first", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+		first, second := mocked_data.GetValidAPIKey(), mocked_data.GetValidAPIKey()
+		second.Hash = "other"
+		_, _ = service.Create(mocked_data.GetAdminContext(), first)
+		_, _ = service.Create(mocked_data.GetAdminContext(), second)
+
+		keys, err := service.GetAll()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{second.ID, first.ID}, []primitive.ObjectID{keys[0].ID, keys[1].ID})
+	})
+
+	t.Run("Revoke method Should stamp the revocation once", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+		key, _ := service.Create(mocked_data.GetAdminContext(), mocked_data.GetValidAPIKey())
+
+		revoked, err := service.Revoke(mocked_data.GetContextWithSubject("auth0|123"), key.ID)
+		again, _ := service.Revoke(mocked_data.GetContextWithSubject("auth0|456"), key.ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, revoked.RevokedAt)
+		assert.Equal(t, "auth0|123", revoked.RevokedBy)
+		assert.Equal(t, revoked, again)
+	})
+
+	t.Run("Revoke method Should return ErrNotFound When the key does not exist", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+
+		_, err := service.Revoke(mocked_data.GetAdminContext(), primitive.NewObjectID())
+
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("Touch method Should record when the key was last used", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).APIKeys
+		key, _ := service.Create(mocked_data.GetAdminContext(), mocked_data.GetValidAPIKey())
+		usedAt := time.Date(2021, 8, 14, 4, 46, 49, 0, time.UTC)
+
+		err := service.Touch(key.ID, usedAt)
+		found, _ := service.GetByHash(key.Hash)
+
+		assert.Nil(t, err)
+		assert.Equal(t, &usedAt, found.LastUsedAt)
+		assert.Equal(t, interfaces.ErrNotFound, service.Touch(primitive.NewObjectID(), usedAt))
+	})
+}
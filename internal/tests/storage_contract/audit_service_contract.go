@@ -0,0 +1,82 @@
+package storage_contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService(t *testing.T, provide Provider) {
+	t.Run("GetAll method Should return the history of a video newest first When it was written", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		videoService := services.Videos
+		auditService := services.Audit
+		ctx := mocked_data.GetContextWithSubject("auth0|123")
+		video, _ := videoService.Create(ctx, mocked_data.GetValidInsertVideoDto())
+		title := "Patched title"
+		_, _ = videoService.Patch(ctx, video.ID, dto.PatchVideo{Titulo: &title}, 0)
+		_ = videoService.Delete(ctx, video.ID, 0)
+		_, _ = videoService.Restore(ctx, video.ID)
+
+		entries, total, err := auditService.GetAll(dto.AuditFilter{Resource: models.VideoResource, ResourceID: video.ID}, 1, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(4), total)
+		var operations []string
+		for _, entry := range entries {
+			operations = append(operations, entry.Operation)
+			assert.Equal(t, "auth0|123", entry.Actor)
+		}
+		assert.Equal(t, []string{models.RestoreOperation, models.DeleteOperation, models.UpdateOperation, models.CreateOperation}, operations)
+		assert.Equal(t, []models.FieldChange{{Field: "titulo", Before: video.Titulo, After: title}}, entries[2].Changes)
+		assert.Contains(t, entries[3].Changes, models.FieldChange{Field: "titulo", Before: nil, After: video.Titulo})
+	})
+
+	t.Run("GetAll method Should record the videos a cascading category delete removes", func(t *testing.T) {
+		services := provide(t, domain.CascadePolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		auditService := services.Audit
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		assert.Nil(t, categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0))
+
+		entries, total, err := auditService.GetAll(dto.AuditFilter{Operation: models.DeleteOperation}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		var deleted []string
+		for _, entry := range entries {
+			deleted = append(deleted, entry.Resource+"/"+entry.ResourceID.Hex())
+		}
+		assert.ElementsMatch(t, []string{"videos/" + video.ID.Hex(), "categories/" + category.ID.Hex()}, deleted)
+	})
+
+	t.Run("GetAll method Should filter by actor and time and paginate", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		auditService := services.Audit
+		start := time.Now().UTC().Truncate(time.Millisecond)
+		for i := 0; i < 3; i++ {
+			_, _ = categoryService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertCategoryDto())
+		}
+		_, _ = categoryService.Create(mocked_data.GetContextWithSubject("auth0|456"), mocked_data.GetValidInsertCategoryDto())
+
+		entries, total, err := auditService.GetAll(dto.AuditFilter{Actor: "auth0|123", From: start}, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, 1, len(entries))
+
+		entries, total, err = auditService.GetAll(dto.AuditFilter{Before: start}, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), total)
+		assert.Nil(t, entries)
+	})
+}
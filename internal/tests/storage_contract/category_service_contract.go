@@ -0,0 +1,489 @@
+package storage_contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCategoryService(t *testing.T, provide Provider) {
+	t.Run("GetAllCategories method Should return objects paginated when has objects", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		for i := 0; i < 3; i++ {
+			_, _ = categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		}
+
+		firstPage, _, err := categoryService.GetAll("", dto.Sort{}, 1, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(firstPage))
+
+		secondPage, total, err := categoryService.GetAll("", dto.Sort{}, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(secondPage))
+		assert.Equal(t, int64(3), total)
+		assert.NotEqual(t, firstPage[0].ID, secondPage[0].ID)
+	})
+
+	t.Run("GetAllCategories method with filter Should return only matching objects", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Front-end", Cor: "blue"})
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Back-end", Cor: "red"})
+
+		response, total, err := categoryService.GetAll("Front", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Front-end", response[0].Titulo)
+	})
+
+	t.Run("GetAllCategories method Should return nil when dont has objects", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+
+		response, _, err := categoryService.GetAll("", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("GetAllCategories method Should match regex metacharacters literally", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category := mocked_data.GetValidInsertCategoryDto()
+		category.Titulo = "C++ (avançado)"
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), category)
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+
+		response, _, err := categoryService.GetAll("(", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+
+		response, _, err = categoryService.GetAll(".*", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("GetAllCategories method Should ignore case and accents", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category := mocked_data.GetValidInsertCategoryDto()
+		category.Titulo = "Programação"
+		created, _ := categoryService.Create(mocked_data.GetAdminContext(), category)
+
+		for _, search := range []string{"programacao", "PROGRAMAÇÃO", "gramaç"} {
+			response, _, err := categoryService.GetAll(search, dto.Sort{}, 1, 5)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(response), search)
+			assert.Equal(t, created.ID, response[0].ID)
+		}
+	})
+
+	t.Run("GetAllCategories method with filter Should match wildcards literally", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "100% Go", Cor: "blue"})
+		_, _ = categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "1000 Go tips", Cor: "red"})
+
+		response, _, err := categoryService.GetAll("100%", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, "100% Go", response[0].Titulo)
+	})
+
+	t.Run("GetAllCategories method with filter Should match the title set by a patch", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		created, _ := categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Programação", Cor: "blue"})
+		title := "Lógica"
+		_, _ = categoryService.Patch(mocked_data.GetAdminContext(), created.ID, dto.PatchCategory{Titulo: &title}, 0)
+
+		for _, search := range []string{"logica", "LÓGICA", "ógi"} {
+			response, _, err := categoryService.GetAll(search, dto.Sort{}, 1, 5)
+			assert.Nil(t, err)
+			assert.Equal(t, 1, len(response), search)
+		}
+		response, _, err := categoryService.GetAll("programacao", dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("GetCategoryById method Should return object when object exists", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		expectedCategory, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+
+		response, err := categoryService.GetById(expectedCategory.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, expectedCategory, response)
+	})
+
+	t.Run("GetCategoryById method Should return error when object dont exists", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+
+		response, err := categoryService.GetById(primitive.NewObjectID())
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("UpdateCategory method Should update fields When object exists", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), category.ID, dto.InsertCategory{Titulo: "New title", Cor: "green"}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "New title", response.Titulo)
+		assert.Equal(t, "green", response.Cor)
+
+		stored, _ := categoryService.GetById(category.ID)
+		assert.Equal(t, response, stored)
+	})
+
+	t.Run("UpdateCategory method Should return error When object dont exists", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), primitive.NewObjectID(), mocked_data.GetValidInsertCategoryDto(), 0)
+		assert.NotNil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("DeleteCategory method Should delete an item When the item can be deleted", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0)
+		assert.Nil(t, err)
+
+		_, err = categoryService.GetById(category.ID)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("DeleteCategory method Should return ErrNotFound When document dont exists", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), primitive.NewObjectID(), 0)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("GetVideosByCategoryId method Should return only videos from the category", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		video := mocked_data.GetValidInsertVideoDto()
+		video.CategoryID = category.ID
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := categoryService.GetVideosByCategoryId(category.ID, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, category.ID, response[0].CategoryID)
+	})
+
+	t.Run("GetVideosByCategoryId method Should leave out the videos not published When publishedOnly is set", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		draft := mocked_data.GetValidInsertVideoDto()
+		draft.CategoryID = category.ID
+		draft.Status = models.DraftStatus
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), draft)
+		video := mocked_data.GetValidInsertVideoDto()
+		video.CategoryID = category.ID
+		published, _ := videoService.Create(mocked_data.GetAdminContext(), video)
+
+		response, err := categoryService.GetVideosByCategoryId(category.ID, true)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, published.ID, response[0].ID)
+
+		firstPage, next, err := categoryService.GetVideosByCategoryIdAfter(category.ID, true, nil, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, published.ID, firstPage[0].ID)
+		assert.Nil(t, next)
+
+		response, err = categoryService.GetVideosByCategoryId(category.ID, false)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(response))
+	})
+
+	t.Run("GetVideosByCategoryIdAfter method Should page the videos of the category by cursor", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		video := mocked_data.GetValidInsertVideoDto()
+		video.CategoryID = category.ID
+		first, _ := videoService.Create(mocked_data.GetAdminContext(), video)
+		second, _ := videoService.Create(mocked_data.GetAdminContext(), video)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		firstPage, next, err := categoryService.GetVideosByCategoryIdAfter(category.ID, false, nil, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, first.ID, firstPage[0].ID)
+		assert.Equal(t, &dto.Cursor{ID: first.ID}, next)
+
+		lastPage, next, err := categoryService.GetVideosByCategoryIdAfter(category.ID, false, next, 1)
+		assert.Nil(t, err)
+		assert.Equal(t, second.ID, lastPage[0].ID)
+		assert.Nil(t, next)
+	})
+
+	t.Run("GetAllCategoriesAfter method Should return only the objects after the cursor", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		first, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		second, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+
+		response, next, err := categoryService.GetAllAfter("", dto.Sort{}, &dto.Cursor{ID: first.ID}, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, second.ID, response[0].ID)
+		assert.Nil(t, next)
+	})
+
+	t.Run("GetFreeCategory method Should create the free category only once", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+
+		first := categoryService.GetFreeCategory()
+		second := categoryService.GetFreeCategory()
+		categories, _, _ := categoryService.GetAll("", dto.Sort{}, 1, 5)
+
+		assert.Equal(t, "FREE", first.Titulo)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, len(categories))
+	})
+
+	t.Run("RestoreCategory method Should bring back a deleted item", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		_ = categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0)
+
+		deleted, err := categoryService.GetDeleted(1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(deleted))
+
+		response, err := categoryService.Restore(mocked_data.GetAdminContext(), category.ID)
+		assert.Nil(t, err)
+		assert.True(t, response.Active)
+
+		_, err = categoryService.Restore(mocked_data.GetAdminContext(), category.ID)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("PurgeCategories method Should only remove the trash of the token subject unless it grants admin:content", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		alice, bob := mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetContextWithSubject("auth0|456")
+		category, _ := categoryService.Create(alice, mocked_data.GetValidInsertCategoryDto())
+		_ = categoryService.Delete(alice, category.ID, 0)
+
+		purged, err := categoryService.Purge(bob, time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+		deleted, _ := categoryService.GetDeleted(1, 5)
+		assert.Equal(t, 1, len(deleted))
+
+		purged, err = categoryService.Purge(mocked_data.GetAdminContext(), time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+	})
+
+	t.Run("PurgeCategories method Should keep deleted items still referenced by deleted videos", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		referenced, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		unreferenced, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = referenced.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+		_ = categoryService.Delete(mocked_data.GetAdminContext(), referenced.ID, 0)
+		_ = categoryService.Delete(mocked_data.GetAdminContext(), unreferenced.ID, 0)
+
+		purged, err := categoryService.Purge(mocked_data.GetAdminContext(), time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+		deleted, _ := categoryService.GetDeleted(1, 5)
+		assert.Equal(t, 1, len(deleted))
+		assert.Equal(t, referenced.ID, deleted[0].ID)
+	})
+
+	t.Run("DeleteCategory method Should return ErrCategoryHasVideos When the reject policy finds videos", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		err := categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0)
+		assert.Equal(t, interfaces.ErrCategoryHasVideos, err)
+		_, err = categoryService.GetById(category.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("DeleteCategory method Should delete the videos too When using the cascade policy", func(t *testing.T) {
+		services := provide(t, domain.CascadePolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		assert.Nil(t, categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0))
+		_, err := videoService.GetByID(video.ID)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		deleted, _ := videoService.GetDeleted(1, 5)
+		assert.Equal(t, 1, len(deleted))
+	})
+
+	t.Run("DeleteCategory method Should move the videos to the free category When using the reassign policy", func(t *testing.T) {
+		services := provide(t, domain.ReassignPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		assert.Nil(t, categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0))
+		response, err := videoService.GetByID(video.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, models.GetFreeCategory().ID, response.CategoryID)
+	})
+
+	t.Run("DeleteCategory method Should refuse to delete or reassign the videos of other users", func(t *testing.T) {
+		for _, policy := range []domain.CategoryDeletePolicy{domain.CascadePolicy, domain.ReassignPolicy} {
+			services := provide(t, policy)
+			categoryService := services.Categories
+			videoService := services.Videos
+			alice, bob := mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetContextWithSubject("auth0|456")
+			category, _ := categoryService.Create(alice, mocked_data.GetValidInsertCategoryDto())
+			insertVideo := mocked_data.GetValidInsertVideoDto()
+			insertVideo.CategoryID = category.ID
+			video, _ := videoService.Create(bob, insertVideo)
+
+			assert.Equal(t, interfaces.ErrNotOwner, categoryService.Delete(alice, category.ID, 0), string(policy))
+			response, err := videoService.GetByID(video.ID)
+			assert.Nil(t, err, string(policy))
+			assert.Equal(t, category.ID, response.CategoryID, string(policy))
+			_, err = categoryService.GetById(category.ID)
+			assert.Nil(t, err, string(policy))
+		}
+	})
+
+	t.Run("DeleteCategory method Should stamp the videos it reassigns with the token subject", func(t *testing.T) {
+		services := provide(t, domain.ReassignPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		admin := mocked_data.GetContextWithPermissions("auth0|456", domain.AdminPermission)
+		assert.Nil(t, categoryService.Delete(admin, category.ID, 0))
+		response, _ := videoService.GetByID(video.ID)
+		assert.Equal(t, "", response.CreatedBy)
+		assert.Equal(t, "auth0|456", response.UpdatedBy)
+		deleted, _ := categoryService.GetDeleted(1, 5)
+		assert.Equal(t, response.UpdatedAt, *deleted[0].DeletedAt)
+		assert.Equal(t, "auth0|456", deleted[0].UpdatedBy)
+	})
+
+	t.Run("DeleteCategory method Should return ErrProtectedCategory When deleting the free category", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		free := categoryService.GetFreeCategory()
+
+		assert.Equal(t, interfaces.ErrProtectedCategory, categoryService.Delete(mocked_data.GetAdminContext(), free.ID, 0))
+	})
+
+	t.Run("UpdateCategory method Should only allow changing the color of the free category", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		free := categoryService.GetFreeCategory()
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), free.ID, mocked_data.GetValidInsertCategoryDto(), 0)
+		assert.Equal(t, interfaces.ErrProtectedCategory, err)
+		assert.Nil(t, response)
+
+		response, err = categoryService.Update(mocked_data.GetAdminContext(), free.ID, dto.InsertCategory{Titulo: "FREE", Cor: "Green"}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "Green", response.Cor)
+	})
+
+	t.Run("PatchCategory method Should change only the fields in the patch", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		color := "Green"
+
+		response, err := categoryService.Patch(mocked_data.GetAdminContext(), category.ID, dto.PatchCategory{Cor: &color}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, category.Titulo, response.Titulo)
+		assert.Equal(t, "Green", response.Cor)
+	})
+
+	t.Run("PatchCategory method Should return ErrProtectedCategory When renaming the free category", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		free := categoryService.GetFreeCategory()
+		title := "Not free"
+
+		response, err := categoryService.Patch(mocked_data.GetAdminContext(), free.ID, dto.PatchCategory{Titulo: &title}, 0)
+		assert.Equal(t, interfaces.ErrProtectedCategory, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("UpdateCategory method Should return ErrVersionMismatch When the expected version is stale", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		_, _ = categoryService.Update(mocked_data.GetAdminContext(), category.ID, mocked_data.GetValidInsertCategoryDto(), category.Version)
+
+		response, err := categoryService.Update(mocked_data.GetAdminContext(), category.ID, mocked_data.GetValidInsertCategoryDto(), category.Version)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("DeleteCategory method Should bump the version of the videos it reassigns", func(t *testing.T) {
+		services := provide(t, domain.ReassignPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		assert.Nil(t, categoryService.Delete(mocked_data.GetAdminContext(), category.ID, category.Version))
+		reassigned, _ := videoService.GetByID(video.ID)
+		assert.Equal(t, video.Version+1, reassigned.Version)
+	})
+
+	t.Run("RestoreRevision method Should update the item back to the revision", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		color := "#000000"
+		updated, _ := categoryService.Patch(mocked_data.GetAdminContext(), category.ID, dto.PatchCategory{Cor: &color}, 0)
+
+		revisions, err := categoryService.GetRevisions(category.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(revisions))
+		assert.Equal(t, category.Version, revisions[0].Revision)
+
+		response, err := categoryService.RestoreRevision(mocked_data.GetAdminContext(), category.ID, category.Version, updated.Version)
+		assert.Nil(t, err)
+		assert.Equal(t, category.Cor, response.Cor)
+		assert.Equal(t, updated.Version+1, response.Version)
+	})
+
+	t.Run("RestoreRevision method Should return ErrVersionMismatch When the expected version is stale", func(t *testing.T) {
+		categoryService := provide(t, domain.RejectPolicy).Categories
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		color := "#000000"
+		_, _ = categoryService.Patch(mocked_data.GetAdminContext(), category.ID, dto.PatchCategory{Cor: &color}, 0)
+
+		response, err := categoryService.RestoreRevision(mocked_data.GetAdminContext(), category.ID, category.Version, category.Version)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		assert.Nil(t, response)
+	})
+}
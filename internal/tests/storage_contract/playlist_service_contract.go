@@ -0,0 +1,147 @@
+package storage_contract
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func videoIDs(playlist *models.Playlist) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for _, item := range playlist.Items {
+		ids = append(ids, item.VideoID)
+	}
+	return ids
+}
+
+func TestPlaylistService(t *testing.T, provide Provider) {
+	owner := mocked_data.GetContextWithSubject("auth0|123")
+
+	t.Run("AddVideo, MoveVideo and RemoveVideo methods Should keep the videos in order", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		playlistService, videoService := services.Playlists, services.Videos
+		playlist, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Favorites"})
+		first, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		second, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		third, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: first.ID}, 0)
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: second.ID}, 0)
+		added, err := playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: third.ID, Position: 1}, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{third.ID, first.ID, second.ID}, videoIDs(added))
+
+		moved, err := playlistService.MoveVideo(owner, playlist.ID, third.ID, 3, 4)
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{first.ID, second.ID, third.ID}, videoIDs(moved))
+		assert.Equal(t, []primitive.ObjectID{third.ID, first.ID, second.ID}, videoIDs(added))
+
+		removed, err := playlistService.RemoveVideo(owner, playlist.ID, second.ID, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{first.ID, third.ID}, videoIDs(removed))
+		assert.Equal(t, int64(6), removed.Version)
+	})
+
+	t.Run("AddVideo method Should reject a video already in the playlist, a deleted one and a position past the end", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		playlistService, videoService := services.Playlists, services.Videos
+		playlist, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Favorites"})
+		video, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		deleted, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		_ = videoService.Delete(owner, deleted.ID, 0)
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: video.ID}, 0)
+
+		_, err := playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: video.ID}, 0)
+		assert.Equal(t, interfaces.ErrVideoInPlaylist, err)
+		_, err = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: deleted.ID}, 0)
+		assert.Equal(t, interfaces.ErrVideoNotFound, err)
+		other, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		_, err = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: other.ID, Position: 3}, 0)
+		assert.IsType(t, dto.ValidationError{}, err)
+	})
+
+	t.Run("DeleteVideo method Should take the video out of every playlist", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		playlistService, videoService := services.Playlists, services.Videos
+		kept, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		video, _ := videoService.Create(owner, mocked_data.GetValidInsertVideoDto())
+		playlist, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Favorites"})
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: kept.ID}, 0)
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: video.ID}, 0)
+
+		assert.Nil(t, videoService.Delete(owner, video.ID, 0))
+		response, _ := playlistService.GetByID(playlist.ID)
+		assert.Equal(t, []primitive.ObjectID{kept.ID}, videoIDs(response))
+		assert.Equal(t, int64(4), response.Version)
+
+		_, _ = videoService.Restore(owner, video.ID)
+		response, _ = playlistService.GetByID(playlist.ID)
+		assert.Equal(t, []primitive.ObjectID{kept.ID}, videoIDs(response))
+	})
+
+	t.Run("DeleteCategory method Should take its videos out of every playlist When using the cascade policy", func(t *testing.T) {
+		services := provide(t, domain.CascadePolicy)
+		playlistService, videoService, categoryService := services.Playlists, services.Videos, services.Categories
+		category, _ := categoryService.Create(owner, mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(owner, insertVideo)
+		playlist, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Favorites"})
+		_, _ = playlistService.AddVideo(owner, playlist.ID, dto.InsertPlaylistItem{VideoID: video.ID}, 0)
+
+		assert.Nil(t, categoryService.Delete(owner, category.ID, 0))
+		response, _ := playlistService.GetByID(playlist.ID)
+		assert.Empty(t, response.Items)
+	})
+
+	t.Run("GetAll method Should leave out the private playlists of others unless the viewer sees them all", func(t *testing.T) {
+		playlistService := provide(t, domain.RejectPolicy).Playlists
+		other := mocked_data.GetContextWithSubject("auth0|456")
+		public, _ := playlistService.Create(other, dto.InsertPlaylist{Titulo: "Public", Visibility: models.PublicVisibility})
+		_, _ = playlistService.Create(other, dto.InsertPlaylist{Titulo: "Private"})
+		own, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Own"})
+
+		visible, total, _ := playlistService.GetAll(dto.PlaylistFilter{Viewer: "auth0|123"}, 1, 5)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []primitive.ObjectID{public.ID, own.ID}, []primitive.ObjectID{visible[0].ID, visible[1].ID})
+		_, total, _ = playlistService.GetAll(dto.PlaylistFilter{OwnerID: "auth0|456"}, 1, 5)
+		assert.Equal(t, int64(2), total)
+		visible, total, _ = playlistService.GetAll(dto.PlaylistFilter{PublicOnly: true}, 1, 5)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, public.ID, visible[0].ID)
+	})
+
+	t.Run("Update and Delete methods Should only let the owner or an admin change the playlist", func(t *testing.T) {
+		playlistService := provide(t, domain.RejectPolicy).Playlists
+		playlist, _ := playlistService.Create(owner, dto.InsertPlaylist{Titulo: "Favorites"})
+		other := mocked_data.GetContextWithSubject("auth0|456")
+
+		_, err := playlistService.Update(other, playlist.ID, dto.InsertPlaylist{Titulo: "Mine"}, 0)
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+		assert.Equal(t, interfaces.ErrNotOwner, playlistService.Delete(other, playlist.ID, 0))
+
+		admin := mocked_data.GetContextWithPermissions("auth0|456", domain.AdminPermission)
+		updated, err := playlistService.Update(admin, playlist.ID, dto.InsertPlaylist{Titulo: "Renamed", Visibility: "public"}, 1)
+		assert.Nil(t, err)
+		assert.True(t, updated.IsPublic())
+		assert.Equal(t, interfaces.ErrVersionMismatch, playlistService.Delete(owner, playlist.ID, 1))
+		assert.Nil(t, playlistService.Delete(owner, playlist.ID, 2))
+		_, err = playlistService.GetByID(playlist.ID)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("MoveVideo method Should return ErrVideoNotInPlaylist When the playlist does not hold the video", func(t *testing.T) {
+		playlistService := provide(t, domain.RejectPolicy).Playlists
+		playlist, _ := playlistService.Create(mocked_data.GetAdminContext(), dto.InsertPlaylist{Titulo: "Favorites"})
+
+		_, err := playlistService.MoveVideo(mocked_data.GetAdminContext(), playlist.ID, primitive.NewObjectID(), 1, 0)
+
+		assert.Equal(t, interfaces.ErrVideoNotInPlaylist, err)
+	})
+}
@@ -0,0 +1,140 @@
+package storage_contract
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchService(t *testing.T, provide Provider) {
+	t.Run("Search method Should rank title matches first and group the results by type", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		searchService := services.Search
+		inDescription, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Primeiros passos", Descricao: "Um curso de programação em Go", Url: "https://www.example.com"})
+		inTitle, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Introdução à Programação", Descricao: "Comece aqui", Url: "https://www.example.com"})
+		deleted, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Programação antiga", Descricao: "Removido", Url: "https://www.example.com"})
+		_ = videoService.Delete(mocked_data.GetAdminContext(), deleted.ID, 0)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Culinária", Descricao: "Receitas", Url: "https://www.example.com"})
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Programacao", Cor: "blue"})
+
+		results, err := searchService.Search("PROGRAMAÇÃO", false, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "PROGRAMAÇÃO", results.Query)
+		assert.Equal(t, 2, len(results.Videos))
+		assert.Equal(t, inTitle.ID, results.Videos[0].Video.ID)
+		assert.Equal(t, inDescription.ID, results.Videos[1].Video.ID)
+		assert.Greater(t, results.Videos[0].Score, results.Videos[1].Score)
+		assert.Equal(t, "Introdução à <mark>Programação</mark>", results.Videos[0].Highlights["titulo"])
+		assert.Equal(t, "Um curso de <mark>programação</mark> em Go", results.Videos[1].Highlights["descricao"])
+		assert.Equal(t, 1, len(results.Categories))
+		assert.Equal(t, category.ID, results.Categories[0].Category.ID)
+	})
+
+	t.Run("Search method Should keep at most limit items of each type", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		videoService := services.Videos
+		searchService := services.Search
+		for i := 0; i < 3; i++ {
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go", Descricao: "Go", Url: "https://www.example.com"})
+		}
+
+		results, err := searchService.Search("go", false, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(results.Videos))
+		assert.Equal(t, []dto.CategoryHit{}, results.Categories)
+	})
+
+	t.Run("Search method Should leave out the videos not published When publishedOnly is set", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		videoService := services.Videos
+		searchService := services.Search
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go rascunho", Descricao: "Go", Url: "https://www.example.com", Status: models.DraftStatus})
+		published, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go publicado", Descricao: "Go", Url: "https://www.example.com"})
+
+		results, err := searchService.Search("go", true, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(results.Videos))
+		assert.Equal(t, published.ID, results.Videos[0].Video.ID)
+
+		results, err = searchService.Search("go", false, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(results.Videos))
+	})
+
+	t.Run("Suggest method Should complete the folded prefix with active titles of both types", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		searchService := services.Search
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Programação", Cor: "blue"})
+		free, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Programar em Go", Descricao: "Go", Url: "https://www.example.com"})
+		paid, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "PROGRAMAÇÃO funcional", Descricao: "Haskell", CategoryID: category.ID, Url: "https://www.example.com"})
+		deleted, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Programa antigo", Descricao: "Removido", Url: "https://www.example.com"})
+		_ = videoService.Delete(mocked_data.GetAdminContext(), deleted.ID, 0)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Aprendendo a programar", Descricao: "Go", Url: "https://www.example.com"})
+
+		suggestions, err := searchService.Suggest("progra", false, false, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.Suggestion{
+			{ID: category.ID, Titulo: category.Titulo, Type: dto.CategorySuggestion},
+			{ID: paid.ID, Titulo: paid.Titulo, Type: dto.VideoSuggestion},
+			{ID: free.ID, Titulo: free.Titulo, Type: dto.VideoSuggestion},
+		}, suggestions)
+	})
+
+	t.Run("Suggest method Should only complete with FREE videos When freeOnly is set", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		searchService := services.Search
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Go avançado", Cor: "blue"})
+		free, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go básico", Descricao: "Go", Url: "https://www.example.com"})
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go concorrente", Descricao: "Go", CategoryID: category.ID, Url: "https://www.example.com"})
+
+		suggestions, err := searchService.Suggest("go", true, false, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.Suggestion{{ID: free.ID, Titulo: free.Titulo, Type: dto.VideoSuggestion}}, suggestions)
+	})
+
+	t.Run("Suggest method Should leave out the videos not published When publishedOnly is set", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		searchService := services.Search
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), dto.InsertCategory{Titulo: "Go avançado", Cor: "blue"})
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go rascunho", Descricao: "Go", CategoryID: category.ID, Url: "https://www.example.com", Status: models.DraftStatus})
+		published, _ := videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go publicado", Descricao: "Go", CategoryID: category.ID, Url: "https://www.example.com"})
+
+		suggestions, err := searchService.Suggest("go", false, true, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.Suggestion{
+			{ID: category.ID, Titulo: category.Titulo, Type: dto.CategorySuggestion},
+			{ID: published.ID, Titulo: published.Titulo, Type: dto.VideoSuggestion},
+		}, suggestions)
+	})
+
+	t.Run("Suggest method Should match the wildcards of the prefix literally", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		videoService := services.Videos
+		searchService := services.Search
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), dto.InsertVideo{Titulo: "Go", Descricao: "Go", Url: "https://www.example.com"})
+
+		suggestions, err := searchService.Suggest("%_", false, false, 10)
+
+		assert.Nil(t, err)
+		assert.Empty(t, suggestions)
+	})
+}
@@ -0,0 +1,26 @@
+// Package storage_contract holds the tests every storage backend running in
+// process must pass. Each backend runs them with a Provider building its own
+// services, so the expected behaviour is written once for all of them.
+package storage_contract
+
+import (
+	"testing"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+)
+
+// Services are the services of a backend sharing one database.
+type Services struct {
+	APIKeys    interfaces.IAPIKeyService
+	Audit      interfaces.IAuditService
+	Categories interfaces.ICategoryService
+	Playlists  interfaces.IPlaylistService
+	Search     interfaces.ISearchService
+	Users      interfaces.IUserService
+	Videos     interfaces.IVideoService
+}
+
+// Provider returns the services of a backend over a new and empty database,
+// deleting categories with the policy.
+type Provider func(t *testing.T, policy domain.CategoryDeletePolicy) Services
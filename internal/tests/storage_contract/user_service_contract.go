@@ -0,0 +1,68 @@
+package storage_contract
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserService(t *testing.T, provide Provider) {
+	t.Run("Save method Should create a user seen for the first time", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).Users
+		user := mocked_data.GetValidUser()
+
+		saved, err := service.Save(user)
+		found, findErr := service.GetByID(user.ID)
+
+		assert.Nil(t, err)
+		assert.Equal(t, user, *saved)
+		assert.Nil(t, findErr)
+		assert.Equal(t, saved, found)
+	})
+
+	t.Run("Save method Should refresh the profile and keep the creation When the user exists", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).Users
+		user := mocked_data.GetValidUser()
+		_, _ = service.Save(user)
+		later := user
+		later.Name = "Renamed"
+		later.CreatedAt = user.CreatedAt.Add(time.Hour)
+		later.LastSeenAt = user.LastSeenAt.Add(time.Hour)
+
+		saved, err := service.Save(later)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "Renamed", saved.Name)
+		assert.Equal(t, user.CreatedAt, saved.CreatedAt)
+		assert.Equal(t, later.LastSeenAt, saved.LastSeenAt)
+	})
+
+	t.Run("GetByID method Should return ErrNotFound When the user is unknown", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).Users
+
+		user, err := service.GetByID("unknown")
+
+		assert.Nil(t, user)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("GetAll method Should list a page of the users newest first", func(t *testing.T) {
+		service := provide(t, domain.RejectPolicy).Users
+		first, second := mocked_data.GetValidUser(), mocked_data.GetValidUser()
+		second.ID = "auth0|456"
+		second.CreatedAt = first.CreatedAt.Add(time.Minute)
+		_, _ = service.Save(first)
+		_, _ = service.Save(second)
+
+		users, total, err := service.GetAll(1, 1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, 1, len(users))
+		assert.Equal(t, second.ID, users[0].ID)
+	})
+}
@@ -0,0 +1,776 @@
+package storage_contract
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/domain"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/http/dto"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/interfaces"
+	"github.com/cristovaoolegario/aluraflix-api/internal/pkg/storage/bson/db/models"
+	"github.com/cristovaoolegario/aluraflix-api/internal/tests/mocked_data"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestVideoService(t *testing.T, provide Provider) {
+	t.Run("GetAllFreeVideos method Should return only videos from the free category", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		video := mocked_data.GetValidInsertVideoDto()
+		video.CategoryID = category.ID
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		freeVideo, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := videoService.GetAllFreeVideos()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, freeVideo.ID, response[0].ID)
+	})
+
+	t.Run("GetAllFreeVideos method Should leave out the videos not published", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		draft := mocked_data.GetValidInsertVideoDto()
+		draft.Status = models.DraftStatus
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), draft)
+		published, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := videoService.GetAllFreeVideos()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, published.ID, response[0].ID)
+	})
+
+	t.Run("GetAllVideos method Should filter by status", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		draft := mocked_data.GetValidInsertVideoDto()
+		draft.Status = models.DraftStatus
+		draftVideo, _ := videoService.Create(mocked_data.GetAdminContext(), draft)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, total, err := videoService.GetAll(dto.VideoFilter{Statuses: []string{models.DraftStatus}}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, draftVideo.ID, response[0].ID)
+		assert.Equal(t, models.DraftStatus, response[0].Status)
+	})
+
+	t.Run("PublishDue method Should publish only the scheduled videos whose time has come", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		now := time.Now()
+		due, later := now.Add(-time.Minute), now.Add(time.Hour)
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.PublishAt = &due
+		dueVideo, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		insertVideo.PublishAt = &later
+		laterVideo, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		published, err := videoService.PublishDue(domain.SystemContext(context.TODO(), domain.SchedulerActor), now)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(published))
+		assert.Equal(t, dueVideo.ID, published[0].ID)
+		assert.Equal(t, models.PublishedStatus, published[0].Status)
+		assert.Nil(t, published[0].PublishAt)
+		assert.Equal(t, domain.SchedulerActor, published[0].UpdatedBy)
+		assert.Equal(t, dueVideo.Version+1, published[0].Version)
+		stored, _ := videoService.GetByID(laterVideo.ID)
+		assert.Equal(t, models.ScheduledStatus, stored.Status)
+		assert.True(t, later.Truncate(time.Millisecond).Equal(*stored.PublishAt))
+
+		published, err = videoService.PublishDue(domain.SystemContext(context.TODO(), domain.SchedulerActor), now)
+		assert.Nil(t, err)
+		assert.Empty(t, published)
+	})
+
+	t.Run("PublishDue method Should refuse a context without a principal", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		due := time.Now().Add(-time.Minute)
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.PublishAt = &due
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		published, err := videoService.PublishDue(context.TODO(), time.Now())
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+		assert.Empty(t, published)
+		stored, _ := videoService.GetByID(video.ID)
+		assert.Equal(t, models.ScheduledStatus, stored.Status)
+	})
+
+	t.Run("UpdateVideo method Should keep the stored publication When the update leaves out the status", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.Status = models.DraftStatus
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), video.ID, mocked_data.GetValidInsertVideoDto(), 0)
+		assert.Nil(t, err)
+		assert.Equal(t, models.DraftStatus, response.Status)
+
+		insertVideo.Status = models.PublishedStatus
+		response, err = videoService.Update(mocked_data.GetAdminContext(), video.ID, insertVideo, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, models.PublishedStatus, response.Status)
+	})
+
+	t.Run("PatchVideo method Should set the publication as a whole", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		publishAt := time.Now().Add(time.Hour)
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.PublishAt = &publishAt
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		status := models.DraftStatus
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Status: &status}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, models.DraftStatus, response.Status)
+		assert.Nil(t, response.PublishAt)
+	})
+
+	t.Run("GetAllVideos method Should return objects paginated when has objects", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		for i := 0; i < 7; i++ {
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		}
+
+		firstPage, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 5, len(firstPage))
+
+		secondPage, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 2, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(secondPage))
+
+		emptyPage, total, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 3, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, emptyPage)
+		assert.Equal(t, int64(7), total)
+	})
+
+	t.Run("GetAllVideos method Should combine the category, host, state and creation filters", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		youtube := mocked_data.GetValidInsertVideoDto()
+		youtube.Url = "https://www.YouTube.com/watch?v=1"
+		youtube.CategoryID = category.ID
+		wanted, _ := videoService.Create(mocked_data.GetAdminContext(), youtube)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), youtube)
+		deleted, _ := videoService.Create(mocked_data.GetAdminContext(), youtube)
+		_ = videoService.Delete(mocked_data.GetAdminContext(), deleted.ID, 0)
+		vimeo := mocked_data.GetValidInsertVideoDto()
+		vimeo.Url = "https://vimeo.com/1"
+		vimeo.CategoryID = category.ID
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), vimeo)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		filter := dto.VideoFilter{
+			CategoryIDs:   []primitive.ObjectID{primitive.NewObjectID(), category.ID},
+			Hosts:         []string{"youtube.com"},
+			CreatedFrom:   wanted.ID.Timestamp(),
+			CreatedBefore: time.Now().Add(time.Hour),
+		}
+
+		response, total, err := videoService.GetAll(filter, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, wanted.ID, response[0].ID)
+
+		inactive := false
+		filter.Active = &inactive
+		response, next, err := videoService.GetAllAfter(filter, dto.Sort{}, nil, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, deleted.ID, response[0].ID)
+
+		response, total, err = videoService.GetAll(dto.VideoFilter{CreatedFrom: time.Now().Add(time.Hour)}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("GetFacets method Should count each facet without its own filter", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		free := models.GetFreeCategory().ID.Hex()
+		for _, url := range []string{"https://youtube.com/1", "https://www.youtube.com/2", "https://vimeo.com/3"} {
+			video := mocked_data.GetValidInsertVideoDto()
+			video.Url = url
+			video.CategoryID = category.ID
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		}
+		video := mocked_data.GetValidInsertVideoDto()
+		video.Url = "https://youtube.com/4"
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+
+		facets, err := videoService.GetFacets(dto.VideoFilter{CategoryIDs: []primitive.ObjectID{category.ID}, Hosts: []string{"youtube.com"}})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []dto.FacetCount{{Value: category.ID.Hex(), Count: 2}, {Value: free, Count: 1}}, facets.Categories)
+		assert.Equal(t, []dto.FacetCount{{Value: "youtube.com", Count: 2}, {Value: "vimeo.com", Count: 1}}, facets.Hosts)
+	})
+
+	t.Run("GetFacets method Should return empty facets When no video matches", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		facets, err := videoService.GetFacets(dto.VideoFilter{Search: "nothing"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, &dto.VideoFacets{Categories: []dto.FacetCount{}, Hosts: []dto.FacetCount{}}, facets)
+	})
+
+	t.Run("Update and Patch methods Should keep the url host in sync", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		created, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		url := "https://vimeo.com/1"
+
+		patched, err := videoService.Patch(mocked_data.GetAdminContext(), created.ID, dto.PatchVideo{Url: &url}, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "vimeo.com", patched.UrlHost)
+		facets, _ := videoService.GetFacets(dto.VideoFilter{})
+		assert.Equal(t, []dto.FacetCount{{Value: "vimeo.com", Count: 1}}, facets.Hosts)
+	})
+
+	t.Run("GetAllVideos method with filter Should return only matching objects", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video := mocked_data.GetValidInsertVideoDto()
+		video.Titulo = "Go concurrency"
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, _, err := videoService.GetAll(dto.VideoFilter{Search: "concurrency"}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+		assert.Equal(t, "Go concurrency", response[0].Titulo)
+	})
+
+	t.Run("GetAllVideos method with filter Should ignore case and accents and match metacharacters literally", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video := mocked_data.GetValidInsertVideoDto()
+		video.Titulo = "Introdução à Programação (C++)"
+		created, _ := videoService.Create(mocked_data.GetAdminContext(), video)
+		_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		for _, search := range []string{"programacao", "INTRODUÇÃO A", "(c++)"} {
+			response, total, err := videoService.GetAll(dto.VideoFilter{Search: search}, dto.Sort{}, 1, 5)
+			assert.Nil(t, err)
+			assert.Equal(t, int64(1), total, search)
+			assert.Equal(t, created.ID, response[0].ID)
+		}
+		response, _, err := videoService.GetAll(dto.VideoFilter{Search: ".*"}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("GetAllVideos method with filter Should match the title set by a patch", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		created, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := "Lógica de programação"
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), created.ID, dto.PatchVideo{Titulo: &title}, 0)
+
+		response, _, err := videoService.GetAll(dto.VideoFilter{Search: "logica"}, dto.Sort{}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(response))
+	})
+
+	t.Run("GetAllVideosAfter method Should walk every object once by cursor", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		for i := 0; i < 5; i++ {
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		}
+
+		var seen []primitive.ObjectID
+		var after *dto.Cursor
+		for pages := 1; ; pages++ {
+			response, next, err := videoService.GetAllAfter(dto.VideoFilter{}, dto.Sort{}, after, 2)
+			assert.Nil(t, err)
+			for _, video := range response {
+				seen = append(seen, video.ID)
+			}
+			if next == nil {
+				assert.Equal(t, 3, pages)
+				break
+			}
+			after = next
+		}
+		assert.Equal(t, 5, len(seen))
+		for i := 1; i < len(seen); i++ {
+			assert.True(t, seen[i-1].Hex() < seen[i].Hex())
+		}
+	})
+
+	t.Run("GetAllVideos method Should order by the sort When one is given", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		for _, titulo := range []string{"Beta", "Alpha", "Gamma"} {
+			video := mocked_data.GetValidInsertVideoDto()
+			video.Titulo = titulo
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		}
+
+		byTitle, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{Field: "titulo"}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Alpha", "Beta", "Gamma"}, []string{byTitle[0].Titulo, byTitle[1].Titulo, byTitle[2].Titulo})
+
+		newestFirst, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{Descending: true}, 1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"Gamma", "Alpha", "Beta"}, []string{newestFirst[0].Titulo, newestFirst[1].Titulo, newestFirst[2].Titulo})
+	})
+
+	t.Run("GetAllVideosAfter method Should walk every object once in the sort order", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		for _, titulo := range []string{"B", "A", "B", "C", "B"} {
+			video := mocked_data.GetValidInsertVideoDto()
+			video.Titulo = titulo
+			_, _ = videoService.Create(mocked_data.GetAdminContext(), video)
+		}
+
+		sort := dto.Sort{Field: "titulo", Descending: true}
+		var titles []string
+		seen := map[primitive.ObjectID]bool{}
+		var after *dto.Cursor
+		for {
+			response, next, err := videoService.GetAllAfter(dto.VideoFilter{}, sort, after, 2)
+			assert.Nil(t, err)
+			for _, video := range response {
+				titles = append(titles, video.Titulo)
+				seen[video.ID] = true
+			}
+			if next == nil {
+				break
+			}
+			after = next
+		}
+		assert.Equal(t, []string{"C", "B", "B", "B", "A"}, titles)
+		assert.Equal(t, 5, len(seen))
+	})
+
+	t.Run("GetAllVideosAfter method Should walk every object once by update time", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		var ids []primitive.ObjectID
+		for _, titulo := range []string{"A", "B", "C"} {
+			video := mocked_data.GetValidInsertVideoDto()
+			video.Titulo = titulo
+			created, _ := videoService.Create(mocked_data.GetAdminContext(), video)
+			ids = append(ids, created.ID)
+		}
+		time.Sleep(2 * time.Millisecond)
+		title := "A patched"
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), ids[0], dto.PatchVideo{Titulo: &title}, 0)
+
+		sort := dto.Sort{Field: "updated_at", Descending: true}
+		var titles []string
+		var after *dto.Cursor
+		for {
+			response, next, err := videoService.GetAllAfter(dto.VideoFilter{}, sort, after, 1)
+			assert.Nil(t, err)
+			for _, video := range response {
+				titles = append(titles, video.Titulo)
+			}
+			if next == nil {
+				break
+			}
+			after = next
+		}
+		assert.Equal(t, []string{"A patched", "C", "B"}, titles)
+	})
+
+	t.Run("CreateVideo method Should stamp the audit fields with the token subject", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		video, err := videoService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertVideoDto())
+
+		assert.Nil(t, err)
+		assert.Equal(t, models.NewAudit("auth0|123", video.CreatedAt), video.Audit)
+		assert.WithinDuration(t, time.Now(), video.CreatedAt, time.Minute)
+		response, _ := videoService.GetByID(video.ID)
+		assert.Equal(t, video.Audit, response.Audit)
+	})
+
+	t.Run("PatchVideo method Should stamp the update and keep the creation", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertVideoDto())
+		time.Sleep(2 * time.Millisecond)
+		title := "Patched title"
+
+		response, err := videoService.Patch(mocked_data.GetContextWithPermissions("auth0|456", domain.AdminPermission), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, video.CreatedAt, response.CreatedAt)
+		assert.Equal(t, "auth0|123", response.CreatedBy)
+		assert.True(t, response.UpdatedAt.After(video.UpdatedAt))
+		assert.Equal(t, "auth0|456", response.UpdatedBy)
+	})
+
+	t.Run("DeleteVideo method Should stamp the deletion", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		assert.Nil(t, videoService.Delete(mocked_data.GetContextWithPermissions("auth0|456", domain.AdminPermission), video.ID, 0))
+		deleted, _ := videoService.GetDeleted(1, 5)
+		assert.Equal(t, "auth0|456", deleted[0].UpdatedBy)
+		assert.Equal(t, *deleted[0].DeletedAt, deleted[0].UpdatedAt)
+
+		restored, err := videoService.Restore(mocked_data.GetContextWithPermissions("auth0|789", domain.AdminPermission), video.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|789", restored.UpdatedBy)
+	})
+
+	t.Run("CreateVideo method Should make the token subject the owner of the video", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		video, err := videoService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertVideoDto())
+
+		assert.Nil(t, err)
+		assert.Equal(t, "auth0|123", video.OwnerID)
+		owned, total, _ := videoService.GetAll(dto.VideoFilter{OwnerID: "auth0|123"}, dto.Sort{}, 1, 5)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, video.ID, owned[0].ID)
+		_, total, _ = videoService.GetAll(dto.VideoFilter{OwnerID: "auth0|456"}, dto.Sort{}, 1, 5)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("DeleteVideo method Should return ErrNotOwner When neither the owner nor an admin deletes the video", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetValidInsertVideoDto())
+
+		err := videoService.Delete(mocked_data.GetContextWithSubject("auth0|456"), video.ID, 0)
+
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+		_, err = videoService.GetByID(video.ID)
+		assert.Nil(t, err)
+		assert.Nil(t, videoService.Delete(mocked_data.GetContextWithSubject("auth0|123"), video.ID, 0))
+	})
+
+	t.Run("GetVideoByID method Should return error when object dont exists", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		response, err := videoService.GetByID(primitive.NewObjectID())
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("CreateVideo method Should bootstrap the free category when category is empty", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		response, err := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		assert.Nil(t, err)
+		assert.True(t, response.CategoryID.IsZero())
+
+		stored, err := videoService.GetByID(response.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, response, stored)
+	})
+
+	t.Run("CreateVideo method Should return error when category dont exist", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video := mocked_data.GetValidInsertVideoDto()
+		video.CategoryID = primitive.NewObjectID()
+
+		response, err := videoService.Create(mocked_data.GetAdminContext(), video)
+		assert.Nil(t, response)
+		assert.Equal(t, "Category with id "+video.CategoryID.Hex()+" dont exists.", err.Error())
+	})
+
+	t.Run("UpdateVideo method Should update fields When object exists", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), video.ID, dto.InsertVideo{
+			Titulo:    "New title",
+			Descricao: "New description",
+			Url:       "https://www.new-url.com",
+		}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, video.ID, response.ID)
+		assert.Equal(t, "New title", response.Titulo)
+		assert.Equal(t, "New description", response.Descricao)
+		assert.Equal(t, "https://www.new-url.com", response.Url)
+	})
+
+	t.Run("UpdateVideo method Should return error When object dont exists", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), primitive.NewObjectID(), mocked_data.GetValidInsertVideoDto(), 0)
+		assert.NotNil(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("UpdateVideo method Should return CategoryNotFoundError When category dont exist", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		newData := mocked_data.GetValidInsertVideoDto()
+		newData.CategoryID = primitive.NewObjectID()
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), video.ID, newData, 0)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: newData.CategoryID}, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("UpdateVideo method Should fall back to the free category When category is empty", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		newData := mocked_data.GetValidInsertVideoDto()
+		newData.Titulo = "  New title  "
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), video.ID, newData, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "New title", response.Titulo)
+		assert.Equal(t, categoryService.GetFreeCategory().ID, response.CategoryID)
+	})
+
+	t.Run("PatchVideo method Should change only the fields in the patch", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := " Patched title "
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "Patched title", response.Titulo)
+		assert.Equal(t, video.Descricao, response.Descricao)
+		assert.Equal(t, video.Url, response.Url)
+		assert.Equal(t, video.CategoryID, response.CategoryID)
+	})
+
+	t.Run("PatchVideo method Should return CategoryNotFoundError When category dont exist", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		categoryID := primitive.NewObjectID()
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{CategoryID: &categoryID}, 0)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: categoryID}, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("PatchVideo method Should return error When object dont exists", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		title := "Patched title"
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), primitive.NewObjectID(), dto.PatchVideo{Titulo: &title}, 0)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("UpdateVideo method Should bump the version When the expected version matches", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := videoService.Update(mocked_data.GetAdminContext(), video.ID, mocked_data.GetValidInsertVideoDto(), video.Version)
+		assert.Nil(t, err)
+		assert.Equal(t, video.Version+1, response.Version)
+	})
+
+	t.Run("PatchVideo method Should return ErrVersionMismatch When the expected version is stale", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := "Patched title"
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+
+		response, err := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, video.Version)
+		assert.Equal(t, interfaces.ErrVersionMismatch, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("DeleteVideo method Should keep the item When the expected version is stale", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		assert.Equal(t, interfaces.ErrVersionMismatch, videoService.Delete(mocked_data.GetAdminContext(), video.ID, video.Version+1))
+		_, err := videoService.GetByID(video.ID)
+		assert.Nil(t, err)
+	})
+
+	t.Run("DeleteVideo method Should delete an item When the item can be deleted", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		assert.Nil(t, videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0))
+		assert.Equal(t, interfaces.ErrNotFound, videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0))
+	})
+
+	t.Run("DeleteVideo method Should move the item to the trash", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+
+		_, err := videoService.GetByID(video.ID)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		active, _, _ := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 5)
+		assert.Nil(t, active)
+
+		deleted, err := videoService.GetDeleted(1, 5)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(deleted))
+		assert.False(t, deleted[0].Active)
+		assert.NotNil(t, deleted[0].DeletedAt)
+	})
+
+	t.Run("RestoreVideo method Should bring back a deleted item", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+
+		response, err := videoService.Restore(mocked_data.GetAdminContext(), video.ID)
+		assert.Nil(t, err)
+		assert.True(t, response.Active)
+		assert.Nil(t, response.DeletedAt)
+
+		_, err = videoService.Restore(mocked_data.GetAdminContext(), video.ID)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+	})
+
+	t.Run("RestoreVideo method Should return error When its category is deleted", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+		_ = categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0)
+
+		response, err := videoService.Restore(mocked_data.GetAdminContext(), video.ID)
+		assert.Equal(t, "Category with id "+category.ID.Hex()+" dont exists.", err.Error())
+		assert.Nil(t, response)
+	})
+
+	t.Run("PurgeVideos method Should remove only items deleted before the retention limit", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+
+		purged, err := videoService.Purge(mocked_data.GetAdminContext(), time.Now().Add(-time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = videoService.Purge(mocked_data.GetAdminContext(), time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+		deleted, _ := videoService.GetDeleted(1, 5)
+		assert.Nil(t, deleted)
+	})
+
+	t.Run("PurgeVideos method Should only remove the trash of the token subject unless it grants admin:content", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		alice, bob := mocked_data.GetContextWithSubject("auth0|123"), mocked_data.GetContextWithSubject("auth0|456")
+		video, _ := videoService.Create(alice, mocked_data.GetValidInsertVideoDto())
+		_ = videoService.Delete(alice, video.ID, 0)
+
+		purged, err := videoService.Purge(bob, time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(0), purged)
+		deleted, _ := videoService.GetDeleted(1, 5)
+		assert.Equal(t, 1, len(deleted))
+
+		_, err = videoService.Purge(context.TODO(), time.Now().Add(time.Hour))
+		assert.Equal(t, interfaces.ErrNotOwner, err)
+
+		purged, err = videoService.Purge(alice, time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+	})
+
+	t.Run("GetRevisions method Should list the replaced versions newest first", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := "Patched title"
+		updated, _ := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+
+		revisions, err := videoService.GetRevisions(video.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(revisions))
+		assert.Equal(t, updated.Version, revisions[0].Revision)
+		assert.Equal(t, video.Version, revisions[1].Revision)
+		assert.Equal(t, video.Titulo, revisions[1].Video.Titulo)
+	})
+
+	t.Run("RestoreRevision method Should update the item back to the revision", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := "Patched title"
+		updated, _ := videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+
+		response, err := videoService.RestoreRevision(mocked_data.GetAdminContext(), video.ID, video.Version, updated.Version)
+		assert.Nil(t, err)
+		assert.Equal(t, video.Titulo, response.Titulo)
+		assert.Equal(t, updated.Version+1, response.Version)
+		revisions, _ := videoService.GetRevisions(video.ID)
+		assert.Equal(t, 2, len(revisions))
+		assert.Equal(t, title, revisions[0].Video.Titulo)
+	})
+
+	t.Run("RestoreRevision method Should return CategoryNotFoundError When the category of the revision is deleted", func(t *testing.T) {
+		services := provide(t, domain.RejectPolicy)
+		categoryService := services.Categories
+		videoService := services.Videos
+		category, _ := categoryService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertCategoryDto())
+		insertVideo := mocked_data.GetValidInsertVideoDto()
+		insertVideo.CategoryID = category.ID
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), insertVideo)
+		freeCategoryID := categoryService.GetFreeCategory().ID
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{CategoryID: &freeCategoryID}, 0)
+		_ = categoryService.Delete(mocked_data.GetAdminContext(), category.ID, 0)
+
+		response, err := videoService.RestoreRevision(mocked_data.GetAdminContext(), video.ID, video.Version, 0)
+		assert.Equal(t, interfaces.CategoryNotFoundError{ID: category.ID}, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("RestoreRevision method Should return error When the revision dont exists", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+
+		response, err := videoService.RestoreRevision(mocked_data.GetAdminContext(), video.ID, video.Version, 0)
+		assert.Equal(t, interfaces.ErrNotFound, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("PurgeVideos method Should remove the revisions of the purged items", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		video, _ := videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+		title := "Patched title"
+		_, _ = videoService.Patch(mocked_data.GetAdminContext(), video.ID, dto.PatchVideo{Titulo: &title}, 0)
+		_ = videoService.Delete(mocked_data.GetAdminContext(), video.ID, 0)
+
+		purged, err := videoService.Purge(mocked_data.GetAdminContext(), time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), purged)
+		revisions, err := videoService.GetRevisions(video.ID)
+		assert.Nil(t, err)
+		assert.Empty(t, revisions)
+	})
+
+	t.Run("Should be safe for concurrent use", func(t *testing.T) {
+		videoService := provide(t, domain.RejectPolicy).Videos
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, _ = videoService.Create(mocked_data.GetAdminContext(), mocked_data.GetValidInsertVideoDto())
+			}()
+			go func() {
+				defer wg.Done()
+				_, _, _ = videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 5)
+			}()
+		}
+		wg.Wait()
+
+		response, _, err := videoService.GetAll(dto.VideoFilter{}, dto.Sort{}, 1, 100)
+		assert.Nil(t, err)
+		assert.Equal(t, 50, len(response))
+	})
+}